@@ -0,0 +1,318 @@
+package main
+
+import (
+    "flag"
+    "fmt"
+
+    "github.com/stuartherbert/go_semver/semver"
+)
+
+// parsedVersion is what the 'parse' command writes out as JSON
+type parsedVersion struct {
+    Version    string `json:"version"`
+    Major      int    `json:"major"`
+    Minor      int    `json:"minor"`
+    PatchLevel int    `json:"patchLevel"`
+    Stability  string `json:"stability,omitempty"`
+    Release    int    `json:"release,omitempty"`
+    PreRelease string `json:"preRelease,omitempty"`
+    Build      string `json:"build,omitempty"`
+}
+
+// checkedVersion is what the 'match' and 'validate' commands write out as
+// JSON for each version
+type checkedVersion struct {
+    Version string `json:"version"`
+    Ok      bool   `json:"ok"`
+    Error   string `json:"error,omitempty"`
+}
+
+// runParse shows the parts of a single version string
+func runParse(c *command) int {
+    if len(c.args) != 1 {
+        return c.fail("usage: semver parse <version>")
+    }
+
+    version, err := semver.ParseVersion(c.args[0])
+    if err != nil {
+        return c.fail("%v", err)
+    }
+
+    if c.json {
+        c.writeJSON(parsedVersion{
+            Version:    version.String(),
+            Major:      version.Major,
+            Minor:      version.Minor,
+            PatchLevel: version.PatchLevel,
+            Stability:  version.Stability,
+            Release:    version.Release,
+            PreRelease: version.PreRelease,
+            Build:      version.Build,
+        })
+        return exitOk
+    }
+
+    fmt.Fprintf(c.stdout, "version:    %s\n", version)
+    fmt.Fprintf(c.stdout, "major:      %d\n", version.Major)
+    fmt.Fprintf(c.stdout, "minor:      %d\n", version.Minor)
+    fmt.Fprintf(c.stdout, "patchLevel: %d\n", version.PatchLevel)
+    if version.Stability != "" {
+        fmt.Fprintf(c.stdout, "stability:  %s\n", version.Stability)
+        fmt.Fprintf(c.stdout, "release:    %d\n", version.Release)
+    }
+    if version.PreRelease != "" {
+        fmt.Fprintf(c.stdout, "preRelease: %s\n", version.PreRelease)
+    }
+    if version.Build != "" {
+        fmt.Fprintf(c.stdout, "build:      %s\n", version.Build)
+    }
+    return exitOk
+}
+
+// runCompare prints -1, 0 or 1, the same way that semver.Cmp() does
+//
+// versions that cannot be compared are an error
+func runCompare(c *command) int {
+    if len(c.args) != 2 {
+        return c.fail("usage: semver compare <version> <version>")
+    }
+
+    lhs, err := semver.ParseVersion(c.args[0])
+    if err != nil {
+        return c.fail("%v", err)
+    }
+    rhs, err := semver.ParseVersion(c.args[1])
+    if err != nil {
+        return c.fail("%v", err)
+    }
+    if lhs.Compare(&rhs) == semver.COMP_APPLES_AND_ORANGES {
+        return c.fail("%v: %s and %s", semver.ErrIncomparable, lhs, rhs)
+    }
+
+    result := semver.Cmp(lhs, rhs)
+    if c.json {
+        c.writeJSON(result)
+        return exitOk
+    }
+
+    fmt.Fprintln(c.stdout, result)
+    return exitOk
+}
+
+// runMatch prints the versions that match a constraint
+//
+// exits with exitNoMatch if any of the versions do not match
+func runMatch(c *command) int {
+    if len(c.args) < 1 {
+        return c.fail("usage: semver match <constraint> [version ...]")
+    }
+
+    constraint, err := semver.ParseConstraint(c.args[0])
+    if err != nil {
+        return c.fail("%v", err)
+    }
+    rawVersions, err := c.versionArgs(c.args[1:])
+    if err != nil {
+        return c.fail("%v", err)
+    }
+
+    results := make([]checkedVersion, 0, len(rawVersions))
+    retval := exitOk
+    for _, raw := range rawVersions {
+        result := checkedVersion{Version: raw}
+        result.Ok, err = constraint.Matches(raw)
+        if err != nil {
+            result.Error = err.Error()
+        }
+        if !result.Ok {
+            retval = exitNoMatch
+        }
+        results = append(results, result)
+    }
+
+    if c.json {
+        c.writeJSON(results)
+        return retval
+    }
+
+    for _, result := range results {
+        if result.Ok {
+            fmt.Fprintln(c.stdout, result.Version)
+        }
+    }
+    return retval
+}
+
+// runSort prints the versions, oldest first
+func runSort(c *command) int {
+    versions, code := c.parseVersions(c.args)
+    if code != exitOk {
+        return code
+    }
+
+    versions.Sort()
+    return c.writeVersions(versions)
+}
+
+// runMax prints the newest of the versions, optionally only considering
+// the ones that match a constraint
+//
+// exits with exitNoMatch if there is no such version
+func runMax(c *command) int {
+    flags := flag.NewFlagSet("max", flag.ContinueOnError)
+    flags.SetOutput(c.stderr)
+    match := flags.String("match", "", "only consider versions that match this constraint")
+    if err := flags.Parse(c.args); err != nil {
+        return exitFailure
+    }
+
+    var constraint *semver.Constraint
+    if *match != "" {
+        parsed, err := semver.ParseConstraint(*match)
+        if err != nil {
+            return c.fail("%v", err)
+        }
+        constraint = &parsed
+    }
+
+    versions, code := c.parseVersions(flags.Args())
+    if code != exitOk {
+        return code
+    }
+
+    var candidates semver.Versions
+    for i := range versions {
+        if constraint == nil {
+            candidates = append(candidates, versions[i])
+        } else if ok, _ := constraint.MatchesVersion(&versions[i]); ok {
+            candidates = append(candidates, versions[i])
+        }
+    }
+    if len(candidates) == 0 {
+        fmt.Fprintln(c.stderr, "semver: no matching versions")
+        return exitNoMatch
+    }
+
+    return c.writeVersion(semver.Max(candidates[0], candidates[1:]...))
+}
+
+// runBump prints the version that comes after the one that we are given
+func runBump(c *command) int {
+    usage := "usage: semver bump <major|minor|patch|release|promote|stability <level>> <version>"
+    if len(c.args) < 2 {
+        return c.fail(usage)
+    }
+
+    part, args := c.args[0], c.args[1:]
+    level := ""
+    if part == "stability" {
+        level, args = args[0], args[1:]
+    }
+    if len(args) != 1 {
+        return c.fail(usage)
+    }
+
+    version, err := semver.ParseVersion(args[0])
+    if err != nil {
+        return c.fail("%v", err)
+    }
+
+    switch part {
+    case "major":
+        version = version.NextMajor()
+    case "minor":
+        version = version.NextMinor()
+    case "patch":
+        version = version.NextPatch()
+    case "release":
+        version, err = version.NextRelease()
+    case "promote":
+        version = version.Promote()
+    case "stability":
+        version, err = version.WithStability(level)
+    default:
+        return c.fail("unknown part %q; %s", part, usage)
+    }
+    if err != nil {
+        return c.fail("cannot bump %s: %v", args[0], err)
+    }
+
+    return c.writeVersion(version)
+}
+
+// runValidate checks that each version can be parsed
+//
+// exits with exitNoMatch if any of the versions are not valid
+func runValidate(c *command) int {
+    rawVersions, err := c.versionArgs(c.args)
+    if err != nil {
+        return c.fail("%v", err)
+    }
+
+    results := make([]checkedVersion, 0, len(rawVersions))
+    retval := exitOk
+    for _, raw := range rawVersions {
+        result := checkedVersion{Version: raw, Ok: true}
+        if _, err := semver.ParseVersion(raw); err != nil {
+            result.Ok = false
+            result.Error = err.Error()
+            retval = exitNoMatch
+        }
+        results = append(results, result)
+    }
+
+    if c.json {
+        c.writeJSON(results)
+        return retval
+    }
+
+    for _, result := range results {
+        if !result.Ok {
+            fmt.Fprintln(c.stderr, result.Error)
+        }
+    }
+    return retval
+}
+
+// parseVersions parses the versions given on the command line (or on
+// stdin)
+func (c *command) parseVersions(args []string) (semver.Versions, int) {
+    rawVersions, err := c.versionArgs(args)
+    if err != nil {
+        return nil, c.fail("%v", err)
+    }
+
+    versions, err := semver.ParseVersions(rawVersions)
+    if err != nil {
+        return nil, c.fail("%v", err)
+    }
+
+    return versions, exitOk
+}
+
+// writeVersion writes out a single version
+func (c *command) writeVersion(version semver.SemVersion) int {
+    if c.json {
+        c.writeJSON(version)
+        return exitOk
+    }
+
+    fmt.Fprintln(c.stdout, version)
+    return exitOk
+}
+
+// writeVersions writes out a list of versions, one per line
+func (c *command) writeVersions(versions semver.Versions) int {
+    if c.json {
+        if versions == nil {
+            versions = semver.Versions{}
+        }
+        c.writeJSON(versions)
+        return exitOk
+    }
+
+    for _, version := range versions {
+        fmt.Fprintln(c.stdout, version)
+    }
+    return exitOk
+}
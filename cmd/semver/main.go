@@ -0,0 +1,135 @@
+// Command semver gives shell scripts and CI pipelines access to the
+// semver package's parsing, comparison and matching rules
+//
+// Usage:
+//
+//     semver [-json] <command> [arguments]
+//
+// The commands are:
+//
+//     parse <version>              show the parts of a version string
+//     compare <version> <version>  print -1, 0 or 1
+//     match <constraint> [version ...]
+//                                  print the versions that match
+//     sort [version ...]           print the versions, oldest first
+//     max [-match <constraint>] [version ...]
+//                                  print the newest version
+//     bump <part> <version>        print the next version, where <part>
+//                                  is major, minor, patch, release,
+//                                  promote, or 'stability <level>'
+//     validate [version ...]       check that the versions can be parsed
+//
+// Commands that take a list of versions read them from stdin, one per
+// line, when none are given on the command line.
+//
+// Exit status is 0 on success, 1 if a version does not match (or is not
+// valid), and 2 if the command cannot be carried out at all.
+package main
+
+import (
+    "bufio"
+    "encoding/json"
+    "flag"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+)
+
+// exit codes
+const (
+    exitOk      = 0
+    exitNoMatch = 1
+    exitFailure = 2
+)
+
+// command holds everything a command needs to run
+type command struct {
+    args   []string
+    stdin  io.Reader
+    stdout io.Writer
+    stderr io.Writer
+    json   bool
+}
+
+// the commands that we support
+var commands = map[string]func(*command) int{
+    "parse":    runParse,
+    "compare":  runCompare,
+    "match":    runMatch,
+    "sort":     runSort,
+    "max":      runMax,
+    "bump":     runBump,
+    "validate": runValidate,
+}
+
+func main() {
+    os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run carries out the command in 'args', and returns the exit code
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+    flags := flag.NewFlagSet("semver", flag.ContinueOnError)
+    flags.SetOutput(stderr)
+    useJSON := flags.Bool("json", false, "write the results as JSON")
+    flags.Usage = func() {
+        fmt.Fprintln(stderr, "usage: semver [-json] <parse|compare|match|sort|max|bump|validate> [arguments]")
+        flags.PrintDefaults()
+    }
+    if err := flags.Parse(args); err != nil {
+        return exitFailure
+    }
+    if flags.NArg() == 0 {
+        flags.Usage()
+        return exitFailure
+    }
+
+    runCommand, ok := commands[flags.Arg(0)]
+    if !ok {
+        fmt.Fprintf(stderr, "semver: unknown command %q\n", flags.Arg(0))
+        flags.Usage()
+        return exitFailure
+    }
+
+    return runCommand(&command{
+        args:   flags.Args()[1:],
+        stdin:  stdin,
+        stdout: stdout,
+        stderr: stderr,
+        json:   *useJSON,
+    })
+}
+
+// fail reports an error, and returns the exit code for it
+func (c *command) fail(format string, args ...interface{}) int {
+    fmt.Fprintf(c.stderr, "semver: "+format+"\n", args...)
+    return exitFailure
+}
+
+// writeJSON writes 'result' to stdout as a single line of JSON
+func (c *command) writeJSON(result interface{}) {
+    encoder := json.NewEncoder(c.stdout)
+    encoder.SetEscapeHTML(false)
+    encoder.Encode(result)
+}
+
+// versionArgs returns the versions given on the command line, or read
+// from stdin if there aren't any
+//
+// blank lines are skipped
+func (c *command) versionArgs(args []string) ([]string, error) {
+    if len(args) > 0 {
+        return args, nil
+    }
+
+    var retval []string
+    scanner := bufio.NewScanner(c.stdin)
+    for scanner.Scan() {
+        line := strings.TrimSpace(scanner.Text())
+        if line != "" {
+            retval = append(retval, line)
+        }
+    }
+
+    return retval, scanner.Err()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+    "bytes"
+    "strings"
+    "testing"
+)
+
+// ========================================================================
+//
+// Tests for run()
+//
+// ------------------------------------------------------------------------
+
+func TestCommands(t *testing.T) {
+    // our list of command lines, and what we expect them to do
+    var toRun = []struct {
+        args     []string
+        stdin    string
+        expected string
+        exitCode int
+    }{
+        {[]string{"parse", "1.3-rc-1+b5"}, "", "version:    1.3.0-rc-1+b5\nmajor:      1\nminor:      3\npatchLevel: 0\nstability:  rc\nrelease:    1\nbuild:      b5\n", exitOk},
+        {[]string{"-json", "parse", "2.0.0-beta.2"}, "", `{"version":"2.0.0-beta.2","major":2,"minor":0,"patchLevel":0,"preRelease":"beta.2"}` + "\n", exitOk},
+        {[]string{"parse", "v1.3"}, "", "", exitFailure},
+        {[]string{"compare", "1.3", "1.10"}, "", "-1\n", exitOk},
+        {[]string{"compare", "1.3.0", "1.3"}, "", "0\n", exitOk},
+        {[]string{"compare", "1.3-alpha-1", "1.3-beta-1"}, "", "", exitFailure},
+        {[]string{"match", "~1.3 || >=2.0", "1.3.5", "2.1.0"}, "", "1.3.5\n2.1.0\n", exitOk},
+        {[]string{"match", ">=1.3", "1.3.5", "1.2.0"}, "", "1.3.5\n", exitNoMatch},
+        {[]string{"match", ">=1.3"}, "1.3.5\n\n1.4\n", "1.3.5\n1.4\n", exitOk},
+        {[]string{"-json", "match", ">=1.3", "1.4", "1.2"}, "", `[{"version":"1.4","ok":true},{"version":"1.2","ok":false,"error":"alternative 1, clause 1: 1.2.0 does not satisfy >=1.3.0: minor version 2 is below 3"}]` + "\n", exitNoMatch},
+        {[]string{"match", "~1.x.3", "1.3.5"}, "", "", exitFailure},
+        {[]string{"sort"}, "1.10.0\n1.9.0\n2.0.0-rc.1\n2.0.0\n", "1.9.0\n1.10.0\n2.0.0-rc.1\n2.0.0\n", exitOk},
+        {[]string{"-json", "sort", "1.10", "1.9"}, "", `["1.9.0","1.10.0"]` + "\n", exitOk},
+        {[]string{"max", "1.3", "1.10", "1.9"}, "", "1.10.0\n", exitOk},
+        {[]string{"max", "-match", "<1.10", "1.3", "1.10", "1.9"}, "", "1.9.0\n", exitOk},
+        {[]string{"max", "-match", ">=3.0", "1.3", "1.10"}, "", "", exitNoMatch},
+        {[]string{"bump", "major", "1.3.5"}, "", "2.0.0\n", exitOk},
+        {[]string{"bump", "minor", "1.3.5"}, "", "1.4.0\n", exitOk},
+        {[]string{"bump", "patch", "1.3.5"}, "", "1.3.6\n", exitOk},
+        {[]string{"bump", "stability", "rc", "1.3.5"}, "", "1.3.5-rc-1\n", exitOk},
+        {[]string{"-json", "bump", "release", "1.3.5-rc.1"}, "", `"1.3.5-rc.2"` + "\n", exitOk},
+        {[]string{"bump", "promote", "1.3.5-rc-2"}, "", "1.3.5\n", exitOk},
+        {[]string{"bump", "release", "1.3.5"}, "", "", exitFailure},
+        {[]string{"bump", "mnior", "1.3.5"}, "", "", exitFailure},
+        {[]string{"validate", "1.3", "1.3.0-rc.1"}, "", "", exitOk},
+        {[]string{"validate"}, "1.3\nv1.3\n", "", exitNoMatch},
+        {[]string{"-json", "validate", "1.3"}, "", `[{"version":"1.3","ok":true}]` + "\n", exitOk},
+        {[]string{}, "", "", exitFailure},
+        {[]string{"frobnicate"}, "", "", exitFailure},
+    }
+
+    for _, set := range toRun {
+        // perform the test
+        var stdout, stderr bytes.Buffer
+        actual := run(set.args, strings.NewReader(set.stdin), &stdout, &stderr)
+
+        // did we get back what we expected?
+        if actual != set.exitCode {
+            t.Errorf("%v: expected exit code %d, received %d (stderr: %s)", set.args, set.exitCode, actual, stderr.String())
+            return
+        }
+        if stdout.String() != set.expected {
+            t.Errorf("%v: expected output %q, received %q", set.args, set.expected, stdout.String())
+            return
+        }
+    }
+}
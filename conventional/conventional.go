@@ -0,0 +1,136 @@
+// Package conventional works out the next version number from commit
+// messages that follow the Conventional Commits specification
+// (https://www.conventionalcommits.org/en/v1.0.0/)
+//
+// Each commit message is classified like this:
+//
+//     fix: ...                      : bumps the patch level
+//     feat: ...                     : bumps the minor version number
+//     feat!: ..., fix(api)!: ...    : bumps the major version number
+//     'BREAKING CHANGE: ...' footer : bumps the major version number
+//     anything else                 : does not change the version
+//
+// While the major version number is 0, breaking changes bump the minor
+// version number instead, because SemVer says that anything can change
+// before 1.0.0.
+//
+// To find the next version of a local git repository:
+//
+//     repo, err := gitver.Open(".")
+//     report, err := conventional.RecommendFromRepo(repo)
+//     fmt.Println(report.Next)
+package conventional
+
+import (
+    "strings"
+)
+
+// values for Message.Bump() and Report.Bump
+const (
+    BUMP_NONE  = 0
+    BUMP_PATCH = 1
+    BUMP_MINOR = 2
+    BUMP_MAJOR = 3
+)
+
+// what we call each of the BUMP_* values
+var bumpNames = []string{"none", "patch", "minor", "major"}
+
+// BumpName returns a human-readable name for one of the BUMP_* values
+func BumpName(bump int) string {
+    if bump < 0 || bump >= len(bumpNames) {
+        return "unknown"
+    }
+
+    return bumpNames[bump]
+}
+
+// Message holds a parsed Conventional Commits message
+//
+// the first line of the message has the form:
+//
+//     <type>[(<scope>)][!]: <description>
+type Message struct {
+    Type         string // e.g. 'feat' or 'fix', always lower case
+    Scope        string // the optional scope, without the brackets
+    Description  string // the rest of the first line
+    Breaking     bool   // true if this is a breaking change
+    BreakingNote string // the text of the BREAKING CHANGE footer, if there is one
+}
+
+// ParseMessage parses a commit message
+//
+// returns false if the message does not follow the Conventional Commits
+// specification
+func ParseMessage(message string) (Message, bool) {
+    header, body, _ := strings.Cut(message, "\n")
+    header = strings.TrimRight(header, "\r")
+
+    var retval Message
+
+    // the type is a single word
+    pos := 0
+    for pos < len(header) && isTypeChar(header[pos]) {
+        pos++
+    }
+    if pos == 0 {
+        return Message{}, false
+    }
+    retval.Type = strings.ToLower(header[:pos])
+
+    // the scope is optional
+    if pos < len(header) && header[pos] == '(' {
+        end := strings.IndexByte(header[pos:], ')')
+        if end < 2 {
+            return Message{}, false
+        }
+        retval.Scope = header[pos+1 : pos+end]
+        pos += end + 1
+    }
+
+    // so is the '!' that marks a breaking change
+    if pos < len(header) && header[pos] == '!' {
+        retval.Breaking = true
+        pos++
+    }
+
+    if !strings.HasPrefix(header[pos:], ": ") {
+        return Message{}, false
+    }
+    retval.Description = strings.TrimSpace(header[pos+2:])
+    if retval.Description == "" {
+        return Message{}, false
+    }
+
+    // breaking changes can also be described in a footer
+    for _, line := range strings.Split(body, "\n") {
+        for _, token := range []string{"BREAKING CHANGE: ", "BREAKING-CHANGE: "} {
+            if strings.HasPrefix(line, token) {
+                retval.Breaking = true
+                retval.BreakingNote = strings.TrimSpace(strings.TrimPrefix(line, token))
+            }
+        }
+    }
+
+    return retval, true
+}
+
+// isTypeChar returns true if 'c' can appear in a commit type
+func isTypeChar(c byte) bool {
+    return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// Bump returns which part of the version number this commit needs to
+// change, as one of the BUMP_* values
+func (m *Message) Bump() int {
+    switch {
+    case m.Breaking:
+        return BUMP_MAJOR
+    case m.Type == "feat":
+        return BUMP_MINOR
+    case m.Type == "fix":
+        return BUMP_PATCH
+    }
+
+    return BUMP_NONE
+}
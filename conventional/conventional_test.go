@@ -0,0 +1,81 @@
+package conventional
+
+import (
+    "testing"
+)
+
+// ========================================================================
+//
+// Tests for ParseMessage() and Bump()
+//
+// ------------------------------------------------------------------------
+
+func TestCanParseConventionalMessages(t *testing.T) {
+    // our list of messages, and what we expect them to become
+    var toParse = []struct {
+        message  string
+        expected Message
+        bump     int
+    }{
+        {"fix: handle empty tags", Message{Type: "fix", Description: "handle empty tags"}, BUMP_PATCH},
+        {"feat(parser): accept X-ranges\n\nwith a body", Message{Type: "feat", Scope: "parser", Description: "accept X-ranges"}, BUMP_MINOR},
+        {"Feat: upper case type\r\n", Message{Type: "feat", Description: "upper case type"}, BUMP_MINOR},
+        {"refactor!: drop Go 1.17", Message{Type: "refactor", Description: "drop Go 1.17", Breaking: true}, BUMP_MAJOR},
+        {"fix(api)!: rename Compare", Message{Type: "fix", Scope: "api", Description: "rename Compare", Breaking: true}, BUMP_MAJOR},
+        {"docs: explain tilde", Message{Type: "docs", Description: "explain tilde"}, BUMP_NONE},
+        {
+            "feat: new operators\n\nsome text\n\nBREAKING CHANGE: '~' now means something else\nRefs: #12",
+            Message{Type: "feat", Description: "new operators", Breaking: true, BreakingNote: "'~' now means something else"},
+            BUMP_MAJOR,
+        },
+        {
+            "chore: tidy up\n\nBREAKING-CHANGE: removed Foo",
+            Message{Type: "chore", Description: "tidy up", Breaking: true, BreakingNote: "removed Foo"},
+            BUMP_MAJOR,
+        },
+    }
+
+    for _, set := range toParse {
+        // perform the test
+        actual, ok := ParseMessage(set.message)
+
+        // did we get back what we expected?
+        if !ok {
+            t.Errorf("%q: expected a conventional message", set.message)
+            return
+        }
+        if actual != set.expected {
+            t.Errorf("%q: expected %+v, received %+v", set.message, set.expected, actual)
+            return
+        }
+        if actual.Bump() != set.bump {
+            t.Errorf("%q: expected bump %s, received %s", set.message, BumpName(set.bump), BumpName(actual.Bump()))
+            return
+        }
+    }
+}
+
+func TestParseMessageRejectsOtherMessages(t *testing.T) {
+    // our list of messages that do not follow the spec
+    var toParse = []string{
+        "",
+        "Merge branch 'side'",
+        "fix:no space",
+        "fix: ",
+        "fix(): empty scope",
+        "fix(api: unclosed scope",
+        "1.3.0: release",
+        "BREAKING CHANGE: this is not a footer",
+    }
+
+    for _, message := range toParse {
+        // perform the test
+        _, ok := ParseMessage(message)
+
+        // did we get back what we expected?
+        if ok {
+            t.Errorf("%q: expected ParseMessage() to fail", message)
+            return
+        }
+    }
+}
@@ -0,0 +1,153 @@
+package conventional
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/stuartherbert/go_semver/gitver"
+    "github.com/stuartherbert/go_semver/semver"
+)
+
+// ClassifiedCommit is a commit, and what it means for the next version
+type ClassifiedCommit struct {
+    Commit       gitver.Commit
+    Message      Message // only set if Conventional is true
+    Conventional bool    // false if the message does not follow the spec
+    Bump         int     // one of the BUMP_* values
+}
+
+// Report explains how the next version was worked out
+type Report struct {
+    PreviousTag string             // the tag we started from; empty if there isn't one
+    Previous    semver.SemVersion  // the version we started from
+    Next        semver.SemVersion  // the recommended next version
+    Bump        int                // which part of the version changed, one of the BUMP_* values
+    Commits     []ClassifiedCommit // every commit since PreviousTag, newest first
+}
+
+// Recommend works out the next version after 'previous', from the commits
+// that have been made since
+//
+// if 'previous' is an unstable release, such as 2.0.0-rc-1, the commits
+// normally lead to its stable release (2.0.0). We only go further if they
+// need a larger bump than the unstable release was already heading for.
+func Recommend(previous semver.SemVersion, commits []gitver.Commit) Report {
+    report := Report{Previous: previous, Next: previous}
+
+    for _, commit := range commits {
+        classified := ClassifiedCommit{Commit: commit}
+        classified.Message, classified.Conventional = ParseMessage(commit.Message)
+        if classified.Conventional {
+            classified.Bump = classified.Message.Bump()
+        }
+
+        // anything can change before 1.0.0, so breaking changes
+        // only bump the minor version number
+        if classified.Bump == BUMP_MAJOR && previous.Major == 0 {
+            classified.Bump = BUMP_MINOR
+        }
+
+        if classified.Bump > report.Bump {
+            report.Bump = classified.Bump
+        }
+        report.Commits = append(report.Commits, classified)
+    }
+
+    base := previous
+    if previous.Stability != "" || previous.PreRelease != "" {
+        base = previous.Promote()
+        if report.Bump != BUMP_NONE && report.Bump <= preReleaseBump(previous) {
+            report.Next = base
+            return report
+        }
+    }
+
+    switch report.Bump {
+    case BUMP_MAJOR:
+        report.Next = base.NextMajor()
+    case BUMP_MINOR:
+        report.Next = base.NextMinor()
+    case BUMP_PATCH:
+        report.Next = base.NextPatch()
+    }
+
+    return report
+}
+
+// preReleaseBump returns the bump that an unstable release is already
+// heading for, e.g. 2.0.0-rc-1 is the start of a new major version
+func preReleaseBump(v semver.SemVersion) int {
+    switch {
+    case v.PatchLevel != 0:
+        return BUMP_PATCH
+    case v.Minor != 0 || v.Major == 0:
+        return BUMP_MINOR
+    }
+
+    return BUMP_MAJOR
+}
+
+// RecommendFromRepo works out the next version of a local git repository,
+// from the commits made since the latest version tag that HEAD was built
+// on
+//
+// tags on other branches are ignored, so that a maintenance branch is
+// compared against its own releases
+//
+// if HEAD has no tagged ancestors, we start from 0.0.0 and look at every
+// commit
+func RecommendFromRepo(repo *gitver.Repo) (Report, error) {
+    tag, _, err := repo.LatestReachable()
+    if err != nil {
+        return Report{}, err
+    }
+
+    commits, err := repo.CommitsSince(tag.Commit)
+    if err != nil {
+        return Report{}, err
+    }
+
+    report := Recommend(tag.Version, commits)
+    report.PreviousTag = tag.Name
+
+    return report, nil
+}
+
+// Drivers returns the commits that decided what the next version is
+//
+// returns an empty list if none of the commits change the version
+func (r *Report) Drivers() []ClassifiedCommit {
+    var retval []ClassifiedCommit
+    if r.Bump == BUMP_NONE {
+        return retval
+    }
+
+    for _, commit := range r.Commits {
+        if commit.Bump == r.Bump {
+            retval = append(retval, commit)
+        }
+    }
+
+    return retval
+}
+
+// String explains the decision, listing the commits that drove it
+func (r Report) String() string {
+    var buf strings.Builder
+
+    from := r.Previous.String()
+    if r.PreviousTag != "" {
+        from = r.PreviousTag
+    }
+    fmt.Fprintf(&buf, "%s -> %s (%s, %d commits)\n", from, r.Next, BumpName(r.Bump), len(r.Commits))
+
+    for _, commit := range r.Drivers() {
+        id := commit.Commit.ID
+        if len(id) > 7 {
+            id = id[:7]
+        }
+        fmt.Fprintf(&buf, "    %s %s\n", id, commit.Commit.Subject())
+    }
+
+    return buf.String()
+}
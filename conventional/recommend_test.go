@@ -0,0 +1,236 @@
+package conventional
+
+import (
+    "bytes"
+    "compress/zlib"
+    "crypto/sha1"
+    "encoding/hex"
+    "fmt"
+    "os"
+    "path/filepath"
+    "testing"
+
+    "github.com/stuartherbert/go_semver/gitver"
+    "github.com/stuartherbert/go_semver/semver"
+)
+
+// makeCommits turns a list of messages into commits, newest first
+func makeCommits(messages ...string) []gitver.Commit {
+    var retval []gitver.Commit
+    for i, message := range messages {
+        retval = append(retval, gitver.Commit{ID: fmt.Sprintf("%040d", i+1), Message: message})
+    }
+
+    return retval
+}
+
+// ========================================================================
+//
+// Tests for Recommend()
+//
+// ------------------------------------------------------------------------
+
+func TestCanRecommendNextVersion(t *testing.T) {
+    // our list of previous versions and commits, and what we expect
+    var toRecommend = []struct {
+        previous string
+        messages []string
+        next     string
+        bump     int
+        drivers  int
+    }{
+        {"1.3.5", []string{"fix: one", "docs: two", "fix: three"}, "1.3.6", BUMP_PATCH, 2},
+        {"1.3.5", []string{"fix: one", "feat: two", "chore: three"}, "1.4.0", BUMP_MINOR, 1},
+        {"1.3.5", []string{"feat!: one", "fix: two\n\nBREAKING CHANGE: three"}, "2.0.0", BUMP_MAJOR, 2},
+        {"0.3.5", []string{"feat!: one", "fix: two"}, "0.4.0", BUMP_MINOR, 1},
+        {"1.3.5", []string{"docs: one", "Merge branch 'two'"}, "1.3.5", BUMP_NONE, 0},
+    }
+
+    for _, set := range toRecommend {
+        previous, _ := semver.ParseVersion(set.previous)
+
+        // perform the test
+        actual := Recommend(previous, makeCommits(set.messages...))
+
+        // did we get back what we expected?
+        if actual.Next.String() != set.next || actual.Bump != set.bump {
+            t.Errorf("%s %v: expected %s (%s), received %s (%s)", set.previous, set.messages, set.next, BumpName(set.bump), actual.Next, BumpName(actual.Bump))
+            return
+        }
+        if len(actual.Commits) != len(set.messages) {
+            t.Errorf("%s %v: expected %d commits, received %d", set.previous, set.messages, len(set.messages), len(actual.Commits))
+            return
+        }
+        if len(actual.Drivers()) != set.drivers {
+            t.Errorf("%s %v: expected %d drivers, received %+v", set.previous, set.messages, set.drivers, actual.Drivers())
+            return
+        }
+    }
+}
+
+func TestRecommendPromotesPreReleases(t *testing.T) {
+    // our list of unstable previous versions and commits, and what we
+    // expect
+    var toRecommend = []struct {
+        previous string
+        messages []string
+        next     string
+        bump     int
+    }{
+        {"2.0.0-rc-1", []string{"feat: one", "fix: two"}, "2.0.0", BUMP_MINOR},
+        {"2.0.0-rc.1", []string{"feat!: one"}, "2.0.0", BUMP_MAJOR},
+        {"1.3.0-rc-2", []string{"fix: one"}, "1.3.0", BUMP_PATCH},
+        {"1.3.0-beta.2", []string{"feat: one"}, "1.3.0", BUMP_MINOR},
+        {"1.3.0-beta-2", []string{"feat!: one"}, "2.0.0", BUMP_MAJOR},
+        {"1.3.1-alpha.1", []string{"fix: one"}, "1.3.1", BUMP_PATCH},
+        {"1.3.1-rc-1", []string{"feat: one"}, "1.4.0", BUMP_MINOR},
+        {"0.4.0-rc-1", []string{"feat!: one"}, "0.4.0", BUMP_MINOR},
+        {"1.3.0-rc-2", []string{"docs: one"}, "1.3.0-rc-2", BUMP_NONE},
+    }
+
+    for _, set := range toRecommend {
+        previous, err := semver.ParseVersion(set.previous)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // perform the test
+        actual := Recommend(previous, makeCommits(set.messages...))
+
+        // did we get back what we expected?
+        if actual.Next.String() != set.next || actual.Bump != set.bump {
+            t.Errorf("%s %v: expected %s (%s), received %s (%s)", set.previous, set.messages, set.next, BumpName(set.bump), actual.Next, BumpName(actual.Bump))
+            return
+        }
+    }
+}
+
+func TestReportExplainsDecision(t *testing.T) {
+    // what result do we expect?
+    expected := "v1.3.5 -> 1.4.0 (minor, 3 commits)\n" +
+        "    0000000 feat: two\n"
+
+    // perform the test
+    report := Recommend(semver.SemVersion{Major: 1, Minor: 3, PatchLevel: 5}, makeCommits("fix: one", "feat: two\n\nbody", "docs: three"))
+    report.PreviousTag = "v1.3.5"
+    actual := report.String()
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %q, received %q", expected, actual)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for RecommendFromRepo()
+//
+// ------------------------------------------------------------------------
+
+// writeCommit adds a loose commit object to our example repository
+func writeCommit(t *testing.T, gitDir string, message string, parents ...string) string {
+    body := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n"
+    for _, parent := range parents {
+        body += "parent " + parent + "\n"
+    }
+    body += "author A <a@example.com> 1700000000 +0000\ncommitter A <a@example.com> 1700000000 +0000\n\n" + message
+    raw := fmt.Sprintf("commit %d\x00%s", len(body), body)
+    sum := sha1.Sum([]byte(raw))
+    id := hex.EncodeToString(sum[:])
+
+    var buf bytes.Buffer
+    writer := zlib.NewWriter(&buf)
+    writer.Write([]byte(raw))
+    writer.Close()
+    writeFile(t, gitDir, "objects/"+id[:2]+"/"+id[2:], buf.String())
+
+    return id
+}
+
+// writeFile creates a file in our example repository
+func writeFile(t *testing.T, dir string, name string, contents string) {
+    path := filepath.Join(dir, filepath.FromSlash(name))
+    if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestCanRecommendFromRepo(t *testing.T) {
+    dir := t.TempDir()
+    gitDir := filepath.Join(dir, ".git")
+    first := writeCommit(t, gitDir, "feat: first\n")
+    tagged := writeCommit(t, gitDir, "fix: tagged\n", first)
+    second := writeCommit(t, gitDir, "fix: after the tag\n", tagged)
+    head := writeCommit(t, gitDir, "feat(cli): add bump command\n", second)
+    writeFile(t, gitDir, "HEAD", "ref: refs/heads/main\n")
+    writeFile(t, gitDir, "refs/heads/main", head+"\n")
+    writeFile(t, gitDir, "refs/tags/v1.3.5", tagged+"\n")
+
+    // perform the test
+    repo, _ := gitver.Open(dir)
+    actual, err := RecommendFromRepo(repo)
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual.PreviousTag != "v1.3.5" || actual.Next.String() != "1.4.0" || len(actual.Commits) != 2 {
+        t.Errorf("Expected v1.3.5 -> 1.4.0 from 2 commits, received %s", actual)
+        return
+    }
+    drivers := actual.Drivers()
+    if len(drivers) != 1 || drivers[0].Commit.ID != head {
+        t.Errorf("Expected commit %s to drive the decision, received %+v", head, drivers)
+        return
+    }
+
+    // without any tags, we start from 0.0.0 and use every commit
+    os.Remove(filepath.Join(gitDir, "refs", "tags", "v1.3.5"))
+    actual, err = RecommendFromRepo(repo)
+    if err != nil || actual.PreviousTag != "" || actual.Next.String() != "0.1.0" || len(actual.Commits) != 4 {
+        t.Errorf("Expected 0.0.0 -> 0.1.0 from 4 commits, received %s, %v", actual, err)
+        return
+    }
+}
+
+func TestRecommendFromRepoIgnoresTagsOnOtherBranches(t *testing.T) {
+    // main:       base (v1.1.0) -- feature -- fix (v1.2.0)
+    // maint-1.1:  base (v1.1.0) -- fix: patched (v1.1.1) -- fix: backport (HEAD)
+    dir := t.TempDir()
+    gitDir := filepath.Join(dir, ".git")
+    base := writeCommit(t, gitDir, "feat: base\n")
+    feature := writeCommit(t, gitDir, "feat: new feature\n", base)
+    released := writeCommit(t, gitDir, "fix: backport\n", feature)
+    patched := writeCommit(t, gitDir, "fix: patched\n", base)
+    head := writeCommit(t, gitDir, "fix: backport\n\nfrom main\n", patched)
+    writeFile(t, gitDir, "HEAD", "ref: refs/heads/maint-1.1\n")
+    writeFile(t, gitDir, "refs/heads/main", released+"\n")
+    writeFile(t, gitDir, "refs/heads/maint-1.1", head+"\n")
+    writeFile(t, gitDir, "refs/tags/v1.1.0", base+"\n")
+    writeFile(t, gitDir, "refs/tags/v1.1.1", patched+"\n")
+    writeFile(t, gitDir, "refs/tags/v1.2.0", released+"\n")
+
+    // perform the test
+    repo, _ := gitver.Open(dir)
+    actual, err := RecommendFromRepo(repo)
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual.PreviousTag != "v1.1.1" || actual.Next.String() != "1.1.2" || len(actual.Commits) != 1 || actual.Commits[0].Commit.ID != head {
+        t.Errorf("Expected v1.1.1 -> 1.1.2 from commit %s, received %s", head, actual)
+        return
+    }
+}
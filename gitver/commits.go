@@ -0,0 +1,131 @@
+package gitver
+
+import (
+    "fmt"
+    "strings"
+)
+
+// Commit is a single commit in the repository
+type Commit struct {
+    ID      string   // the commit's object ID
+    Parents []string // the object IDs of its parents
+    Message string   // the full commit message
+}
+
+// Subject returns the first line of the commit message
+func (c *Commit) Subject() string {
+    subject, _, _ := strings.Cut(c.Message, "\n")
+    return subject
+}
+
+// Commit reads a single commit from the repository
+//
+// returns ErrUnknownObject if the commit cannot be found
+func (r *Repo) Commit(id string) (Commit, error) {
+    kind, body, err := r.readObject(id)
+    if err != nil {
+        return Commit{}, err
+    }
+    if kind != "commit" {
+        return Commit{}, fmt.Errorf("%w: %s is a %s, not a commit", ErrUnknownObject, id, kind)
+    }
+
+    commit := Commit{ID: id}
+
+    // the headers end at the first blank line; the message follows
+    headers, message, _ := strings.Cut(string(body), "\n\n")
+    for _, line := range strings.Split(headers, "\n") {
+        if strings.HasPrefix(line, "parent ") {
+            commit.Parents = append(commit.Parents, strings.TrimPrefix(line, "parent "))
+        }
+    }
+    commit.Message = message
+
+    return commit, nil
+}
+
+// CommitsSince returns the commits that are reachable from HEAD, but not
+// from 'since' (the same commits as 'git log since..HEAD')
+//
+// the commits are returned in breadth-first order, starting with HEAD
+//
+// if 'since' is empty, every commit that is reachable from HEAD is
+// returned
+func (r *Repo) CommitsSince(since string) ([]Commit, error) {
+    head, err := r.Head()
+    if err != nil {
+        return nil, err
+    }
+
+    // everything that is already in 'since' is excluded
+    excluded := map[string]bool{}
+    if since != "" {
+        if _, err := r.walk(since, excluded, false); err != nil {
+            return nil, err
+        }
+    }
+
+    return r.walk(head, excluded, true)
+}
+
+// LatestReachable returns the tag with the newest version, out of the
+// tags that point at HEAD or at one of its ancestors
+//
+// this is the release that HEAD was built on. On a maintenance branch,
+// it is that branch's latest release, even if there are newer releases
+// on other branches.
+//
+// returns false if none of the version tags are reachable from HEAD
+func (r *Repo) LatestReachable() (Tag, bool, error) {
+    tags, err := r.Tags()
+    if err != nil || len(tags) == 0 {
+        return Tag{}, false, err
+    }
+
+    head, err := r.Head()
+    if err != nil {
+        return Tag{}, false, err
+    }
+    reachable := map[string]bool{}
+    if _, err := r.walk(head, reachable, false); err != nil {
+        return Tag{}, false, err
+    }
+
+    for i := len(tags) - 1; i >= 0; i-- {
+        if reachable[tags[i].Commit] {
+            return tags[i], true, nil
+        }
+    }
+
+    return Tag{}, false, nil
+}
+
+// walk visits every commit that is reachable from 'start', skipping the
+// ones that are already in 'seen'
+//
+// each commit that we visit is added to 'seen'. If 'collect' is set, the
+// commits are returned too, in the order that we visited them
+func (r *Repo) walk(start string, seen map[string]bool, collect bool) ([]Commit, error) {
+    var retval []Commit
+
+    queue := []string{start}
+    for len(queue) > 0 {
+        id := queue[0]
+        queue = queue[1:]
+        if seen[id] {
+            continue
+        }
+        seen[id] = true
+
+        commit, err := r.Commit(id)
+        if err != nil {
+            return nil, err
+        }
+        if collect {
+            retval = append(retval, commit)
+        }
+        queue = append(queue, commit.Parents...)
+    }
+
+    return retval, nil
+}
@@ -0,0 +1,177 @@
+package gitver
+
+import (
+    "errors"
+    "fmt"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+// commitBody builds the contents of a commit object
+func commitBody(message string, parents ...string) string {
+    body := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n"
+    for _, parent := range parents {
+        body += "parent " + parent + "\n"
+    }
+
+    return body + "author A <a@example.com> 1700000000 +0000\ncommitter A <a@example.com> 1700000000 +0000\n\n" + message
+}
+
+// makeHistoryRepo creates a repository with this history, where commits
+// 1 to 3 are packed, and the rest are loose objects:
+//
+//     1 -- 2 -- 3 (v1.0.0) -- 4 -------- 6 (HEAD)
+//                \                      /
+//                 ------------ 5 ------
+func makeHistoryRepo(t *testing.T) (string, []string) {
+    dir := t.TempDir()
+    gitDir := filepath.Join(dir, ".git")
+
+    one := commitBody("one\n")
+    two := commitBody("two\n", objectID("commit", one))
+    three := commitBody("three\n\nwith a body\n", objectID("commit", two))
+    ids := writePack(t, gitDir, "commit", []string{one, two, three})
+
+    for _, commit := range []struct {
+        message string
+        parents []int
+    }{
+        {"four\n", []int{2}},
+        {"five\n", []int{2}},
+        {"six\n", []int{3, 4}},
+    } {
+        var parents []string
+        for _, parent := range commit.parents {
+            parents = append(parents, ids[parent])
+        }
+        body := commitBody(commit.message, parents...)
+        id := objectID("commit", body)
+        writeLooseObject(t, gitDir, id, "commit", body)
+        ids = append(ids, id)
+    }
+
+    writeFile(t, gitDir, "HEAD", "ref: refs/heads/main\n")
+    writeFile(t, gitDir, "refs/heads/main", ids[5]+"\n")
+    writeFile(t, gitDir, "refs/tags/v1.0.0", ids[2]+"\n")
+
+    return dir, ids
+}
+
+// ========================================================================
+//
+// Tests for Commit() and CommitsSince()
+//
+// ------------------------------------------------------------------------
+
+func TestCanReadPackedCommits(t *testing.T) {
+    dir, ids := makeHistoryRepo(t)
+    repo, _ := Open(dir)
+
+    // perform the test
+    actual, err := repo.Commit(ids[2])
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual.ID != ids[2] || actual.Message != "three\n\nwith a body\n" || actual.Subject() != "three" {
+        t.Errorf("Expected commit three, received %+v", actual)
+        return
+    }
+    if len(actual.Parents) != 1 || actual.Parents[0] != ids[1] {
+        t.Errorf("Expected parent %s, received %v", ids[1], actual.Parents)
+        return
+    }
+}
+
+func TestCommitReturnsErrUnknownObject(t *testing.T) {
+    dir, _ := makeHistoryRepo(t)
+    repo, _ := Open(dir)
+
+    // perform the test
+    _, err := repo.Commit(commit1)
+
+    // did we get back what we expected?
+    if !errors.Is(err, ErrUnknownObject) {
+        t.Errorf("Expected ErrUnknownObject, received %v", err)
+        return
+    }
+}
+
+func TestCanFindCommitsSinceTag(t *testing.T) {
+    dir, ids := makeHistoryRepo(t)
+    repo, _ := Open(dir)
+    tag, _, _ := repo.Latest()
+
+    // our list of starting points, and the commits that we expect
+    var toFind = []struct {
+        since    string
+        expected []string
+    }{
+        {tag.Commit, []string{ids[5], ids[3], ids[4]}},
+        {ids[4], []string{ids[5], ids[3]}},
+        {ids[5], nil},
+        {"", []string{ids[5], ids[3], ids[4], ids[2], ids[1], ids[0]}},
+    }
+
+    for _, set := range toFind {
+        // perform the test
+        actual, err := repo.CommitsSince(set.since)
+
+        // was an error returned?
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        var actualIDs []string
+        for _, commit := range actual {
+            actualIDs = append(actualIDs, commit.ID)
+        }
+        if fmt.Sprint(actualIDs) != fmt.Sprint(set.expected) {
+            t.Errorf("since %q: expected %v, received %v", set.since, set.expected, actualIDs)
+            return
+        }
+    }
+}
+
+func TestCanFindLatestReachableTag(t *testing.T) {
+    dir, ids := makeHistoryRepo(t)
+    gitDir := filepath.Join(dir, ".git")
+
+    // a newer release on another branch, that HEAD does not include
+    other := commitBody("seven\n", ids[5])
+    writeLooseObject(t, gitDir, objectID("commit", other), "commit", other)
+    writeFile(t, gitDir, "refs/tags/v2.0.0", objectID("commit", other)+"\n")
+    writeFile(t, gitDir, "refs/tags/v1.1.0", ids[4]+"\n")
+    repo, _ := Open(dir)
+
+    // perform the test
+    actual, ok, err := repo.LatestReachable()
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if !ok || actual.Name != "v1.1.0" {
+        t.Errorf("Expected v1.1.0, received %+v", actual)
+        return
+    }
+
+    // a repository whose tags are all somewhere else
+    os.Remove(filepath.Join(gitDir, "refs", "tags", "v1.0.0"))
+    os.Remove(filepath.Join(gitDir, "refs", "tags", "v1.1.0"))
+    _, ok, err = repo.LatestReachable()
+    if ok || err != nil {
+        t.Errorf("Expected no tag, received %v, %v", ok, err)
+        return
+    }
+}
@@ -0,0 +1,270 @@
+// Package gitver reads version tags from a local git repository
+//
+// It reads the repository's refs (refs/tags and packed-refs) and objects
+// straight from disk, so it never needs to run git, and never touches the
+// network:
+//
+//     repo, err := gitver.Open(".")
+//     latest, ok, err := repo.Latest()
+//
+// Tags are parsed using semver.ParseVersion(), after removing any 'v' or
+// 'V' prefix, so that 'v1.3.0' and '1.3.0' are both understood. Tags that
+// are not version numbers are ignored.
+package gitver
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+    "sync"
+
+    "github.com/stuartherbert/go_semver/semver"
+)
+
+// returned by Open() when it cannot find a git repository
+var ErrNotARepository = fmt.Errorf("not a git repository")
+
+// Tag is a git tag that holds a version number
+type Tag struct {
+    Name    string            // the tag's name, without 'refs/tags/'
+    Version semver.SemVersion // the version parsed from Name
+    Object  string            // the object ID that the tag points at
+    Commit  string            // the commit ID that the tag points at, if we know it
+}
+
+// Repo is a local git repository
+//
+// create one by calling:
+//
+//     repo, err = gitver.Open("/path/to/repo")
+type Repo struct {
+    // where HEAD and the objects are
+    gitDir string
+
+    // where the shared refs and objects are; this is different to
+    // gitDir for linked worktrees
+    commonDir string
+
+    // the indexes of the pack files, loaded the first time that we need
+    // them
+    packsOnce sync.Once
+    packs     []*packIndex
+    packsErr  error
+}
+
+// Open finds the git repository at 'path'
+//
+// 'path' can be the top of a working tree, a '.git' directory, or a bare
+// repository
+//
+// returns ErrNotARepository if there is no git repository at 'path'
+func Open(path string) (*Repo, error) {
+    gitDir, err := findGitDir(path)
+    if err != nil {
+        return nil, err
+    }
+
+    repo := &Repo{gitDir: gitDir, commonDir: gitDir}
+
+    // linked worktrees share their refs with the main repository
+    if raw, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+        commonDir := strings.TrimSpace(string(raw))
+        if !filepath.IsAbs(commonDir) {
+            commonDir = filepath.Join(gitDir, commonDir)
+        }
+        repo.commonDir = filepath.Clean(commonDir)
+    }
+
+    return repo, nil
+}
+
+// findGitDir works out where the git directory for 'path' is
+func findGitDir(path string) (string, error) {
+    // is this a git directory already?
+    if isGitDir(path) {
+        return path, nil
+    }
+
+    dotGit := filepath.Join(path, ".git")
+    info, err := os.Stat(dotGit)
+    if err != nil {
+        return "", fmt.Errorf("%w: %s", ErrNotARepository, path)
+    }
+
+    // worktrees and submodules have a '.git' file that points to the
+    // real git directory
+    if !info.IsDir() {
+        raw, err := os.ReadFile(dotGit)
+        if err != nil {
+            return "", err
+        }
+        line := strings.TrimSpace(string(raw))
+        if !strings.HasPrefix(line, "gitdir: ") {
+            return "", fmt.Errorf("%w: %s", ErrNotARepository, path)
+        }
+        dotGit = strings.TrimPrefix(line, "gitdir: ")
+        if !filepath.IsAbs(dotGit) {
+            dotGit = filepath.Join(path, dotGit)
+        }
+    }
+
+    if !isGitDir(dotGit) {
+        return "", fmt.Errorf("%w: %s", ErrNotARepository, path)
+    }
+
+    return filepath.Clean(dotGit), nil
+}
+
+// isGitDir returns true if 'path' looks like a git directory
+func isGitDir(path string) bool {
+    info, err := os.Stat(filepath.Join(path, "HEAD"))
+    return err == nil && !info.IsDir()
+}
+
+// Tags returns all of the tags that hold version numbers, oldest version
+// first
+//
+// tags that hold the same version (e.g. 'v1.3' and '1.3.0') are sorted
+// by name
+func (r *Repo) Tags() ([]Tag, error) {
+    refs, err := r.refs("refs/tags/")
+    if err != nil {
+        return nil, err
+    }
+
+    var retval []Tag
+    for _, ref := range refs {
+        name := strings.TrimPrefix(ref.name, "refs/tags/")
+        version, ok := ParseTag(name)
+        if !ok {
+            continue
+        }
+
+        commit := ref.peeled
+        if commit == "" {
+            commit = r.peel(ref.object)
+        }
+        retval = append(retval, Tag{
+            Name:    name,
+            Version: version,
+            Object:  ref.object,
+            Commit:  commit,
+        })
+    }
+
+    sort.SliceStable(retval, func(i, j int) bool {
+        result := semver.Cmp(retval[i].Version, retval[j].Version)
+        if result != 0 {
+            return result < 0
+        }
+        return retval[i].Name < retval[j].Name
+    })
+
+    return retval, nil
+}
+
+// ParseTag parses a tag name into a version, ignoring any 'v' or 'V'
+// prefix
+//
+// returns false if the tag is not a version number
+func ParseTag(name string) (semver.SemVersion, bool) {
+    if strings.HasPrefix(name, "v") || strings.HasPrefix(name, "V") {
+        name = name[1:]
+    }
+
+    version, err := semver.ParseVersion(name)
+    if err != nil {
+        return semver.SemVersion{}, false
+    }
+
+    return version, true
+}
+
+// Latest returns the tag with the newest version
+//
+// returns false if the repository has no version tags
+func (r *Repo) Latest() (Tag, bool, error) {
+    tags, err := r.Tags()
+    if err != nil || len(tags) == 0 {
+        return Tag{}, false, err
+    }
+
+    return tags[len(tags)-1], true, nil
+}
+
+// LatestMatching returns the tag with the newest version that matches
+// 'exp'
+//
+// returns false if none of the version tags match
+func (r *Repo) LatestMatching(exp semver.VersionExpression) (Tag, bool, error) {
+    tags, err := r.Tags()
+    if err != nil {
+        return Tag{}, false, err
+    }
+
+    for i := len(tags) - 1; i >= 0; i-- {
+        if ok, _ := exp.MatchesVersion(&tags[i].Version); ok {
+            return tags[i], true, nil
+        }
+    }
+
+    return Tag{}, false, nil
+}
+
+// TagsBetween returns the tags with versions that are newer than 'from',
+// up to and including 'to', oldest version first
+//
+// this is the list of releases that someone upgrading from 'from' to 'to'
+// needs to know about
+func (r *Repo) TagsBetween(from semver.SemVersion, to semver.SemVersion) ([]Tag, error) {
+    tags, err := r.Tags()
+    if err != nil {
+        return nil, err
+    }
+
+    var retval []Tag
+    for _, tag := range tags {
+        if semver.Cmp(tag.Version, from) > 0 && semver.Cmp(tag.Version, to) <= 0 {
+            retval = append(retval, tag)
+        }
+    }
+
+    return retval, nil
+}
+
+// Head returns the commit ID that HEAD points at
+func (r *Repo) Head() (string, error) {
+    raw, err := os.ReadFile(filepath.Join(r.gitDir, "HEAD"))
+    if err != nil {
+        return "", err
+    }
+
+    return r.resolve(strings.TrimSpace(string(raw)))
+}
+
+// HeadTags returns the version tags that point at HEAD, oldest version
+// first
+//
+// an empty list means that HEAD has not been tagged yet
+func (r *Repo) HeadTags() ([]Tag, error) {
+    head, err := r.Head()
+    if err != nil {
+        return nil, err
+    }
+
+    tags, err := r.Tags()
+    if err != nil {
+        return nil, err
+    }
+
+    var retval []Tag
+    for _, tag := range tags {
+        if tag.Commit == head {
+            retval = append(retval, tag)
+        }
+    }
+
+    return retval, nil
+}
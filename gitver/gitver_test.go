@@ -0,0 +1,446 @@
+package gitver
+
+import (
+    "bytes"
+    "compress/zlib"
+    "crypto/sha1"
+    "encoding/binary"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "os"
+    "path/filepath"
+    "sort"
+    "testing"
+
+    "github.com/stuartherbert/go_semver/semver"
+)
+
+// the commits in our example repository
+const (
+    commit1 = "1111111111111111111111111111111111111111"
+    commit2 = "2222222222222222222222222222222222222222"
+    commit3 = "3333333333333333333333333333333333333333"
+    tagObj  = "4444444444444444444444444444444444444444"
+)
+
+// writeFile creates a file in our example repository
+func writeFile(t *testing.T, dir string, name string, contents string) {
+    path := filepath.Join(dir, filepath.FromSlash(name))
+    if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+        t.Fatal(err)
+    }
+}
+
+// writeLooseObject adds a compressed object to our example repository
+func writeLooseObject(t *testing.T, gitDir string, object string, kind string, body string) {
+    var buf bytes.Buffer
+    writer := zlib.NewWriter(&buf)
+    fmt.Fprintf(writer, "%s %d\x00%s", kind, len(body), body)
+    writer.Close()
+
+    writeFile(t, gitDir, "objects/"+object[:2]+"/"+object[2:], buf.String())
+}
+
+// objectID works out the ID that git gives an object
+func objectID(kind string, body string) string {
+    sum := sha1.Sum([]byte(fmt.Sprintf("%s %d\x00%s", kind, len(body), body)))
+    return hex.EncodeToString(sum[:])
+}
+
+// makeDelta builds a git delta that turns 'base' into 'target', by copying
+// the prefix that they share and inserting the rest
+func makeDelta(base string, target string) []byte {
+    shared := 0
+    for shared < len(base) && shared < len(target) && base[shared] == target[shared] {
+        shared++
+    }
+
+    delta := binary.AppendUvarint(nil, uint64(len(base)))
+    delta = binary.AppendUvarint(delta, uint64(len(target)))
+    if shared > 0 {
+        // copy 'shared' bytes from offset 0
+        delta = append(delta, 0x80|0x10|0x20, byte(shared), byte(shared>>8))
+    }
+    for rest := target[shared:]; len(rest) > 0; {
+        n := len(rest)
+        if n > 127 {
+            n = 127
+        }
+        delta = append(delta, byte(n))
+        delta = append(delta, rest[:n]...)
+        rest = rest[n:]
+    }
+
+    return delta
+}
+
+// writePack adds a pack file of 'kind' objects to our example repository
+//
+// each object is stored as a delta of the object before it
+func writePack(t *testing.T, gitDir string, kind string, bodies []string) []string {
+    var pack bytes.Buffer
+    pack.WriteString("PACK")
+    binary.Write(&pack, binary.BigEndian, uint32(2))
+    binary.Write(&pack, binary.BigEndian, uint32(len(bodies)))
+
+    ids := make([]string, len(bodies))
+    offsets := map[string]uint32{}
+    for i, body := range bodies {
+        ids[i] = objectID(kind, body)
+        offsets[ids[i]] = uint32(pack.Len())
+
+        // the header holds the type, and the size of the data; we
+        // keep everything small enough to fit into two bytes
+        data := []byte(body)
+        packKind := packCommit
+        if kind == "tag" {
+            packKind = packTag
+        }
+        if i > 0 {
+            data = makeDelta(bodies[i-1], body)
+            packKind = packOfsDelta
+        }
+        if len(data) > 0x7ff {
+            t.Fatal("object too large for our test pack")
+        }
+        pack.WriteByte(byte(0x80 | packKind<<4 | len(data)&0x0f))
+        pack.WriteByte(byte(len(data) >> 4))
+        if i > 0 {
+            distance := offsets[ids[i]] - offsets[ids[i-1]]
+            if distance > 0x7f {
+                pack.WriteByte(byte(0x80 | ((distance >> 7) - 1)))
+                pack.WriteByte(byte(distance & 0x7f))
+            } else {
+                pack.WriteByte(byte(distance))
+            }
+        }
+
+        writer := zlib.NewWriter(&pack)
+        writer.Write(data)
+        writer.Close()
+    }
+    writePackFiles(t, gitDir, &pack, ids, offsets)
+
+    return ids
+}
+
+// writePackFiles finishes off a pack file, and writes it to our example
+// repository along with its index
+//
+// 'offsets' says where each of the objects in 'ids' starts in the pack
+func writePackFiles(t *testing.T, gitDir string, pack *bytes.Buffer, ids []string, offsets map[string]uint32) {
+    trailer := sha1.Sum(pack.Bytes())
+    pack.Write(trailer[:])
+
+    // the index needs the IDs in sorted order
+    sorted := append([]string{}, ids...)
+    sort.Strings(sorted)
+
+    var idx bytes.Buffer
+    idx.WriteString("\377tOc")
+    binary.Write(&idx, binary.BigEndian, uint32(2))
+    for i := 0; i < 256; i++ {
+        count := sort.Search(len(sorted), func(j int) bool {
+            first, _ := hex.DecodeString(sorted[j][:2])
+            return int(first[0]) > i
+        })
+        binary.Write(&idx, binary.BigEndian, uint32(count))
+    }
+    for _, id := range sorted {
+        raw, _ := hex.DecodeString(id)
+        idx.Write(raw)
+    }
+    for range sorted {
+        binary.Write(&idx, binary.BigEndian, uint32(0))
+    }
+    for _, id := range sorted {
+        binary.Write(&idx, binary.BigEndian, offsets[id])
+    }
+
+    writeFile(t, gitDir, "objects/pack/pack-test.pack", pack.String())
+    writeFile(t, gitDir, "objects/pack/pack-test.idx", idx.String())
+}
+
+// makeExampleRepo creates a repository with a mix of packed and loose
+// tags, some of which are not version numbers
+func makeExampleRepo(t *testing.T) string {
+    dir := t.TempDir()
+    gitDir := filepath.Join(dir, ".git")
+
+    writeFile(t, gitDir, "HEAD", "ref: refs/heads/main\n")
+    writeFile(t, gitDir, "refs/heads/main", commit3+"\n")
+    writeFile(t, gitDir, "packed-refs", "# pack-refs with: peeled fully-peeled sorted\n"+
+        commit1+" refs/heads/old\n"+
+        commit1+" refs/tags/v1.2.0\n"+
+        tagObj+" refs/tags/v1.3.0\n"+
+        "^"+commit2+"\n"+
+        commit1+" refs/tags/v2.0.0\n")
+
+    // loose refs take priority over packed-refs
+    writeFile(t, gitDir, "refs/tags/v2.0.0", commit3+"\n")
+    writeFile(t, gitDir, "refs/tags/1.10.0-rc.1", commit2+"\n")
+    writeFile(t, gitDir, "refs/tags/release/candidate", commit3+"\n")
+    writeFile(t, gitDir, "refs/tags/not-a-version", commit3+"\n")
+
+    // an annotated tag, which we have to peel ourselves
+    writeFile(t, gitDir, "refs/tags/V1.9", "5555555555555555555555555555555555555555\n")
+    writeLooseObject(t, gitDir, "5555555555555555555555555555555555555555", "tag",
+        "object "+commit3+"\ntype commit\ntag V1.9\n\nrelease 1.9\n")
+
+    return dir
+}
+
+// tagNames returns the names of a list of tags
+func tagNames(tags []Tag) []string {
+    var retval []string
+    for _, tag := range tags {
+        retval = append(retval, tag.Name)
+    }
+
+    return retval
+}
+
+// ========================================================================
+//
+// Tests for Open()
+//
+// ------------------------------------------------------------------------
+
+func TestCanOpenRepository(t *testing.T) {
+    dir := makeExampleRepo(t)
+
+    // a working tree, its .git directory, and a worktree's .git file all
+    // work
+    worktree := t.TempDir()
+    writeFile(t, worktree, ".git", "gitdir: "+filepath.Join(dir, ".git")+"\n")
+
+    for _, path := range []string{dir, filepath.Join(dir, ".git"), worktree} {
+        // perform the test
+        repo, err := Open(path)
+
+        // was an error returned?
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if repo.gitDir != filepath.Join(dir, ".git") {
+            t.Errorf("%s: expected git dir %s, received %s", path, filepath.Join(dir, ".git"), repo.gitDir)
+            return
+        }
+    }
+}
+
+func TestOpenReturnsErrNotARepository(t *testing.T) {
+    // perform the test
+    _, err := Open(t.TempDir())
+
+    // did we get back what we expected?
+    if !errors.Is(err, ErrNotARepository) {
+        t.Errorf("Expected ErrNotARepository, received %v", err)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for Tags(), Latest(), LatestMatching() and TagsBetween()
+//
+// ------------------------------------------------------------------------
+
+func TestCanReadTags(t *testing.T) {
+    // what result do we expect?
+    expected := []Tag{
+        {"v1.2.0", semver.SemVersion{Major: 1, Minor: 2}, commit1, commit1},
+        {"v1.3.0", semver.SemVersion{Major: 1, Minor: 3}, tagObj, commit2},
+        {"V1.9", semver.SemVersion{Major: 1, Minor: 9}, "5555555555555555555555555555555555555555", commit3},
+        {"1.10.0-rc.1", semver.SemVersion{Major: 1, Minor: 10, PreRelease: "rc.1"}, commit2, commit2},
+        {"v2.0.0", semver.SemVersion{Major: 2}, commit3, commit3},
+    }
+
+    // perform the test
+    repo, _ := Open(makeExampleRepo(t))
+    actual, err := repo.Tags()
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if len(actual) != len(expected) {
+        t.Errorf("Expected %v, received %v", tagNames(expected), tagNames(actual))
+        return
+    }
+    for i := range expected {
+        if actual[i] != expected[i] {
+            t.Errorf("Expected %+v at position %d, received %+v", expected[i], i, actual[i])
+            return
+        }
+    }
+}
+
+func TestCanFindLatestTag(t *testing.T) {
+    repo, _ := Open(makeExampleRepo(t))
+
+    // perform the test
+    actual, ok, err := repo.Latest()
+
+    // did we get back what we expected?
+    if err != nil || !ok || actual.Name != "v2.0.0" {
+        t.Errorf("Expected v2.0.0, received %+v, %v, %v", actual, ok, err)
+        return
+    }
+
+    // what about a repository with no tags at all?
+    dir := t.TempDir()
+    writeFile(t, dir, "HEAD", "ref: refs/heads/main\n")
+    repo, _ = Open(dir)
+    _, ok, err = repo.Latest()
+    if err != nil || ok {
+        t.Errorf("Expected no tag, received %v, %v", ok, err)
+        return
+    }
+}
+
+func TestCanPeelPackedTags(t *testing.T) {
+    dir := makeExampleRepo(t)
+    gitDir := filepath.Join(dir, ".git")
+
+    // an annotated tag that has been packed, but whose ref has not
+    ids := writePack(t, gitDir, "tag", []string{"object " + commit3 + "\ntype commit\ntag v3.0.0\n\nrelease 3.0\n"})
+    writeFile(t, gitDir, "refs/tags/v3.0.0", ids[0]+"\n")
+
+    // what result do we expect?
+    expected := Tag{"v3.0.0", semver.SemVersion{Major: 3}, ids[0], commit3}
+
+    // perform the test
+    repo, _ := Open(dir)
+    actual, ok, err := repo.Latest()
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if !ok || actual != expected {
+        t.Errorf("Expected %+v, received %+v", expected, actual)
+        return
+    }
+}
+
+func TestCanFindLatestMatchingTag(t *testing.T) {
+    // our list of expressions, and the tags that we expect
+    var toFind = map[string]string{
+        "<=1.9": "V1.9",
+        "<1.10": "1.10.0-rc.1",
+        "=1.2":  "v1.2.0",
+        "^2.0":  "v2.0.0",
+        ">=3.0": "",
+    }
+
+    repo, _ := Open(makeExampleRepo(t))
+    for raw, expected := range toFind {
+        exp, _ := semver.ParseExpression(raw)
+
+        // perform the test
+        actual, ok, err := repo.LatestMatching(exp)
+
+        // was an error returned?
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if ok != (expected != "") || actual.Name != expected {
+            t.Errorf("%s: expected %q, received %q", raw, expected, actual.Name)
+            return
+        }
+    }
+}
+
+func TestCanFindTagsBetweenVersions(t *testing.T) {
+    // what result do we expect?
+    expected := []string{"v1.3.0", "V1.9", "1.10.0-rc.1"}
+
+    // perform the test
+    repo, _ := Open(makeExampleRepo(t))
+    actual, err := repo.TagsBetween(semver.SemVersion{Major: 1, Minor: 2}, semver.SemVersion{Major: 1, Minor: 10, PreRelease: "rc.1"})
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if fmt.Sprint(tagNames(actual)) != fmt.Sprint(expected) {
+        t.Errorf("Expected %v, received %v", expected, tagNames(actual))
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for Head() and HeadTags()
+//
+// ------------------------------------------------------------------------
+
+func TestCanFindHeadTags(t *testing.T) {
+    // what result do we expect?
+    expected := []string{"V1.9", "v2.0.0"}
+
+    // perform the test
+    repo, _ := Open(makeExampleRepo(t))
+    actual, err := repo.HeadTags()
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if fmt.Sprint(tagNames(actual)) != fmt.Sprint(expected) {
+        t.Errorf("Expected %v, received %v", expected, tagNames(actual))
+        return
+    }
+}
+
+func TestCanResolvePackedAndDetachedHead(t *testing.T) {
+    dir := makeExampleRepo(t)
+    repo, _ := Open(dir)
+
+    // HEAD points at a branch that only exists in packed-refs
+    writeFile(t, filepath.Join(dir, ".git"), "HEAD", "ref: refs/heads/old\n")
+    actual, err := repo.Head()
+    if err != nil || actual != commit1 {
+        t.Errorf("Expected %s, received %s, %v", commit1, actual, err)
+        return
+    }
+
+    // detached HEAD
+    writeFile(t, filepath.Join(dir, ".git"), "HEAD", commit2+"\n")
+    actual, err = repo.Head()
+    if err != nil || actual != commit2 {
+        t.Errorf("Expected %s, received %s, %v", commit2, actual, err)
+        return
+    }
+
+    // a branch that does not exist
+    writeFile(t, filepath.Join(dir, ".git"), "HEAD", "ref: refs/heads/missing\n")
+    _, err = repo.Head()
+    if !errors.Is(err, ErrUnknownRef) {
+        t.Errorf("Expected ErrUnknownRef, received %v", err)
+        return
+    }
+}
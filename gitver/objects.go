@@ -0,0 +1,389 @@
+package gitver
+
+import (
+    "bufio"
+    "bytes"
+    "compress/zlib"
+    "encoding/binary"
+    "encoding/hex"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "sort"
+)
+
+// returned when an object is not in the repository
+var ErrUnknownObject = fmt.Errorf("unknown object")
+
+// the object types, as they appear in a pack file
+const (
+    packCommit   = 1
+    packTree     = 2
+    packBlob     = 3
+    packTag      = 4
+    packOfsDelta = 6
+    packRefDelta = 7
+)
+
+// the names of the object types that we can find in a pack file
+var packTypeNames = map[int]string{
+    packCommit: "commit",
+    packTree:   "tree",
+    packBlob:   "blob",
+    packTag:    "tag",
+}
+
+// deltas can be built on top of other deltas; we give up after this many,
+// so that a corrupt pack cannot send us round in circles
+const maxDeltaDepth = 1000
+
+// the largest object that we will rebuild from a delta
+//
+// we only read commits and tags, which are far smaller than this, so a
+// delta that claims to build anything larger must be corrupt
+const maxDeltaResultSize = 64 << 20
+
+// packIndex is a pack file, and the index that tells us where each
+// object is inside it
+type packIndex struct {
+    path    string   // the .pack file
+    ids     [][]byte // object IDs, sorted
+    offsets []int64  // where each object starts in the .pack file
+}
+
+// readObject finds an object, either as a loose object or in one of the
+// repository's pack files
+//
+// returns the object's type (e.g. 'commit' or 'tag') and its contents
+func (r *Repo) readObject(object string) (string, []byte, error) {
+    return r.readObjectAtDepth(object, 0)
+}
+
+// readObjectAtDepth does the work for readObject()
+//
+// 'depth' is how many deltas we have already found on the way to this
+// object, when it is the base of a ref delta
+func (r *Repo) readObjectAtDepth(object string, depth int) (string, []byte, error) {
+    kind, body, err := r.readLooseObject(object)
+    if err == nil {
+        return kind, body, nil
+    }
+    if !os.IsNotExist(err) {
+        return "", nil, err
+    }
+
+    id, err := hex.DecodeString(object)
+    if err != nil {
+        return "", nil, fmt.Errorf("%w: %s", ErrUnknownObject, object)
+    }
+
+    packs, err := r.packIndexes()
+    if err != nil {
+        return "", nil, err
+    }
+    for _, pack := range packs {
+        if offset, ok := pack.find(id); ok {
+            return r.readPackedObject(pack, offset, depth)
+        }
+    }
+
+    return "", nil, fmt.Errorf("%w: %s", ErrUnknownObject, object)
+}
+
+// packIndexes loads the index of each of the repository's pack files
+//
+// they are only loaded once
+func (r *Repo) packIndexes() ([]*packIndex, error) {
+    r.packsOnce.Do(func() {
+        var paths []string
+        paths, r.packsErr = filepath.Glob(filepath.Join(r.commonDir, "objects", "pack", "*.idx"))
+        for _, path := range paths {
+            var pack *packIndex
+            pack, r.packsErr = loadPackIndex(path)
+            if r.packsErr != nil {
+                return
+            }
+            r.packs = append(r.packs, pack)
+        }
+    })
+
+    return r.packs, r.packsErr
+}
+
+// loadPackIndex reads a version 2 pack index file
+//
+// the file holds:
+//
+//     "\377tOc", version 2
+//     256 x 4-byte fan-out table
+//     N x 20-byte object IDs, sorted
+//     N x 4-byte CRCs
+//     N x 4-byte offsets (MSB set == index into the large offsets)
+//     M x 8-byte large offsets
+func loadPackIndex(path string) (*packIndex, error) {
+    raw, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+
+    const headerLen = 8 + 256*4
+    if len(raw) < headerLen || !bytes.Equal(raw[:4], []byte("\377tOc")) || binary.BigEndian.Uint32(raw[4:8]) != 2 {
+        return nil, fmt.Errorf("%s: unsupported pack index", path)
+    }
+
+    count := int(binary.BigEndian.Uint32(raw[headerLen-4 : headerLen]))
+    idStart := headerLen
+    offsetStart := idStart + count*20 + count*4
+    largeStart := offsetStart + count*4
+    if len(raw) < largeStart {
+        return nil, fmt.Errorf("%s: truncated pack index", path)
+    }
+
+    pack := &packIndex{
+        path:    path[:len(path)-len(".idx")] + ".pack",
+        ids:     make([][]byte, count),
+        offsets: make([]int64, count),
+    }
+    for i := 0; i < count; i++ {
+        pack.ids[i] = raw[idStart+i*20 : idStart+(i+1)*20]
+
+        offset := binary.BigEndian.Uint32(raw[offsetStart+i*4:])
+        if offset&0x80000000 == 0 {
+            pack.offsets[i] = int64(offset)
+            continue
+        }
+
+        large := largeStart + int(offset&0x7fffffff)*8
+        if len(raw) < large+8 {
+            return nil, fmt.Errorf("%s: truncated pack index", path)
+        }
+        pack.offsets[i] = int64(binary.BigEndian.Uint64(raw[large:]))
+    }
+
+    return pack, nil
+}
+
+// find looks up where an object is in the pack file
+//
+// returns false if the object is not in this pack
+func (p *packIndex) find(id []byte) (int64, bool) {
+    i := sort.Search(len(p.ids), func(i int) bool {
+        return bytes.Compare(p.ids[i], id) >= 0
+    })
+    if i < len(p.ids) && bytes.Equal(p.ids[i], id) {
+        return p.offsets[i], true
+    }
+
+    return 0, false
+}
+
+// readPackedObject reads the object that starts at 'offset' in a pack
+// file, applying any deltas that it is built from
+//
+// 'depth' is how many deltas we have already found on the way to it
+func (r *Repo) readPackedObject(pack *packIndex, offset int64, depth int) (string, []byte, error) {
+    file, err := os.Open(pack.path)
+    if err != nil {
+        return "", nil, err
+    }
+    defer file.Close()
+
+    // deltas are applied on top of their base, so we collect them
+    // until we find an object that isn't a delta
+    var deltas [][]byte
+    for ; depth < maxDeltaDepth; depth++ {
+        reader := bufio.NewReader(io.NewSectionReader(file, offset, 1<<62))
+        kind, err := readPackHeader(reader)
+        if err != nil {
+            return "", nil, fmt.Errorf("%s: %v", pack.path, err)
+        }
+
+        switch kind {
+        case packOfsDelta:
+            distance, err := readOfsDeltaDistance(reader)
+            if err != nil {
+                return "", nil, fmt.Errorf("%s: %v", pack.path, err)
+            }
+            delta, err := inflate(reader)
+            if err != nil {
+                return "", nil, err
+            }
+            deltas = append(deltas, delta)
+            offset -= distance
+
+        case packRefDelta:
+            base := make([]byte, 20)
+            if _, err := io.ReadFull(reader, base); err != nil {
+                return "", nil, err
+            }
+            delta, err := inflate(reader)
+            if err != nil {
+                return "", nil, err
+            }
+
+            // the base can be anywhere, even in another pack
+            baseKind, body, err := r.readObjectAtDepth(hex.EncodeToString(base), depth+1)
+            if err != nil {
+                return "", nil, err
+            }
+            deltas = append(deltas, delta)
+            return applyDeltas(baseKind, body, deltas)
+
+        default:
+            name, ok := packTypeNames[kind]
+            if !ok {
+                return "", nil, fmt.Errorf("%s: unknown object type %d", pack.path, kind)
+            }
+            body, err := inflate(reader)
+            if err != nil {
+                return "", nil, err
+            }
+            return applyDeltas(name, body, deltas)
+        }
+    }
+
+    return "", nil, fmt.Errorf("%s: too many deltas", pack.path)
+}
+
+// readPackHeader reads the type and size that start each object in a pack
+// file
+//
+// we don't need the size, because zlib tells us where the object ends
+func readPackHeader(reader io.ByteReader) (int, error) {
+    c, err := reader.ReadByte()
+    if err != nil {
+        return 0, err
+    }
+    kind := int(c>>4) & 7
+    for c&0x80 != 0 {
+        if c, err = reader.ReadByte(); err != nil {
+            return 0, err
+        }
+    }
+
+    return kind, nil
+}
+
+// readOfsDeltaDistance reads how far back in the pack file a delta's base
+// object is
+func readOfsDeltaDistance(reader io.ByteReader) (int64, error) {
+    c, err := reader.ReadByte()
+    if err != nil {
+        return 0, err
+    }
+    distance := int64(c & 0x7f)
+    for c&0x80 != 0 {
+        if c, err = reader.ReadByte(); err != nil {
+            return 0, err
+        }
+        distance = ((distance + 1) << 7) | int64(c&0x7f)
+    }
+
+    return distance, nil
+}
+
+// inflate decompresses the zlib stream that 'reader' is positioned at
+func inflate(reader io.Reader) ([]byte, error) {
+    inflater, err := zlib.NewReader(reader)
+    if err != nil {
+        return nil, err
+    }
+    defer inflater.Close()
+
+    return io.ReadAll(inflater)
+}
+
+// applyDeltas rebuilds an object from its base, and the deltas that we
+// found on the way to the base
+//
+// 'deltas' is in the order that we found them, so the last one must be
+// applied first
+func applyDeltas(kind string, body []byte, deltas [][]byte) (string, []byte, error) {
+    for i := len(deltas) - 1; i >= 0; i-- {
+        var err error
+        body, err = applyDelta(body, deltas[i])
+        if err != nil {
+            return "", nil, err
+        }
+    }
+
+    return kind, body, nil
+}
+
+// applyDelta builds a new object from 'base' and a git delta
+//
+// a delta holds the size of the base and of the result, followed by
+// instructions to either copy a range of bytes from the base, or to
+// insert new bytes
+func applyDelta(base []byte, delta []byte) ([]byte, error) {
+    errCorrupt := fmt.Errorf("corrupt delta")
+
+    baseSize, n := binary.Uvarint(delta)
+    if n <= 0 || baseSize != uint64(len(base)) {
+        return nil, errCorrupt
+    }
+    delta = delta[n:]
+    resultSize, n := binary.Uvarint(delta)
+    if n <= 0 {
+        return nil, errCorrupt
+    }
+    delta = delta[n:]
+
+    // each instruction adds, at most, the whole of the base or 127 new
+    // bytes; we check the size before we trust it to allocate the result
+    most := uint64(len(base))
+    if most < 0x7f {
+        most = 0x7f
+    }
+    if resultSize > maxDeltaResultSize || resultSize > most*uint64(len(delta)) {
+        return nil, errCorrupt
+    }
+
+    result := make([]byte, 0, resultSize)
+    for len(delta) > 0 {
+        op := delta[0]
+        delta = delta[1:]
+
+        if op&0x80 == 0 {
+            // insert the next 'op' bytes
+            if op == 0 || int(op) > len(delta) {
+                return nil, errCorrupt
+            }
+            result = append(result, delta[:op]...)
+            delta = delta[op:]
+            continue
+        }
+
+        // copy from the base; the low 7 bits say which bytes of the
+        // offset and size follow
+        var offset, size uint64
+        for i := uint(0); i < 7; i++ {
+            if op&(1<<i) == 0 {
+                continue
+            }
+            if len(delta) == 0 {
+                return nil, errCorrupt
+            }
+            if i < 4 {
+                offset |= uint64(delta[0]) << (8 * i)
+            } else {
+                size |= uint64(delta[0]) << (8 * (i - 4))
+            }
+            delta = delta[1:]
+        }
+        if size == 0 {
+            size = 0x10000
+        }
+        if offset+size > uint64(len(base)) {
+            return nil, errCorrupt
+        }
+        result = append(result, base[offset:offset+size]...)
+    }
+
+    if uint64(len(result)) != resultSize {
+        return nil, errCorrupt
+    }
+
+    return result, nil
+}
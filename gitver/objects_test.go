@@ -0,0 +1,84 @@
+package gitver
+
+import (
+    "bytes"
+    "compress/zlib"
+    "encoding/binary"
+    "encoding/hex"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+// ========================================================================
+//
+// Tests for reading corrupt objects
+//
+// ------------------------------------------------------------------------
+
+func TestReadObjectRejectsRefDeltaLoops(t *testing.T) {
+    gitDir := filepath.Join(t.TempDir(), ".git")
+    writeFile(t, gitDir, "HEAD", "ref: refs/heads/main\n")
+
+    // a ref delta whose base is itself
+    object := objectID("commit", commitBody("one\n"))
+    base, _ := hex.DecodeString(object)
+
+    var pack bytes.Buffer
+    pack.WriteString("PACK")
+    binary.Write(&pack, binary.BigEndian, uint32(2))
+    binary.Write(&pack, binary.BigEndian, uint32(1))
+    pack.WriteByte(byte(packRefDelta<<4 | 4))
+    pack.Write(base)
+    writer := zlib.NewWriter(&pack)
+    writer.Write([]byte{0, 0, 0, 0})
+    writer.Close()
+    writePackFiles(t, gitDir, &pack, []string{object}, map[string]uint32{object: 12})
+
+    repo, err := Open(filepath.Dir(gitDir))
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // perform the test
+    _, _, err = repo.readObject(object)
+
+    // was an error returned?
+    if err == nil || !strings.Contains(err.Error(), "too many deltas") {
+        t.Errorf("Expected too many deltas, received %v", err)
+        return
+    }
+}
+
+func TestApplyDeltaRejectsImpossibleSizes(t *testing.T) {
+    base := []byte("tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n")
+
+    // a delta that claims to build a huge object from a single insert
+    delta := binary.AppendUvarint(nil, uint64(len(base)))
+    delta = binary.AppendUvarint(delta, 1<<40)
+    delta = append(delta, 1, 'x')
+
+    // perform the test
+    actual, err := applyDelta(base, delta)
+
+    // was an error returned?
+    if err == nil {
+        t.Errorf("Expected an error, received %d bytes", len(actual))
+        return
+    }
+
+    // a size that the delta can build is still fine
+    delta = binary.AppendUvarint(nil, uint64(len(base)))
+    delta = binary.AppendUvarint(delta, 1)
+    delta = append(delta, 1, 'x')
+    actual, err = applyDelta(base, delta)
+    if err != nil {
+        t.Error(err)
+        return
+    }
+    if !bytes.Equal(actual, []byte("x")) {
+        t.Errorf("Expected x, received %q", actual)
+        return
+    }
+}
@@ -0,0 +1,238 @@
+package gitver
+
+import (
+    "bufio"
+    "bytes"
+    "compress/zlib"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+)
+
+// returned when a ref cannot be resolved to an object ID
+var ErrUnknownRef = fmt.Errorf("unknown ref")
+
+// ref is a single entry from the repository's refs
+type ref struct {
+    name   string // e.g. 'refs/tags/v1.3.0'
+    object string // the object ID that the ref points at
+    peeled string // the commit ID, if packed-refs told us what it is
+}
+
+// symbolic refs can point at other symbolic refs; we give up after this
+// many, the same way that git does
+const maxSymrefDepth = 5
+
+// refs returns all of the refs whose names start with 'prefix', sorted
+// by name
+//
+// loose refs take priority over the ones in packed-refs, the same way
+// that they do in git
+func (r *Repo) refs(prefix string) ([]ref, error) {
+    found := map[string]ref{}
+
+    packed, err := r.packedRefs()
+    if err != nil {
+        return nil, err
+    }
+    for _, entry := range packed {
+        if strings.HasPrefix(entry.name, prefix) {
+            found[entry.name] = entry
+        }
+    }
+
+    root := filepath.Join(r.commonDir, filepath.FromSlash(prefix))
+    err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+        if err != nil {
+            if os.IsNotExist(err) {
+                return nil
+            }
+            return err
+        }
+        if info.IsDir() {
+            return nil
+        }
+
+        raw, err := os.ReadFile(path)
+        if err != nil {
+            return err
+        }
+        rel, err := filepath.Rel(r.commonDir, path)
+        if err != nil {
+            return err
+        }
+        name := filepath.ToSlash(rel)
+        found[name] = ref{name: name, object: strings.TrimSpace(string(raw))}
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    retval := make([]ref, 0, len(found))
+    for _, entry := range found {
+        retval = append(retval, entry)
+    }
+    sort.Slice(retval, func(i, j int) bool {
+        return retval[i].name < retval[j].name
+    })
+
+    return retval, nil
+}
+
+// packedRefs reads the repository's packed-refs file
+//
+// the file looks like this:
+//
+//     # pack-refs with: peeled fully-peeled sorted
+//     <object ID> refs/heads/main
+//     <object ID> refs/tags/v1.3.0
+//     ^<commit ID>
+//
+// where a line that starts with '^' holds the commit ID that the
+// annotated tag on the line before points at
+func (r *Repo) packedRefs() ([]ref, error) {
+    file, err := os.Open(filepath.Join(r.commonDir, "packed-refs"))
+    if os.IsNotExist(err) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    defer file.Close()
+
+    var retval []ref
+    scanner := bufio.NewScanner(file)
+    for scanner.Scan() {
+        line := strings.TrimSpace(scanner.Text())
+        switch {
+        case line == "" || line[0] == '#':
+            continue
+        case line[0] == '^':
+            if len(retval) > 0 {
+                retval[len(retval)-1].peeled = line[1:]
+            }
+        default:
+            fields := strings.Fields(line)
+            if len(fields) == 2 {
+                retval = append(retval, ref{name: fields[1], object: fields[0]})
+            }
+        }
+    }
+
+    return retval, scanner.Err()
+}
+
+// resolve turns the contents of a ref file (either an object ID, or
+// 'ref: <name>') into an object ID
+func (r *Repo) resolve(target string) (string, error) {
+    for depth := 0; depth < maxSymrefDepth; depth++ {
+        if !strings.HasPrefix(target, "ref: ") {
+            return target, nil
+        }
+
+        name := strings.TrimPrefix(target, "ref: ")
+        next, err := r.readRef(name)
+        if err != nil {
+            return "", err
+        }
+        target = next
+    }
+
+    return "", fmt.Errorf("%w: too many levels of symbolic refs", ErrUnknownRef)
+}
+
+// readRef finds the contents of a single ref
+func (r *Repo) readRef(name string) (string, error) {
+    for _, dir := range []string{r.gitDir, r.commonDir} {
+        raw, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+        if err == nil {
+            return strings.TrimSpace(string(raw)), nil
+        }
+    }
+
+    packed, err := r.packedRefs()
+    if err != nil {
+        return "", err
+    }
+    for _, entry := range packed {
+        if entry.name == name {
+            return entry.object, nil
+        }
+    }
+
+    return "", fmt.Errorf("%w: %s", ErrUnknownRef, name)
+}
+
+// peel follows an annotated tag to the commit that it points at
+//
+// if we cannot read the tag object, 'object' is returned as it is
+func (r *Repo) peel(object string) string {
+    for depth := 0; depth < maxSymrefDepth; depth++ {
+        kind, body, err := r.readObject(object)
+        if err != nil || kind != "tag" {
+            return object
+        }
+
+        target, ok := tagTarget(body)
+        if !ok {
+            return object
+        }
+        object = target
+    }
+
+    return object
+}
+
+// readLooseObject reads an object from the repository's objects directory
+//
+// returns the object's type (e.g. 'commit' or 'tag') and its contents
+func (r *Repo) readLooseObject(object string) (string, []byte, error) {
+    if len(object) < 3 {
+        return "", nil, fmt.Errorf("%w: %s", ErrUnknownRef, object)
+    }
+
+    file, err := os.Open(filepath.Join(r.commonDir, "objects", object[:2], object[2:]))
+    if err != nil {
+        return "", nil, err
+    }
+    defer file.Close()
+
+    reader, err := zlib.NewReader(file)
+    if err != nil {
+        return "", nil, err
+    }
+    defer reader.Close()
+
+    raw, err := io.ReadAll(reader)
+    if err != nil {
+        return "", nil, err
+    }
+
+    // the header is '<type> <size>\0'
+    nul := bytes.IndexByte(raw, 0)
+    space := bytes.IndexByte(raw, ' ')
+    if nul < 0 || space < 0 || space > nul {
+        return "", nil, fmt.Errorf("corrupt object %s", object)
+    }
+
+    return string(raw[:space]), raw[nul+1:], nil
+}
+
+// tagTarget finds the 'object' header in an annotated tag
+func tagTarget(body []byte) (string, bool) {
+    for _, line := range strings.Split(string(body), "\n") {
+        if line == "" {
+            // end of the headers
+            break
+        }
+        if strings.HasPrefix(line, "object ") {
+            return strings.TrimPrefix(line, "object "), true
+        }
+    }
+
+    return "", false
+}
@@ -0,0 +1,172 @@
+package resolve
+
+import (
+    "fmt"
+    "strings"
+)
+
+// returned (wrapped) by Solve() when no set of versions satisfies all of
+// the requirements
+var ErrNoSolution = fmt.Errorf("no versions satisfy the requirements")
+
+// NoSolutionError is returned by Solve() when there is no solution
+//
+// Error() explains why, one step per line, e.g.
+//
+//     Because any version of foo depends on bar >=2.0.0, <3.0.0-0 and no versions of bar match >=2.0.0, <3.0.0-0, any version of foo is forbidden.
+//     And because the root requirements ask for foo >=1.0.0, <2.0.0-0, version solving failed.
+//
+// Steps that are used more than once are numbered, so that later steps
+// can refer back to them.
+//
+// errors.Is(err, ErrNoSolution) returns true
+type NoSolutionError struct {
+    incompatibility *incompatibility
+}
+
+// Error explains why there is no solution
+func (e *NoSolutionError) Error() string {
+    return strings.Join(explain(e.incompatibility), "\n")
+}
+
+// Is lets errors.Is() match ErrNoSolution
+func (e *NoSolutionError) Is(target error) bool {
+    return target == ErrNoSolution
+}
+
+// explainer turns the tree of incompatibilities that led to a failure
+// into sentences
+type explainer struct {
+    lines []explanationLine
+
+    // how many times each incompatibility is used to derive another one
+    uses map[*incompatibility]int
+
+    // which line each incompatibility was explained on, if it was given
+    // a number
+    numbers map[*incompatibility]int
+}
+
+// explanationLine is one sentence of the explanation
+type explanationLine struct {
+    text   string
+    number int // 0 if the line does not need one
+}
+
+// explain returns one sentence for each step that led to 'failure'
+func explain(failure *incompatibility) []string {
+    e := &explainer{
+        uses:    map[*incompatibility]int{},
+        numbers: map[*incompatibility]int{},
+    }
+    e.countUses(failure)
+
+    if failure.cause != causeConflict {
+        // we failed straight away
+        e.write(failure, capitalise(failure.String())+".", false)
+    } else {
+        e.visit(failure, true)
+    }
+
+    // line up the sentences, leaving room for the numbers
+    width := 0
+    for _, line := range e.lines {
+        if line.number != 0 {
+            width = len(fmt.Sprintf("(%d) ", line.number))
+        }
+    }
+
+    retval := make([]string, len(e.lines))
+    for i, line := range e.lines {
+        prefix := strings.Repeat(" ", width)
+        if line.number != 0 {
+            prefix = fmt.Sprintf("%-*s", width, fmt.Sprintf("(%d) ", line.number))
+        }
+        retval[i] = strings.TrimRight(prefix+line.text, " ")
+    }
+
+    return retval
+}
+
+// countUses works out how many times each incompatibility is used
+func (e *explainer) countUses(inc *incompatibility) {
+    if inc.cause != causeConflict {
+        return
+    }
+
+    for _, cause := range []*incompatibility{inc.left, inc.right} {
+        e.uses[cause]++
+        if e.uses[cause] == 1 {
+            e.countUses(cause)
+        }
+    }
+}
+
+// visit explains how 'inc' was derived
+//
+// the incompatibilities that it was derived from are explained first,
+// unless they have already been explained
+func (e *explainer) visit(inc *incompatibility, conclusion bool) {
+    numbered := e.uses[inc] > 1
+    prefix := ""
+    if conclusion && len(e.lines) > 0 {
+        prefix = "So, "
+    }
+    text := inc.String()
+
+    left, right := inc.left, inc.right
+    if left.cause != causeConflict && right.cause == causeConflict {
+        left, right = right, left
+    }
+
+    switch {
+    case left.cause == causeConflict && right.cause == causeConflict:
+        leftLine, leftDone := e.numbers[left]
+        rightLine, rightDone := e.numbers[right]
+        switch {
+        case leftDone && rightDone:
+            e.write(inc, fmt.Sprintf("%sbecause %s (%d) and %s (%d), %s.", prefix, left, leftLine, right, rightLine, text), numbered)
+        case leftDone:
+            e.visit(right, false)
+            e.write(inc, fmt.Sprintf("And because %s (%d), %s.", left, leftLine, text), numbered)
+        case rightDone:
+            e.visit(left, false)
+            e.write(inc, fmt.Sprintf("And because %s (%d), %s.", right, rightLine, text), numbered)
+        default:
+            e.visit(left, false)
+            e.visit(right, false)
+            e.write(inc, fmt.Sprintf("Thus, %s.", text), numbered)
+        }
+
+    case left.cause == causeConflict:
+        // 'left' was derived, 'right' is a fact
+        if line, ok := e.numbers[left]; ok {
+            e.write(inc, fmt.Sprintf("%sbecause %s and %s (%d), %s.", prefix, right, left, line, text), numbered)
+            return
+        }
+        e.visit(left, false)
+        e.write(inc, fmt.Sprintf("And because %s, %s.", right, text), numbered)
+
+    default:
+        e.write(inc, fmt.Sprintf("%sbecause %s and %s, %s.", prefix, left, right, text), numbered)
+    }
+}
+
+// write adds a sentence to the explanation
+func (e *explainer) write(inc *incompatibility, text string, numbered bool) {
+    line := explanationLine{text: capitalise(text)}
+    if numbered {
+        line.number = len(e.numbers) + 1
+        e.numbers[inc] = line.number
+    }
+    e.lines = append(e.lines, line)
+}
+
+// capitalise makes the first letter of a sentence upper case
+func capitalise(text string) string {
+    if text == "" {
+        return text
+    }
+
+    return strings.ToUpper(text[:1]) + text[1:]
+}
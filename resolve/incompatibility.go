@@ -0,0 +1,115 @@
+package resolve
+
+import (
+    "strings"
+)
+
+// where an incompatibility came from
+const (
+    causeRoot           = 0 // the root requirements must be met
+    causeDependency     = 1 // one package depends on another
+    causeNoVersions     = 2 // none of the package's versions are allowed
+    causeUnknownPackage = 3 // the registry has never heard of the package
+    causeConflict       = 4 // we learned it from two other incompatibilities
+)
+
+// incompatibility is a set of terms that cannot all be true at the same
+// time
+type incompatibility struct {
+    terms []term
+    cause int
+
+    // the two incompatibilities that we learned this one from, if cause
+    // is causeConflict
+    left  *incompatibility
+    right *incompatibility
+}
+
+// newIncompatibility creates an incompatibility, merging any terms that
+// are about the same package
+func newIncompatibility(terms []term, cause int, left *incompatibility, right *incompatibility) *incompatibility {
+    var merged []term
+    positions := map[string]int{}
+    for _, t := range terms {
+        if pos, ok := positions[t.pkg]; ok {
+            merged[pos] = merged[pos].intersect(t)
+            continue
+        }
+        positions[t.pkg] = len(merged)
+        merged = append(merged, t)
+    }
+
+    // we always pick the root package, so saying so does not add
+    // anything (except noise in the explanation)
+    if cause == causeConflict && len(merged) > 1 {
+        var kept []term
+        for _, t := range merged {
+            if !t.positive || t.pkg != rootPackage {
+                kept = append(kept, t)
+            }
+        }
+        merged = kept
+    }
+
+    return &incompatibility{terms: merged, cause: cause, left: left, right: right}
+}
+
+// isFailure returns true if the incompatibility proves that there is no
+// solution at all
+func (inc *incompatibility) isFailure() bool {
+    return len(inc.terms) == 0 ||
+        (len(inc.terms) == 1 && inc.terms[0].positive && inc.terms[0].pkg == rootPackage)
+}
+
+// String describes the incompatibility for humans
+func (inc *incompatibility) String() string {
+    switch inc.cause {
+    case causeRoot:
+        return "the root requirements must be met"
+    case causeDependency:
+        depender, dependee := inc.terms[0], inc.terms[1].inverse()
+        if depender.pkg == rootPackage {
+            return "the root requirements ask for " + dependee.String()
+        }
+        return depender.String() + " depends on " + dependee.String()
+    case causeNoVersions:
+        t := inc.terms[0]
+        if isSubset(anyVersion(), t.versions) {
+            return t.pkg + " has no versions"
+        }
+        return "no versions of " + t.pkg + " match " + t.versions.String()
+    case causeUnknownPackage:
+        return inc.terms[0].pkg + " does not exist"
+    }
+
+    if inc.isFailure() {
+        return "version solving failed"
+    }
+
+    if len(inc.terms) == 1 {
+        t := inc.terms[0]
+        if t.positive {
+            return t.String() + " is forbidden"
+        }
+        return t.inverse().String() + " is required"
+    }
+
+    if len(inc.terms) == 2 {
+        a, b := inc.terms[0], inc.terms[1]
+        switch {
+        case a.positive && b.positive:
+            return a.String() + " is incompatible with " + b.String()
+        case a.positive:
+            return a.String() + " requires " + b.inverse().String()
+        case b.positive:
+            return b.String() + " requires " + a.inverse().String()
+        }
+    }
+
+    parts := make([]string, len(inc.terms))
+    for i, t := range inc.terms {
+        parts[i] = t.String()
+    }
+
+    return "one of " + strings.Join(parts, " or ") + " must be false"
+}
@@ -0,0 +1,202 @@
+package resolve
+
+import (
+    "bufio"
+    "errors"
+    "fmt"
+    "io/fs"
+    "os"
+    "path/filepath"
+    "strings"
+
+    "github.com/stuartherbert/go_semver/semver"
+)
+
+// ========================================================================
+//
+// MemoryRegistry
+//
+// ------------------------------------------------------------------------
+
+// MemoryRegistry is a Registry that holds everything in memory
+//
+// create one by calling:
+//
+//     registry := resolve.NewMemoryRegistry()
+//     err := registry.Add("foo", "1.2.0", "bar ^2.0", "baz >=1.1")
+type MemoryRegistry struct {
+    packages map[string][]release
+}
+
+// release is one version of a package, and what it depends on
+type release struct {
+    version      semver.SemVersion
+    dependencies []Requirement
+}
+
+// NewMemoryRegistry creates an empty MemoryRegistry
+func NewMemoryRegistry() *MemoryRegistry {
+    return &MemoryRegistry{packages: map[string][]release{}}
+}
+
+// Add adds a version of a package, along with its dependencies
+//
+// each dependency is anything that ParseRequirement() accepts
+func (r *MemoryRegistry) Add(pkg string, version string, dependencies ...string) error {
+    v, err := semver.ParseVersion(version)
+    if err != nil {
+        return err
+    }
+    requirements, err := ParseRequirements(dependencies...)
+    if err != nil {
+        return err
+    }
+
+    r.packages[pkg] = append(r.packages[pkg], release{v, requirements})
+    return nil
+}
+
+// Versions returns every version of the package that has been added
+func (r *MemoryRegistry) Versions(pkg string) (semver.Versions, error) {
+    releases, ok := r.packages[pkg]
+    if !ok {
+        return nil, fmt.Errorf("%w: %s", ErrUnknownPackage, pkg)
+    }
+
+    retval := make(semver.Versions, len(releases))
+    for i, release := range releases {
+        retval[i] = release.version
+    }
+
+    return retval, nil
+}
+
+// Dependencies returns what this version of the package needs
+func (r *MemoryRegistry) Dependencies(pkg string, version semver.SemVersion) ([]Requirement, error) {
+    for _, release := range r.packages[pkg] {
+        if semver.Cmp(release.version, version) == 0 {
+            return release.dependencies, nil
+        }
+    }
+
+    return nil, fmt.Errorf("%w: %s %s", ErrUnknownVersion, pkg, version)
+}
+
+// ========================================================================
+//
+// DirRegistry
+//
+// ------------------------------------------------------------------------
+
+// DirRegistry is a Registry that reads packages from a directory tree
+//
+// each package is a directory, and each version of that package is a
+// file inside it, named after the version. The file lists the version's
+// dependencies, one per line:
+//
+//     registry/
+//         foo/
+//             1.0.0      <- contains 'bar ^2.0'
+//             1.1.0
+//         bar/
+//             2.0.0
+//
+// Blank lines, and lines starting with '#', are ignored. Files whose
+// names are not versions are ignored too, so you can keep a README
+// next to them.
+type DirRegistry struct {
+    dir string
+}
+
+// NewDirRegistry creates a DirRegistry that reads from 'dir'
+func NewDirRegistry(dir string) *DirRegistry {
+    return &DirRegistry{dir: dir}
+}
+
+// Versions returns every version of the package found on disk
+func (r *DirRegistry) Versions(pkg string) (semver.Versions, error) {
+    files, err := r.files(pkg)
+    if err != nil {
+        return nil, err
+    }
+
+    retval := make(semver.Versions, 0, len(files))
+    for _, v := range files {
+        retval = append(retval, v)
+    }
+
+    return retval, nil
+}
+
+// Dependencies reads the dependencies of this version of the package
+func (r *DirRegistry) Dependencies(pkg string, version semver.SemVersion) ([]Requirement, error) {
+    files, err := r.files(pkg)
+    if err != nil {
+        return nil, err
+    }
+
+    for name, v := range files {
+        if semver.Cmp(v, version) == 0 {
+            return readRequirements(filepath.Join(r.dir, filepath.FromSlash(pkg), name))
+        }
+    }
+
+    return nil, fmt.Errorf("%w: %s %s", ErrUnknownVersion, pkg, version)
+}
+
+// files returns the versions found in a package's directory, keyed by
+// file name
+func (r *DirRegistry) files(pkg string) (map[string]semver.SemVersion, error) {
+    // package names must not escape from our directory
+    if !fs.ValidPath(pkg) || pkg == "." {
+        return nil, fmt.Errorf("%w: %s", ErrUnknownPackage, pkg)
+    }
+
+    entries, err := os.ReadDir(filepath.Join(r.dir, filepath.FromSlash(pkg)))
+    if errors.Is(err, fs.ErrNotExist) {
+        return nil, fmt.Errorf("%w: %s", ErrUnknownPackage, pkg)
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    retval := map[string]semver.SemVersion{}
+    for _, entry := range entries {
+        if entry.IsDir() {
+            continue
+        }
+        v, err := semver.ParseVersion(entry.Name())
+        if err != nil {
+            continue
+        }
+        retval[entry.Name()] = v
+    }
+
+    return retval, nil
+}
+
+// readRequirements reads a list of requirements from a file
+func readRequirements(path string) ([]Requirement, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+
+    var retval []Requirement
+    scanner := bufio.NewScanner(f)
+    for lineNo := 1; scanner.Scan(); lineNo++ {
+        line := strings.TrimSpace(scanner.Text())
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+
+        requirement, err := ParseRequirement(line)
+        if err != nil {
+            return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+        }
+        retval = append(retval, requirement)
+    }
+
+    return retval, scanner.Err()
+}
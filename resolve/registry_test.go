@@ -0,0 +1,131 @@
+package resolve
+
+import (
+    "errors"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/stuartherbert/go_semver/semver"
+)
+
+// writeFile creates a file in our example registry
+func writeFile(t *testing.T, dir string, name string, contents string) {
+    path := filepath.Join(dir, filepath.FromSlash(name))
+    if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+        t.Fatal(err)
+    }
+}
+
+// ========================================================================
+//
+// Tests for MemoryRegistry
+//
+// ------------------------------------------------------------------------
+
+func TestMemoryRegistryRejectsBadInput(t *testing.T) {
+    registry := NewMemoryRegistry()
+
+    // perform the test
+    err1 := registry.Add("foo", "banana")
+    err2 := registry.Add("foo", "1.0.0", "bar")
+
+    // was an error returned?
+    var parseErr *semver.ParseError
+    if !errors.As(err1, &parseErr) {
+        t.Errorf("Expected a *semver.ParseError, received %v", err1)
+        return
+    }
+    if !errors.Is(err2, ErrInvalidRequirement) {
+        t.Errorf("Expected ErrInvalidRequirement, received %v", err2)
+        return
+    }
+}
+
+func TestMemoryRegistryReportsUnknownPackages(t *testing.T) {
+    registry := makeRegistry(t, "foo 1.0.0")
+
+    // perform the test
+    _, err1 := registry.Versions("bar")
+    _, err2 := registry.Dependencies("foo", semver.SemVersion{Major: 2})
+
+    // was an error returned?
+    if !errors.Is(err1, ErrUnknownPackage) {
+        t.Errorf("Expected ErrUnknownPackage, received %v", err1)
+        return
+    }
+    if !errors.Is(err2, ErrUnknownVersion) {
+        t.Errorf("Expected ErrUnknownVersion, received %v", err2)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for DirRegistry
+//
+// ------------------------------------------------------------------------
+
+func TestCanSolveFromDirRegistry(t *testing.T) {
+    dir := t.TempDir()
+    writeFile(t, dir, "foo/1.0.0", "bar ^1.0\n")
+    writeFile(t, dir, "foo/1.1", "# needs the new API\nbar ^2.0\n\nbaz >=1.2\n")
+    writeFile(t, dir, "foo/README.md", "not a version\n")
+    writeFile(t, dir, "bar/2.3.0", "")
+    writeFile(t, dir, "bar/1.0.0", "")
+    writeFile(t, dir, "baz/1.2.0", "")
+    writeFile(t, dir, "github.com/example/qux/0.4.0", "foo ~1.1\n")
+
+    // perform the test
+    actual, err := solve(t, NewDirRegistry(dir), "github.com/example/qux ^0.4")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    expected := map[string]string{"github.com/example/qux": "0.4.0", "foo": "1.1.0", "bar": "2.3.0", "baz": "1.2.0"}
+    if len(actual) != len(expected) {
+        t.Errorf("Expected %v, received %v", expected, actual)
+        return
+    }
+    for pkg, version := range expected {
+        if actual[pkg].String() != version {
+            t.Errorf("Expected %v, received %v", expected, actual)
+            return
+        }
+    }
+}
+
+func TestDirRegistryReportsProblems(t *testing.T) {
+    dir := t.TempDir()
+    writeFile(t, dir, "foo/1.0.0", "bar ^1.0\nbar\n")
+
+    registry := NewDirRegistry(dir)
+
+    // perform the test
+    _, err1 := registry.Versions("bar")
+    _, err2 := registry.Versions("../foo")
+    _, err3 := registry.Dependencies("foo", semver.SemVersion{Major: 2})
+    _, err4 := registry.Dependencies("foo", semver.SemVersion{Major: 1})
+
+    // was an error returned?
+    if !errors.Is(err1, ErrUnknownPackage) || !errors.Is(err2, ErrUnknownPackage) {
+        t.Errorf("Expected ErrUnknownPackage, received %v and %v", err1, err2)
+        return
+    }
+    if !errors.Is(err3, ErrUnknownVersion) {
+        t.Errorf("Expected ErrUnknownVersion, received %v", err3)
+        return
+    }
+    if !errors.Is(err4, ErrInvalidRequirement) || !strings.Contains(err4.Error(), "1.0.0:2: ") {
+        t.Errorf("Expected ErrInvalidRequirement on line 2, received %v", err4)
+        return
+    }
+}
@@ -0,0 +1,122 @@
+// Package resolve picks a version of every package that a set of
+// requirements needs, so that all of their dependencies are satisfied
+//
+// It uses the PubGrub algorithm (the one that Dart's pub and Cargo use):
+// it tries the newest version of each package in turn, and when it hits a
+// conflict, it works out why, remembers that as a new rule, and jumps
+// back to the decision that caused it. When there is no solution at all,
+// those rules are used to explain what went wrong:
+//
+//     registry := resolve.NewMemoryRegistry()
+//     registry.Add("foo", "1.0.0", "bar ^2.0")
+//     registry.Add("bar", "2.1.0")
+//
+//     requirements, _ := resolve.ParseRequirements("foo ~1.0")
+//     solution, err := resolve.Solve(registry, requirements)
+//     solution["bar"] // 2.1.0
+//
+// Packages and their dependencies come from a Registry. This package
+// ships with a MemoryRegistry and a DirRegistry; anything else can be
+// plugged in by implementing the Registry interface.
+//
+// Each package's allowed versions are tracked as a semver.Range. A range
+// can contain a few more pre-releases than the expression that it came
+// from, so Solve() leaves out any of the package's versions that
+// VersionExpression.MatchesVersion() rejects; it never picks a version
+// that a requirement does not match. Solve() also picks the newest stable
+// version that it can, and only picks a pre-release or an unstable release
+// when nothing else will do.
+package resolve
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/stuartherbert/go_semver/semver"
+)
+
+// returned (wrapped) by a Registry when it has never heard of a package
+var ErrUnknownPackage = fmt.Errorf("unknown package")
+
+// returned (wrapped) by a Registry when it does not have a version of a
+// package
+var ErrUnknownVersion = fmt.Errorf("unknown version")
+
+// returned by ParseRequirement() when a requirement is not of the form
+// '<package> <expression>'
+var ErrInvalidRequirement = fmt.Errorf("invalid requirement")
+
+// Requirement says which versions of a package are acceptable
+type Requirement struct {
+    Package    string
+    Expression semver.VersionExpression
+}
+
+// Registry is where Solve() finds out about packages
+type Registry interface {
+    // Versions returns every version of the package, in any order
+    //
+    // returns an error that wraps ErrUnknownPackage if the package does
+    // not exist
+    Versions(pkg string) (semver.Versions, error)
+
+    // Dependencies returns what this version of the package needs
+    Dependencies(pkg string, version semver.SemVersion) ([]Requirement, error)
+}
+
+// Solution holds the version picked for each package, keyed by package
+// name
+type Solution map[string]semver.SemVersion
+
+// ParseRequirement converts a string of the form '<package> <expression>'
+// (e.g. 'foo >=1.2') into a Requirement
+//
+// <expression> is anything that semver.ParseExpression() accepts
+func ParseRequirement(raw string) (Requirement, error) {
+    pkg, exp, _ := strings.Cut(strings.TrimSpace(raw), " ")
+    exp = strings.TrimSpace(exp)
+    if pkg == "" || exp == "" {
+        return Requirement{}, fmt.Errorf("%w: %q", ErrInvalidRequirement, raw)
+    }
+
+    expression, err := semver.ParseExpression(exp)
+    if err != nil {
+        return Requirement{}, err
+    }
+
+    return Requirement{Package: pkg, Expression: expression}, nil
+}
+
+// ParseRequirements converts a list of strings into Requirements
+//
+// returns the first error that ParseRequirement() returns
+func ParseRequirements(raw ...string) ([]Requirement, error) {
+    retval := make([]Requirement, 0, len(raw))
+    for _, item := range raw {
+        requirement, err := ParseRequirement(item)
+        if err != nil {
+            return nil, err
+        }
+        retval = append(retval, requirement)
+    }
+
+    return retval, nil
+}
+
+// String returns the requirement in the form that ParseRequirement()
+// accepts
+func (r Requirement) String() string {
+    return r.Package + " " + r.Expression.String()
+}
+
+// Solve picks a version of every package that the requirements need,
+// directly or indirectly
+//
+// returns a *NoSolutionError (which wraps ErrNoSolution) that explains
+// why, if there is no set of versions that satisfies everything; any
+// other error comes from the Registry, or from a requirement that does
+// not describe a range of versions (e.g. 'foo @main')
+func Solve(registry Registry, requirements []Requirement) (Solution, error) {
+    s := newSolver(registry, requirements)
+    return s.solve()
+}
@@ -0,0 +1,417 @@
+package resolve
+
+import (
+    "errors"
+    "fmt"
+    "sort"
+    "strings"
+    "testing"
+
+    "github.com/stuartherbert/go_semver/semver"
+)
+
+// makeRegistry builds a MemoryRegistry from a list of releases, each of
+// the form 'pkg version[: requirement, requirement ...]'
+func makeRegistry(t *testing.T, releases ...string) *MemoryRegistry {
+    t.Helper()
+
+    registry := NewMemoryRegistry()
+    for _, raw := range releases {
+        name, deps, _ := strings.Cut(raw, ":")
+        pkg, version, _ := strings.Cut(name, " ")
+
+        var requirements []string
+        for _, dep := range strings.Split(deps, ",") {
+            if strings.TrimSpace(dep) != "" {
+                requirements = append(requirements, dep)
+            }
+        }
+        if err := registry.Add(pkg, strings.TrimSpace(version), requirements...); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    return registry
+}
+
+// solve runs Solve() against a registry, with requirements given as
+// strings
+func solve(t *testing.T, registry Registry, requirements ...string) (Solution, error) {
+    t.Helper()
+
+    parsed, err := ParseRequirements(requirements...)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return Solve(registry, parsed)
+}
+
+// ========================================================================
+//
+// Tests for ParseRequirement()
+//
+// ------------------------------------------------------------------------
+
+func TestCanParseRequirements(t *testing.T) {
+    // our list of requirements, and what we expect them to become
+    var toParse = []struct {
+        raw      string
+        expected string
+    }{
+        {"foo >=1.2", "foo >=1.2.0"},
+        {"  foo   ~1.4  ", "foo ~1.4.0"},
+        {"github.com/a/b 1.x", "github.com/a/b 1.x"},
+    }
+
+    for _, set := range toParse {
+        // perform the test
+        actual, err := ParseRequirement(set.raw)
+
+        // was an error returned?
+        if err != nil {
+            t.Errorf("%q: %v", set.raw, err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual.String() != set.expected {
+            t.Errorf("%q: expected %q, received %q", set.raw, set.expected, actual)
+            return
+        }
+    }
+}
+
+func TestParseRequirementRejectsInvalidRequirements(t *testing.T) {
+    // our list of requirements that have a missing part
+    var toParse = []string{"", "   ", "foo", " foo  "}
+
+    for _, raw := range toParse {
+        // perform the test
+        _, err := ParseRequirement(raw)
+
+        // was an error returned?
+        if !errors.Is(err, ErrInvalidRequirement) {
+            t.Errorf("%q: expected ErrInvalidRequirement, received %v", raw, err)
+            return
+        }
+    }
+
+    // bad expressions are reported by the semver package
+    _, err := ParseRequirement("foo >=banana")
+    var parseErr *semver.ParseError
+    if !errors.As(err, &parseErr) {
+        t.Errorf("Expected a *semver.ParseError, received %v", err)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for Solve()
+//
+// ------------------------------------------------------------------------
+
+func TestSolveFindsSolutions(t *testing.T) {
+    // our list of registries and requirements, and what we expect
+    var toSolve = []struct {
+        name         string
+        releases     []string
+        requirements []string
+        expected     string
+    }{
+        {
+            "no conflicts",
+            []string{"foo 1.0.0: bar ^1.0.0", "bar 1.0.0: baz ^1.0.0", "bar 1.1.0: baz ^1.0.0", "baz 1.0.0", "baz 1.2.0", "baz 2.0.0"},
+            []string{"foo ^1.0.0"},
+            "bar 1.1.0, baz 1.2.0, foo 1.0.0",
+        },
+        {
+            "avoiding a conflict while deciding",
+            []string{"foo 1.0.0", "foo 1.1.0: bar ^2.0.0", "bar 1.0.0", "bar 1.1.0", "bar 2.0.0"},
+            []string{"foo ^1.0.0", "bar ^1.0.0"},
+            "bar 1.1.0, foo 1.0.0",
+        },
+        {
+            "conflict resolution",
+            []string{"foo 1.0.0", "foo 2.0.0: bar ^1.0.0", "bar 1.0.0: foo ^1.0.0"},
+            []string{"foo >=1.0.0"},
+            "foo 1.0.0",
+        },
+        {
+            "conflict resolution with a partial satisfier",
+            []string{
+                "foo 1.0.0", "foo 1.1.0: left ^1.0.0, right ^1.0.0",
+                "left 1.0.0: shared >=1.0.0", "right 1.0.0: shared <2.0.0",
+                "shared 2.0.0", "shared 1.0.0: target ^1.0.0",
+                "target 1.0.0", "target 2.0.0",
+            },
+            []string{"foo ^1.0.0", "target ^2.0.0"},
+            "foo 1.0.0, target 2.0.0",
+        },
+        {
+            "backjumping over unrelated packages",
+            []string{"a 1.0.0: c =1.0.0", "a 2.0.0: c =2.0.0", "b 1.0.0", "b 2.0.0", "b 3.0.0", "c 1.0.0"},
+            []string{"a *", "b *"},
+            "a 1.0.0, b 3.0.0, c 1.0.0",
+        },
+        {
+            "requirements on the same package are combined",
+            []string{"foo 1.0.0", "foo 1.5.0", "foo 2.0.0"},
+            []string{"foo >=1.0", "foo <2.0"},
+            "foo 1.5.0",
+        },
+        {
+            "stable versions are preferred",
+            []string{"foo 1.0.0", "foo 1.1.0-beta.1", "foo 1.1.0-alpha-2"},
+            []string{"foo ^1.0.0"},
+            "foo 1.0.0",
+        },
+        {
+            "pre-releases are used when the requirement matches them",
+            []string{"foo 1.0.0", "foo 1.1.0-beta.1", "foo 1.1.0-beta.2"},
+            []string{"foo ^1.1.0-beta.1"},
+            "foo 1.1.0-beta.2",
+        },
+        {
+            "pre-releases that a requirement does not match are skipped",
+            []string{"foo 1.3.0-rc.1", "bar 1.0.0: foo >=1.3.0-rc.1", "bar 1.1.0: foo ^1.2.0"},
+            []string{"bar ^1.0"},
+            "bar 1.0.0, foo 1.3.0-rc.1",
+        },
+    }
+
+    for _, set := range toSolve {
+        // perform the test
+        actual, err := solve(t, makeRegistry(t, set.releases...), set.requirements...)
+
+        // was an error returned?
+        if err != nil {
+            t.Errorf("%s: %v", set.name, err)
+            return
+        }
+
+        // did we get back what we expected?
+        var picked []string
+        for pkg, version := range actual {
+            picked = append(picked, pkg+" "+version.String())
+        }
+        sort.Strings(picked)
+        if strings.Join(picked, ", ") != set.expected {
+            t.Errorf("%s: expected %q, received %q", set.name, set.expected, strings.Join(picked, ", "))
+            return
+        }
+    }
+}
+
+func TestSolveExplainsFailures(t *testing.T) {
+    // our list of registries and requirements, and the explanations we
+    // expect
+    var toSolve = []struct {
+        name         string
+        releases     []string
+        requirements []string
+        expected     []string
+    }{
+        {
+            "no matching versions",
+            []string{"foo 1.0.0: bar ^2.0", "bar 1.5.0"},
+            []string{"foo ~1.0"},
+            []string{
+                "Because any version of foo depends on bar >=2.0.0, <3.0.0-0 and no versions of bar match >=2.0.0, <3.0.0-0, any version of foo is forbidden.",
+                "And because the root requirements ask for foo >=1.0.0, <2.0.0-0, version solving failed.",
+            },
+        },
+        {
+            "only pre-releases that caret does not match",
+            []string{"foo 1.3.0-rc.1"},
+            []string{"foo ^1.2.0"},
+            []string{
+                "Because no versions of foo match >=1.2.0, <1.3.0-rc.1 || >1.3.0-rc.1, <2.0.0-0 and the root requirements ask for foo >=1.2.0, <1.3.0-rc.1 || >1.3.0-rc.1, <2.0.0-0, version solving failed.",
+            },
+        },
+        {
+            "only pre-releases of a dependency",
+            []string{"foo 1.0.0: bar >=1.0", "bar 1.5.0-alpha-1"},
+            []string{"foo ^1.0"},
+            []string{
+                "Because any version of foo depends on bar >=1.0.0, <1.5.0-alpha-1 || >1.5.0-alpha-1 and no versions of bar match >=1.0.0, <1.5.0-alpha-1 || >1.5.0-alpha-1, any version of foo is forbidden.",
+                "And because the root requirements ask for foo >=1.0.0, <2.0.0-0, version solving failed.",
+            },
+        },
+        {
+            "unknown package",
+            []string{"foo 1.0.0"},
+            []string{"bar ^1.0"},
+            []string{
+                "Because bar does not exist and the root requirements ask for bar >=1.0.0, <2.0.0-0, version solving failed.",
+            },
+        },
+        {
+            "linear conflict",
+            []string{"foo 1.0.0: baz ^1.0.0", "bar 2.0.0: baz ^3.0.0", "baz 1.0.0", "baz 3.0.0"},
+            []string{"foo ^1.0.0", "bar ^2.0.0"},
+            []string{
+                "Because any version of bar depends on baz >=3.0.0, <4.0.0-0 and any version of foo depends on baz >=1.0.0, <2.0.0-0, any version of bar is incompatible with any version of foo.",
+                "And because the root requirements ask for foo >=1.0.0, <2.0.0-0, any version of bar is forbidden.",
+                "And because the root requirements ask for bar >=2.0.0, <3.0.0-0, version solving failed.",
+            },
+        },
+        {
+            "branching conflict",
+            []string{
+                "foo 1.0.0: a ^1.0.0, b ^1.0.0", "foo 1.1.0: x ^1.0.0, y ^1.0.0",
+                "a 1.0.0: b ^2.0.0", "b 1.0.0", "b 2.0.0",
+                "x 1.0.0: y ^2.0.0", "y 1.0.0", "y 2.0.0",
+            },
+            []string{"foo ^1.0.0"},
+            []string{
+                "Because any version of a depends on b >=2.0.0, <3.0.0-0 and foo <1.1.0 depends on a >=1.0.0, <2.0.0-0, foo <1.1.0 requires b >=2.0.0, <3.0.0-0.",
+                "And because foo <1.1.0 depends on b >=1.0.0, <2.0.0-0, foo <1.1.0 is forbidden.",
+                "Because any version of x depends on y >=2.0.0, <3.0.0-0 and foo >1.0.0 depends on x >=1.0.0, <2.0.0-0, foo >1.0.0 requires y >=2.0.0, <3.0.0-0.",
+                "And because foo >1.0.0 depends on y >=1.0.0, <2.0.0-0, foo >1.0.0 is forbidden.",
+                "Thus, any version of foo is forbidden.",
+                "And because the root requirements ask for foo >=1.0.0, <2.0.0-0, version solving failed.",
+            },
+        },
+    }
+
+    for _, set := range toSolve {
+        // perform the test
+        _, err := solve(t, makeRegistry(t, set.releases...), set.requirements...)
+
+        // was an error returned?
+        var noSolution *NoSolutionError
+        if !errors.As(err, &noSolution) || !errors.Is(err, ErrNoSolution) {
+            t.Errorf("%s: expected a *NoSolutionError, received %v", set.name, err)
+            return
+        }
+
+        // did we get back what we expected?
+        expected := strings.Join(set.expected, "\n")
+        if err.Error() != expected {
+            t.Errorf("%s: expected\n%s\nreceived\n%s", set.name, expected, err)
+            return
+        }
+    }
+}
+
+func TestSolveNumbersStepsThatAreUsedTwice(t *testing.T) {
+    registry := makeRegistry(t,
+        "a 1.0.0: c >=1.0, e !=1.0.0",
+        "b 1.0.0: d ^2.0", "b 2.0.0: d >=2.0",
+        "c 1.0.0: a ^1.0, b !=3.0.0, d ^3.0, e ^2.0", "c 3.0.0",
+        "d 2.0.0: a >=1.0, c ^1.0, e <3.0", "d 3.0.0: a !=1.0.0, b <1.0",
+        "e 1.0.0: a !=1.0.0, c ^1.0", "e 2.0.0", "e 3.0.0",
+    )
+
+    // what result do we expect?
+    expected := strings.Join([]string{
+        "(1) Because d <3.0.0 depends on c >=1.0.0, <2.0.0-0 and c <3.0.0 depends on d >=3.0.0, <4.0.0-0, d <3.0.0 is forbidden.",
+        "    And because b <2.0.0 depends on d >=2.0.0, <3.0.0-0, b <2.0.0 is forbidden.",
+        "    Because d >2.0.0 depends on b <1.0.0 and d <3.0.0 is forbidden (1), any version of d requires b <1.0.0.",
+        "    And because b >1.0.0 depends on d >=2.0.0, b >1.0.0 is forbidden.",
+        "    Thus, any version of b is forbidden.",
+        "    And because the root requirements ask for b <3.0.0, version solving failed.",
+    }, "\n")
+
+    // perform the test
+    _, err := solve(t, registry, "a >=1.0", "b <3.0")
+
+    // was an error returned?
+    if !errors.Is(err, ErrNoSolution) {
+        t.Errorf("Expected ErrNoSolution, received %v", err)
+        return
+    }
+
+    // did we get back what we expected?
+    if err.Error() != expected {
+        t.Errorf("Expected\n%s\nreceived\n%s", expected, err)
+        return
+    }
+}
+
+// failingRegistry is a Registry that cannot be read
+type failingRegistry struct{}
+
+var errRegistryOffline = fmt.Errorf("registry is offline")
+
+func (failingRegistry) Versions(pkg string) (semver.Versions, error) {
+    return nil, errRegistryOffline
+}
+
+func (failingRegistry) Dependencies(pkg string, version semver.SemVersion) ([]Requirement, error) {
+    return nil, errRegistryOffline
+}
+
+func TestSolveReturnsRegistryErrors(t *testing.T) {
+    // perform the test
+    _, err := solve(t, failingRegistry{}, "foo ^1.0")
+
+    // was an error returned?
+    if !errors.Is(err, errRegistryOffline) {
+        t.Errorf("Expected the registry's error, received %v", err)
+        return
+    }
+}
+
+func TestSolveRejectsReferences(t *testing.T) {
+    registry := makeRegistry(t, "foo 1.0.0: bar @main", "bar 1.0.0")
+
+    // perform the test
+    _, err := solve(t, registry, "foo ^1.0")
+
+    // was an error returned?
+    if !errors.Is(err, semver.ErrNotARange) {
+        t.Errorf("Expected ErrNotARange, received %v", err)
+        return
+    }
+}
+
+// countingRegistry counts how often each version's dependencies are
+// asked for, and cannot read the dependencies of the versions in 'broken'
+type countingRegistry struct {
+    Registry
+    broken map[string]bool
+    calls  map[string]int
+}
+
+func (r *countingRegistry) Dependencies(pkg string, version semver.SemVersion) ([]Requirement, error) {
+    key := pkg + " " + version.String()
+    r.calls[key]++
+    if r.broken[key] {
+        return nil, errRegistryOffline
+    }
+
+    return r.Registry.Dependencies(pkg, version)
+}
+
+func TestSolveAsksForDependenciesOnce(t *testing.T) {
+    registry := &countingRegistry{
+        Registry: makeRegistry(t,
+            "foo 1.0.0: bar ^1.0", "foo 1.1.0: bar ^1.0", "foo 1.2.0: bar ^1.0, baz ^1.0",
+            "foo 1.3.0: bar ^2.0", "foo 1.4.0: bar ^2.0, baz ^1.0",
+            "bar 1.0.0", "bar 2.0.0: baz ^1.0",
+            "baz 1.0.0", "baz 2.0.0",
+        ),
+        broken: map[string]bool{"foo 1.3.0": true},
+        calls:  map[string]int{},
+    }
+
+    // perform the test
+    _, err := solve(t, registry, "foo ^1.0")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    for version, calls := range registry.calls {
+        if calls > 1 {
+            t.Errorf("%s: expected 1 call to Dependencies(), received %d", version, calls)
+            return
+        }
+    }
+}
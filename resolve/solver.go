@@ -0,0 +1,606 @@
+package resolve
+
+import (
+    "errors"
+    "fmt"
+
+    "github.com/stuartherbert/go_semver/semver"
+)
+
+// the name we use for the requirements passed into Solve(); it can never
+// clash with a real package, because ParseRequirement() does not accept
+// an empty package name
+const rootPackage = ""
+
+// assignment is one step in our partial solution: either a version that
+// we have decided to try, or something that we have derived from an
+// incompatibility
+type assignment struct {
+    term
+    level    int              // how many decisions had been made at the time
+    decision bool             // true if we chose this, rather than derived it
+    cause    *incompatibility // why we derived it; nil for decisions
+    index    int              // where it is in the partial solution
+}
+
+// what propagateIncompatibility() found
+const (
+    propagateNone     = 0 // nothing new
+    propagateDerived  = 1 // we derived something about a package
+    propagateConflict = 2 // the partial solution satisfies the whole incompatibility
+)
+
+// solver holds the state of one call to Solve()
+type solver struct {
+    registry     Registry
+    requirements []Requirement
+
+    // everything we have learned so far, indexed by package
+    incompatibilities map[string][]*incompatibility
+
+    // the partial solution
+    assignments []assignment
+    decisions   map[string]semver.SemVersion
+
+    // the intersection of all the assignments for each package
+    terms map[string]term
+
+    // what we have already asked the registry
+    versions map[string]semver.Versions
+    ranges   map[string]requirementRanges
+}
+
+// requirementRanges holds what one version of a package requires
+type requirementRanges struct {
+    order  []string                // the packages required, in the order they were listed
+    ranges map[string]semver.Range // the versions required of each package
+    err    error                   // why we could not work them out
+}
+
+// newSolver creates a solver for the given requirements
+func newSolver(registry Registry, requirements []Requirement) *solver {
+    return &solver{
+        registry:          registry,
+        requirements:      requirements,
+        incompatibilities: map[string][]*incompatibility{},
+        decisions:         map[string]semver.SemVersion{},
+        terms:             map[string]term{},
+        versions:          map[string]semver.Versions{},
+        ranges:            map[string]requirementRanges{},
+    }
+}
+
+// solve is the main loop: propagate what we know, then decide on the
+// next package's version, until there is nothing left to decide
+func (s *solver) solve() (Solution, error) {
+    root := term{rootPackage, exactly(semver.SemVersion{}), false}
+    s.addIncompatibility(newIncompatibility([]term{root}, causeRoot, nil, nil))
+
+    next := rootPackage
+    for {
+        if err := s.propagate(next); err != nil {
+            return nil, err
+        }
+
+        var err error
+        var ok bool
+        next, ok, err = s.decide()
+        if err != nil {
+            return nil, err
+        }
+        if !ok {
+            break
+        }
+    }
+
+    retval := Solution{}
+    for pkg, version := range s.decisions {
+        if pkg != rootPackage {
+            retval[pkg] = version
+        }
+    }
+
+    return retval, nil
+}
+
+// addIncompatibility remembers an incompatibility against each of the
+// packages that it mentions
+func (s *solver) addIncompatibility(inc *incompatibility) {
+    for _, t := range inc.terms {
+        s.incompatibilities[t.pkg] = append(s.incompatibilities[t.pkg], inc)
+    }
+}
+
+// ========================================================================
+//
+// The partial solution
+//
+// ------------------------------------------------------------------------
+
+// assign adds an assignment to the partial solution
+func (s *solver) assign(a assignment) {
+    a.index = len(s.assignments)
+    s.assignments = append(s.assignments, a)
+    if existing, ok := s.terms[a.pkg]; ok {
+        s.terms[a.pkg] = existing.intersect(a.term)
+        return
+    }
+    s.terms[a.pkg] = a.term
+}
+
+// derive adds something that we have learned from an incompatibility
+func (s *solver) derive(t term, cause *incompatibility) {
+    s.assign(assignment{term: t, level: len(s.decisions), cause: cause})
+}
+
+// decideVersion adds a decision to the partial solution
+func (s *solver) decideVersion(pkg string, version semver.SemVersion) {
+    s.decisions[pkg] = version
+    s.assign(assignment{term: term{pkg, exactly(version), true}, level: len(s.decisions), decision: true})
+}
+
+// backtrack throws away every assignment made after the given decision
+// level
+func (s *solver) backtrack(level int) {
+    var kept []assignment
+    for _, a := range s.assignments {
+        if a.level <= level {
+            kept = append(kept, a)
+        }
+    }
+
+    s.assignments = nil
+    s.decisions = map[string]semver.SemVersion{}
+    s.terms = map[string]term{}
+    for _, a := range kept {
+        if a.decision {
+            s.decisions[a.pkg] = a.term.versions.Intervals[0].Lower.Version
+        }
+        s.assign(a)
+    }
+}
+
+// relation works out how 't' relates to the partial solution
+func (s *solver) relation(t term) int {
+    existing, ok := s.terms[t.pkg]
+    if !ok {
+        return relationInconclusive
+    }
+
+    return existing.relation(t)
+}
+
+// satisfier returns the earliest assignment that, together with the
+// assignments before it, satisfies 't'
+func (s *solver) satisfier(t term) assignment {
+    var sofar term
+    seen := false
+    for _, a := range s.assignments {
+        if a.pkg != t.pkg {
+            continue
+        }
+        if seen {
+            sofar = sofar.intersect(a.term)
+        } else {
+            sofar = a.term
+            seen = true
+        }
+        if sofar.satisfies(t) {
+            return a
+        }
+    }
+
+    // we only ever look for satisfiers of terms that the partial
+    // solution satisfies
+    panic(fmt.Sprintf("resolve: %s is not satisfied", t))
+}
+
+// ========================================================================
+//
+// Unit propagation and conflict resolution
+//
+// ------------------------------------------------------------------------
+
+// propagate derives everything that it can from the incompatibilities,
+// starting with those that mention 'pkg'
+func (s *solver) propagate(pkg string) error {
+    changed := []string{pkg}
+    for len(changed) > 0 {
+        next := changed[len(changed)-1]
+        changed = changed[:len(changed)-1]
+
+        // newest first, because they are the most useful
+        incompatibilities := s.incompatibilities[next]
+        for i := len(incompatibilities) - 1; i >= 0; i-- {
+            derived, result := s.propagateIncompatibility(incompatibilities[i])
+            if result == propagateConflict {
+                rootCause, err := s.resolveConflict(incompatibilities[i])
+                if err != nil {
+                    return err
+                }
+
+                // after backtracking, the root cause tells us something new
+                derived, _ = s.propagateIncompatibility(rootCause)
+                changed = []string{derived}
+                break
+            }
+            if result == propagateDerived {
+                changed = append(changed, derived)
+            }
+        }
+    }
+
+    return nil
+}
+
+// propagateIncompatibility derives the one term that the partial solution
+// leaves undecided, if there is only one
+//
+// returns the package that we derived something about, and one of the
+// propagate* values
+func (s *solver) propagateIncompatibility(inc *incompatibility) (string, int) {
+    var unsatisfied *term
+    for i, t := range inc.terms {
+        switch s.relation(t) {
+        case relationContradicted:
+            return "", propagateNone
+        case relationInconclusive:
+            if unsatisfied != nil {
+                return "", propagateNone
+            }
+            unsatisfied = &inc.terms[i]
+        }
+    }
+
+    if unsatisfied == nil {
+        return "", propagateConflict
+    }
+
+    s.derive(unsatisfied.inverse(), inc)
+    return unsatisfied.pkg, propagateDerived
+}
+
+// resolveConflict works backwards from an incompatibility that the
+// partial solution satisfies, learning new incompatibilities as it goes,
+// until it finds one that lets us backtrack
+//
+// returns a *NoSolutionError if it proves that there is no solution
+func (s *solver) resolveConflict(inc *incompatibility) (*incompatibility, error) {
+    learned := false
+    for !inc.isFailure() {
+        var mostRecentTerm term
+        var mostRecentSatisfier assignment
+        var difference *term
+        found := false
+        previousLevel := 1
+
+        for _, t := range inc.terms {
+            satisfier := s.satisfier(t)
+            if !found || satisfier.index > mostRecentSatisfier.index {
+                if found {
+                    previousLevel = maxInt(previousLevel, mostRecentSatisfier.level)
+                }
+                mostRecentTerm, mostRecentSatisfier, found = t, satisfier, true
+                difference = nil
+
+                // the satisfier may say more than the term does; if so,
+                // the rest of what it says must have been satisfied
+                // before we can backtrack past it
+                if !satisfier.decision {
+                    diff := satisfier.term.difference(t)
+                    if !diff.isEmpty() {
+                        difference = &diff
+                        previousLevel = maxInt(previousLevel, s.satisfier(diff.inverse()).level)
+                    }
+                }
+                continue
+            }
+            previousLevel = maxInt(previousLevel, satisfier.level)
+        }
+
+        if mostRecentSatisfier.decision || previousLevel < mostRecentSatisfier.level {
+            s.backtrack(previousLevel)
+            if learned {
+                s.addIncompatibility(inc)
+            }
+            return inc, nil
+        }
+
+        // combine this incompatibility with the one that caused the
+        // satisfier, to learn a new one
+        var terms []term
+        for _, t := range inc.terms {
+            if t.pkg != mostRecentTerm.pkg {
+                terms = append(terms, t)
+            }
+        }
+        for _, t := range mostRecentSatisfier.cause.terms {
+            if t.pkg != mostRecentSatisfier.pkg {
+                terms = append(terms, t)
+            }
+        }
+        if difference != nil {
+            terms = append(terms, difference.inverse())
+        }
+
+        inc = newIncompatibility(terms, causeConflict, inc, mostRecentSatisfier.cause)
+        learned = true
+    }
+
+    return nil, &NoSolutionError{incompatibility: inc}
+}
+
+// ========================================================================
+//
+// Decision making
+//
+// ------------------------------------------------------------------------
+
+// decide picks a version for the next package that needs one
+//
+// returns false when every package that we need has a version
+func (s *solver) decide() (string, bool, error) {
+    pkg, ok := s.nextPackage()
+    if !ok {
+        return "", false, nil
+    }
+    allowed := s.terms[pkg]
+
+    versions, err := s.packageVersions(pkg)
+    if errors.Is(err, ErrUnknownPackage) {
+        s.addIncompatibility(newIncompatibility([]term{{pkg, anyVersion(), true}}, causeUnknownPackage, nil, nil))
+        return pkg, true, nil
+    }
+    if err != nil {
+        return "", false, err
+    }
+
+    i, ok := pickVersion(versions, allowed.versions)
+    if !ok {
+        s.addIncompatibility(newIncompatibility([]term{allowed}, causeNoVersions, nil, nil))
+        return pkg, true, nil
+    }
+    version := versions[i]
+
+    incompatibilities, err := s.dependencies(pkg, versions, i)
+    if err != nil {
+        return "", false, err
+    }
+
+    // we only decide on this version if its dependencies don't
+    // immediately conflict with what we have already picked; if they
+    // do, propagating them will steer us somewhere else
+    conflict := false
+    for _, inc := range incompatibilities {
+        s.addIncompatibility(inc)
+        conflict = conflict || s.satisfiedApartFrom(inc, pkg)
+    }
+    if !conflict {
+        s.decideVersion(pkg, version)
+    }
+
+    return pkg, true, nil
+}
+
+// nextPackage returns the first package that we know we need, but have
+// not picked a version for yet
+func (s *solver) nextPackage() (string, bool) {
+    for _, a := range s.assignments {
+        if _, ok := s.decisions[a.pkg]; ok {
+            continue
+        }
+        if s.terms[a.pkg].positive {
+            return a.pkg, true
+        }
+    }
+
+    return "", false
+}
+
+// satisfiedApartFrom returns true if the partial solution satisfies every
+// term in 'inc' that is not about 'pkg'
+func (s *solver) satisfiedApartFrom(inc *incompatibility, pkg string) bool {
+    for _, t := range inc.terms {
+        if t.pkg != pkg && s.relation(t) != relationSatisfied {
+            return false
+        }
+    }
+
+    return true
+}
+
+// pickVersion picks the newest stable version in 'allowed', or the
+// newest version of any kind if there are no stable ones
+//
+// returns the index of the version in 'versions'
+func pickVersion(versions semver.Versions, allowed semver.Range) (int, bool) {
+    fallback := -1
+    for i := len(versions) - 1; i >= 0; i-- {
+        if !allowed.Contains(versions[i]) {
+            continue
+        }
+        if isStable(versions[i]) {
+            return i, true
+        }
+        if fallback < 0 {
+            fallback = i
+        }
+    }
+
+    return fallback, fallback >= 0
+}
+
+// isStable returns true if 'v' is neither a pre-release nor an unstable
+// release
+func isStable(v semver.SemVersion) bool {
+    return v.PreRelease == "" && v.Stability == ""
+}
+
+// packageVersions returns every version of 'pkg', oldest first
+func (s *solver) packageVersions(pkg string) (semver.Versions, error) {
+    if pkg == rootPackage {
+        return semver.Versions{semver.SemVersion{}}, nil
+    }
+    if versions, ok := s.versions[pkg]; ok {
+        return versions, nil
+    }
+
+    versions, err := s.registry.Versions(pkg)
+    if err != nil {
+        return nil, err
+    }
+    versions = versions.Dedupe()
+    s.versions[pkg] = versions
+
+    return versions, nil
+}
+
+// dependencies turns what versions[i] of a package needs into
+// incompatibilities
+//
+// each incompatibility covers all of the neighbouring versions that have
+// the same requirement, so that we can rule them all out in one go (and
+// explain it in one sentence) if we need to
+func (s *solver) dependencies(pkg string, versions semver.Versions, i int) ([]*incompatibility, error) {
+    order, ranges, err := s.requirementRanges(pkg, versions[i])
+    if err != nil {
+        return nil, err
+    }
+
+    retval := make([]*incompatibility, 0, len(order))
+    for _, dependency := range order {
+        // how far does this requirement stretch?
+        first, last := i, i
+        for first > 0 && s.hasRequirement(pkg, versions[first-1], dependency, ranges[dependency]) {
+            first--
+        }
+        for last < len(versions)-1 && s.hasRequirement(pkg, versions[last+1], dependency, ranges[dependency]) {
+            last++
+        }
+
+        var interval semver.Interval
+        interval.Lower = semver.Bound{Unbounded: true}
+        if first > 0 {
+            interval.Lower = semver.Bound{Version: versions[first-1]}
+        }
+        interval.Upper = semver.Bound{Unbounded: true}
+        if last < len(versions)-1 {
+            interval.Upper = semver.Bound{Version: versions[last+1]}
+        }
+        if pkg == rootPackage {
+            interval = exactly(versions[i]).Intervals[0]
+        }
+
+        depender := term{pkg, semver.Range{Intervals: []semver.Interval{interval}}, true}
+        dependee := term{dependency, ranges[dependency], false}
+        retval = append(retval, newIncompatibility([]term{depender, dependee}, causeDependency, nil, nil))
+    }
+
+    return retval, nil
+}
+
+// hasRequirement returns true if 'version' of 'pkg' requires exactly the
+// versions in 'want' of 'dependency'
+//
+// errors from the registry are ignored here; they are reported if we
+// ever pick that version
+func (s *solver) hasRequirement(pkg string, version semver.SemVersion, dependency string, want semver.Range) bool {
+    _, ranges, err := s.requirementRanges(pkg, version)
+    if err != nil {
+        return false
+    }
+
+    r, ok := ranges[dependency]
+    return ok && isSubset(r, want) && isSubset(want, r)
+}
+
+// requirementRanges returns the range of versions that one version of a
+// package requires of each of its dependencies, in the order that they
+// were listed
+//
+// requirements on the same package are combined into a single range, so
+// '>=1.2' plus '<2.0' works as you'd expect; each range leaves out the
+// versions that its requirement does not match
+//
+// we only ask the registry once for each version, even if it returns an
+// error, as we look at the neighbours of every version that we try
+func (s *solver) requirementRanges(pkg string, version semver.SemVersion) ([]string, map[string]semver.Range, error) {
+    key := pkg + " " + version.String()
+    cached, ok := s.ranges[key]
+    if !ok {
+        cached = s.readRequirementRanges(pkg, version)
+        s.ranges[key] = cached
+    }
+
+    return cached.order, cached.ranges, cached.err
+}
+
+// readRequirementRanges does the work for requirementRanges()
+func (s *solver) readRequirementRanges(pkg string, version semver.SemVersion) requirementRanges {
+    requirements := s.requirements
+    if pkg != rootPackage {
+        var err error
+        requirements, err = s.registry.Dependencies(pkg, version)
+        if err != nil {
+            return requirementRanges{err: err}
+        }
+    }
+
+    var order []string
+    ranges := map[string]semver.Range{}
+    for _, requirement := range requirements {
+        if requirement.Package == pkg {
+            continue
+        }
+        r, err := semver.NewRange(requirement.Expression)
+        if err != nil {
+            return requirementRanges{err: fmt.Errorf("%s: %w", requirement, err)}
+        }
+        r = s.withoutRejectedVersions(requirement, r)
+        if existing, ok := ranges[requirement.Package]; ok {
+            r = existing.Intersect(r)
+        } else {
+            order = append(order, requirement.Package)
+        }
+        ranges[requirement.Package] = r
+    }
+
+    return requirementRanges{order: order, ranges: ranges}
+}
+
+// withoutRejectedVersions takes out of 'r' any versions of the required
+// package that the requirement's expression does not match
+//
+// a range can contain a few more pre-releases than the expression that it
+// came from (e.g. '^1.2.0' contains '1.3.0-rc.1', but does not match it),
+// so we leave out the ones that the registry actually has. That way, every
+// range we work with only contains versions that we are allowed to pick.
+//
+// errors from the registry are ignored here; they are reported if we ever
+// need to pick a version of the package
+func (s *solver) withoutRejectedVersions(requirement Requirement, r semver.Range) semver.Range {
+    versions, err := s.packageVersions(requirement.Package)
+    if err != nil {
+        return r
+    }
+
+    for i := range versions {
+        if !r.Contains(versions[i]) {
+            continue
+        }
+        if ok, _ := requirement.Expression.MatchesVersion(&versions[i]); !ok {
+            r = r.Intersect(exactly(versions[i]).Complement())
+        }
+    }
+
+    return r
+}
+
+// maxInt returns the larger of two ints
+func maxInt(a int, b int) int {
+    if a > b {
+        return a
+    }
+
+    return b
+}
@@ -0,0 +1,138 @@
+package resolve
+
+import (
+    "github.com/stuartherbert/go_semver/semver"
+)
+
+// how a term relates to what we have picked so far
+const (
+    relationSatisfied    = 0 // everything we have picked agrees with the term
+    relationContradicted = 1 // nothing we have picked agrees with the term
+    relationInconclusive = 2 // we can't tell yet
+)
+
+// term is a statement about one package
+//
+// a positive term says 'pkg is selected, and its version is in versions';
+// a negative term says 'pkg is not selected, or its version is not in
+// versions'
+type term struct {
+    pkg      string
+    versions semver.Range
+    positive bool
+}
+
+// anyVersion returns a range that contains every version
+func anyVersion() semver.Range {
+    return semver.Range{}.Complement()
+}
+
+// exactly returns a range that only contains 'version'
+func exactly(version semver.SemVersion) semver.Range {
+    bound := semver.Bound{Version: version, Inclusive: true}
+    return semver.Range{Intervals: []semver.Interval{{Lower: bound, Upper: bound}}}
+}
+
+// isSubset returns true if every version in 'a' is also in 'b'
+func isSubset(a semver.Range, b semver.Range) bool {
+    return a.Intersect(b.Complement()).IsEmpty()
+}
+
+// isDisjoint returns true if 'a' and 'b' have no versions in common
+func isDisjoint(a semver.Range, b semver.Range) bool {
+    return a.Intersect(b).IsEmpty()
+}
+
+// inverse returns the term that is true whenever this one is false
+func (t term) inverse() term {
+    return term{t.pkg, t.versions, !t.positive}
+}
+
+// intersect returns the term that is true when both terms are true
+//
+// both terms must be about the same package
+func (t term) intersect(other term) term {
+    switch {
+    case t.positive && other.positive:
+        return term{t.pkg, t.versions.Intersect(other.versions), true}
+    case t.positive:
+        return term{t.pkg, t.versions.Intersect(other.versions.Complement()), true}
+    case other.positive:
+        return term{t.pkg, other.versions.Intersect(t.versions.Complement()), true}
+    }
+
+    return term{t.pkg, t.versions.Union(other.versions), false}
+}
+
+// difference returns the term that is true when this term is true, and
+// 'other' is false
+func (t term) difference(other term) term {
+    return t.intersect(other.inverse())
+}
+
+// isEmpty returns true if the term can never be true
+//
+// a negative term can always be satisfied by not selecting the package
+func (t term) isEmpty() bool {
+    return t.positive && t.versions.IsEmpty()
+}
+
+// satisfies returns true if 'other' is true whenever this term is true
+func (t term) satisfies(other term) bool {
+    switch {
+    case t.positive && other.positive:
+        return isSubset(t.versions, other.versions)
+    case t.positive:
+        return isDisjoint(t.versions, other.versions)
+    case other.positive:
+        return false
+    }
+
+    return isSubset(other.versions, t.versions)
+}
+
+// relation works out whether 'other' must be true, must be false, or
+// could be either, given that this term is true
+func (t term) relation(other term) int {
+    if t.satisfies(other) {
+        return relationSatisfied
+    }
+    if t.intersect(other).isEmpty() {
+        return relationContradicted
+    }
+
+    return relationInconclusive
+}
+
+// String describes the term for humans, e.g. 'foo >=1.2.0' or
+// 'not foo 1.2.0'
+func (t term) String() string {
+    var retval string
+    switch {
+    case t.pkg == rootPackage:
+        retval = "the root requirements"
+    case isSubset(anyVersion(), t.versions):
+        retval = "any version of " + t.pkg
+    case isSingleVersion(t.versions):
+        retval = t.pkg + " " + t.versions.Intervals[0].Lower.Version.String()
+    default:
+        retval = t.pkg + " " + t.versions.String()
+    }
+
+    if !t.positive {
+        return "not " + retval
+    }
+
+    return retval
+}
+
+// isSingleVersion returns true if the range only contains one version
+func isSingleVersion(r semver.Range) bool {
+    if len(r.Intervals) != 1 {
+        return false
+    }
+
+    lower, upper := r.Intervals[0].Lower, r.Intervals[0].Upper
+    return !lower.Unbounded && !upper.Unbounded && lower.Inclusive && upper.Inclusive &&
+        semver.Cmp(lower.Version, upper.Version) == 0
+}
@@ -0,0 +1,212 @@
+package semver
+
+import (
+    "fmt"
+    "math/big"
+)
+
+// returned by BigVersion.SemVersion() when one of the numbers is less
+// than zero; no version string can contain one of those
+var ErrNegativeNumber = fmt.Errorf("number is negative")
+
+// BigVersion holds the same information as a SemVersion, but uses
+// arbitrary-precision numbers
+//
+// Some ecosystems put datestamps or timestamps into their version numbers
+// (e.g. 1.1.0-SNAPSHOT-20141013093000), and these can be too large to fit
+// into an int. Create one by calling:
+//
+//     v, err = semver.ParseBigVersion("<version>")
+type BigVersion struct {
+    Major      *big.Int // X
+    Minor      *big.Int // Y
+    PatchLevel *big.Int // Z
+    Stability  string   // stability
+    Release    *big.Int // R
+    PreRelease string   // pre.release
+    Build      string   // build.metadata
+}
+
+// ParseBigVersion takes a version string and turns it into a BigVersion
+// struct.
+//
+// Accepts exactly the same version strings as ParseVersion(), but never
+// fails because a number is too large.
+func ParseBigVersion(version string) (BigVersion, error) {
+    scanned, err := scanVersion(version, 0, false)
+    if err != nil {
+        return BigVersion{}, err
+    }
+
+    return scanned.toBigVersion(version), nil
+}
+
+// toBigVersion converts the parts of a scanned version string into a
+// BigVersion struct
+func (s *scannedVersion) toBigVersion(raw string) BigVersion {
+    return BigVersion{
+        Major:      s.major.toBigInt(raw),
+        Minor:      s.minor.toBigInt(raw),
+        PatchLevel: s.patchLevel.toBigInt(raw),
+        Stability:  s.stability,
+        Release:    s.release.toBigInt(raw),
+        PreRelease: s.preRelease,
+        Build:      s.build,
+    }
+}
+
+// toBigInt converts a span of digits into a big.Int
+func (d digits) toBigInt(raw string) *big.Int {
+    value := new(big.Int)
+    if d.start < d.end {
+        value.SetString(raw[d.start:d.end], 10)
+    }
+
+    return value
+}
+
+// SemVersion converts a BigVersion into a SemVersion struct
+//
+// returns ErrNumberOverflow if any of the numbers are too large to fit
+// into an int, or ErrNegativeNumber if any of them are less than zero
+func (v *BigVersion) SemVersion() (SemVersion, error) {
+    numbers := []*big.Int{v.Major, v.Minor, v.PatchLevel, v.Release}
+    for _, number := range numbers {
+        if number == nil {
+            continue
+        }
+        if number.Sign() < 0 {
+            return SemVersion{}, ErrNegativeNumber
+        }
+        if !number.IsInt64() || number.Int64() > int64(maxInt) {
+            return SemVersion{}, ErrNumberOverflow
+        }
+    }
+
+    return SemVersion{
+        Major:      int(bigOrZero(v.Major).Int64()),
+        Minor:      int(bigOrZero(v.Minor).Int64()),
+        PatchLevel: int(bigOrZero(v.PatchLevel).Int64()),
+        Stability:  v.Stability,
+        Release:    int(bigOrZero(v.Release).Int64()),
+        PreRelease: v.PreRelease,
+        Build:      v.Build,
+    }, nil
+}
+
+// Compare compares two BigVersion structs against each other.
+//
+// follows the same rules as SemVersion.Compare(), including
+// StabilityMode, and returns the same COMP_* constants
+func (lhs *BigVersion) Compare(rhs *BigVersion) int {
+    // are we putting stability levels in order?
+    if StabilityMode == STABILITY_ORDERED {
+        return lhs.compareWithStabilityOrder(rhs)
+    }
+
+    // are both sides comparable at all?
+    if !sameStability(lhs.Stability, rhs.Stability) {
+        return COMP_APPLES_AND_ORANGES
+    }
+
+    // stable versions are compared on X.Y.Z; unstable versions can only
+    // be compared if they share the same X.Y.Z
+    lhsNumbers := []*big.Int{lhs.Major, lhs.Minor, lhs.PatchLevel}
+    rhsNumbers := []*big.Int{rhs.Major, rhs.Minor, rhs.PatchLevel}
+    for i := range lhsNumbers {
+        result := bigOrZero(lhsNumbers[i]).Cmp(bigOrZero(rhsNumbers[i]))
+        if result != 0 && lhs.Stability != "" {
+            return COMP_APPLES_AND_ORANGES
+        }
+        if result < 0 {
+            return COMP_LARGER
+        }
+        if result > 0 {
+            return COMP_SMALLER
+        }
+    }
+
+    if lhs.Stability != "" {
+        result := bigOrZero(lhs.Release).Cmp(bigOrZero(rhs.Release))
+        if result < 0 {
+            return COMP_LARGER
+        }
+        if result > 0 {
+            return COMP_SMALLER
+        }
+    }
+
+    switch comparePreReleases(lhs.PreRelease, rhs.PreRelease) {
+    case -1:
+        return COMP_LARGER
+    case 1:
+        return COMP_SMALLER
+    }
+
+    return COMP_EQUAL
+}
+
+// compareWithStabilityOrder is BigVersion.Compare() for when
+// StabilityMode is STABILITY_ORDERED
+func (lhs *BigVersion) compareWithStabilityOrder(rhs *BigVersion) int {
+    lhsNumbers := []*big.Int{lhs.Major, lhs.Minor, lhs.PatchLevel}
+    rhsNumbers := []*big.Int{rhs.Major, rhs.Minor, rhs.PatchLevel}
+    for i := range lhsNumbers {
+        switch bigOrZero(lhsNumbers[i]).Cmp(bigOrZero(rhsNumbers[i])) {
+        case -1:
+            return COMP_LARGER
+        case 1:
+            return COMP_SMALLER
+        }
+    }
+
+    lhsStability, lhsRelease, lhsPreRelease := lhs.orderedStability()
+    rhsStability, rhsRelease, rhsPreRelease := rhs.orderedStability()
+    result, ok := compareStabilityLevels(lhsStability, rhsStability)
+    if !ok {
+        return COMP_APPLES_AND_ORANGES
+    }
+    if result == 0 {
+        result = rhsRelease.Cmp(lhsRelease)
+    }
+    if result == 0 {
+        result = comparePreReleases(rhsPreRelease, lhsPreRelease)
+    }
+
+    switch result {
+    case -1:
+        return COMP_SMALLER
+    case 1:
+        return COMP_LARGER
+    }
+
+    return COMP_EQUAL
+}
+
+// orderedStability does the same job as orderedStability() does for a
+// SemVersion
+func (v *BigVersion) orderedStability() (string, *big.Int, string) {
+    if v.Stability != "" || v.PreRelease == "" {
+        return v.Stability, bigOrZero(v.Release), v.PreRelease
+    }
+
+    level, release, preRelease, ok := splitPreReleaseStability(v.PreRelease)
+    if !ok {
+        return preReleaseLevel, bigOrZero(v.Release), v.PreRelease
+    }
+    number := new(big.Int)
+    if release != "" {
+        number.SetString(release, 10)
+    }
+
+    return level, number, preRelease
+}
+
+// bigOrZero lets us treat a missing number as zero
+func bigOrZero(number *big.Int) *big.Int {
+    if number == nil {
+        return new(big.Int)
+    }
+
+    return number
+}
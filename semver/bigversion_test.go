@@ -0,0 +1,202 @@
+package semver
+
+import (
+    "math/big"
+    "testing"
+)
+
+// ========================================================================
+//
+// Tests for ParseBigVersion()
+//
+// ------------------------------------------------------------------------
+
+func TestCanParseBigVersion(t *testing.T) {
+    // what result do we expect?
+    expected, _ := new(big.Int).SetString("201410131234567890123", 10)
+
+    // perform the test
+    actual, err := ParseBigVersion("1.1.0-SNAPSHOT-201410131234567890123")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual.Release.Cmp(expected) != 0 {
+        t.Errorf("Expected %v, received %v", expected, actual.Release)
+        return
+    }
+    if actual.Major.Int64() != 1 || actual.Minor.Int64() != 1 || actual.PatchLevel.Int64() != 0 {
+        t.Errorf("Expected 1.1.0, received %v.%v.%v", actual.Major, actual.Minor, actual.PatchLevel)
+        return
+    }
+    if actual.Stability != "SNAPSHOT" {
+        t.Errorf("Expected SNAPSHOT, received %s", actual.Stability)
+        return
+    }
+}
+
+func TestParseBigVersionRejectsGarbage(t *testing.T) {
+    // perform the test
+    _, err := ParseBigVersion("1.1-SNAPSHOT")
+
+    // was an error returned?
+    if err == nil {
+        t.Error("Expected an error")
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for BigVersion.SemVersion()
+//
+// ------------------------------------------------------------------------
+
+func TestCanConvertBigVersionToSemVersion(t *testing.T) {
+    // what result do we expect?
+    expected := SemVersion{
+        Major:      1,
+        Minor:      3,
+        PatchLevel: 6,
+        Stability:  "alpha",
+        Release:    1,
+        Build:      "build.5",
+    }
+
+    // perform the test
+    big, err := ParseBigVersion("1.3.6-alpha-1+build.5")
+    if err != nil {
+        t.Error(err)
+        return
+    }
+    actual, err := big.SemVersion()
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %d, received %d", expected, actual)
+        return
+    }
+}
+
+func TestCannotConvertHugeBigVersionToSemVersion(t *testing.T) {
+    // perform the test
+    big, err := ParseBigVersion("1.1.0-SNAPSHOT-201410131234567890123")
+    if err != nil {
+        t.Error(err)
+        return
+    }
+    _, err = big.SemVersion()
+
+    // did we get back what we expected?
+    if err != ErrNumberOverflow {
+        t.Errorf("Expected ErrNumberOverflow, received %v", err)
+        return
+    }
+}
+
+func TestCannotConvertNegativeBigVersionToSemVersion(t *testing.T) {
+    // perform the test
+    negative := BigVersion{Major: big.NewInt(-1)}
+    _, err := negative.SemVersion()
+
+    // did we get back what we expected?
+    if err != ErrNegativeNumber {
+        t.Errorf("Expected ErrNegativeNumber, received %v", err)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for BigVersion.Compare()
+//
+// ------------------------------------------------------------------------
+
+func TestCanCompareTwoBigVersions(t *testing.T) {
+    // our list of things to compare
+    var toCompareList = []VersionExpectedResult{
+        VersionExpectedResult{"1.0", "1.0.0", COMP_EQUAL},
+        VersionExpectedResult{"1.0", "1.1", COMP_LARGER},
+        VersionExpectedResult{"99999999999999999999.0", "100000000000000000000.0", COMP_LARGER},
+        VersionExpectedResult{"100000000000000000000.0", "99999999999999999999.0", COMP_SMALLER},
+        VersionExpectedResult{"1.1.0-SNAPSHOT-201410131234567890123", "1.1.0-SNAPSHOT-201410131234567890124", COMP_LARGER},
+        VersionExpectedResult{"1.1.0-SNAPSHOT-201410131234567890124", "1.1.0-SNAPSHOT-201410131234567890123", COMP_SMALLER},
+        VersionExpectedResult{"1.0.0-rc.1", "1.0.0", COMP_LARGER},
+        VersionExpectedResult{"1.0.0-alpha-1", "1.0.1-alpha-2", COMP_APPLES_AND_ORANGES},
+        VersionExpectedResult{"1.0.0-alpha-1", "1.0.0-beta-1", COMP_APPLES_AND_ORANGES},
+        VersionExpectedResult{"1.0.0", "1.0.0-beta-1", COMP_APPLES_AND_ORANGES},
+    }
+
+    for _, toCompare := range toCompareList {
+        lhs, err := ParseBigVersion(toCompare.lhs)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        rhs, err := ParseBigVersion(toCompare.rhs)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // perform the test
+        actual := lhs.Compare(&rhs)
+
+        // what happened?
+        if actual != toCompare.expected {
+            t.Errorf("lhs: %s; rhs: %s; expected: %d; actual: %d", toCompare.lhs, toCompare.rhs, toCompare.expected, actual)
+            return
+        }
+    }
+}
+
+func TestCanCompareTwoBigVersionsUsingStabilityOrder(t *testing.T) {
+    // put our stability levels in order
+    StabilityMode = STABILITY_ORDERED
+    defer func() { StabilityMode = STABILITY_STRICT }()
+
+    // our list of things to compare
+    var toCompareList = []VersionExpectedResult{
+        VersionExpectedResult{"1.0.0-alpha-1", "1.0.0-beta-1", COMP_LARGER},
+        VersionExpectedResult{"1.0.0", "1.0.0-rc-1", COMP_SMALLER},
+        VersionExpectedResult{"1.0.0-rc-1", "1.0.1-dev-1", COMP_LARGER},
+        VersionExpectedResult{"1.0.0-pre-2", "1.0.0-RC-2", COMP_EQUAL},
+        VersionExpectedResult{"1.1.0-SNAPSHOT-201410131234567890124", "1.1.0-dev-201410131234567890123", COMP_SMALLER},
+        VersionExpectedResult{"1.0.0-rc-1", "1.0.0-alpha.1", COMP_SMALLER},
+        VersionExpectedResult{"1.0.0-rc.1", "1.0.0-alpha.beta", COMP_SMALLER},
+        VersionExpectedResult{"1.0.0-foo", "1.0.0", COMP_LARGER},
+        VersionExpectedResult{"1.0.0-alpha-1", "1.0.0-nightly-1", COMP_APPLES_AND_ORANGES},
+    }
+
+    for _, toCompare := range toCompareList {
+        lhs, err := ParseBigVersion(toCompare.lhs)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        rhs, err := ParseBigVersion(toCompare.rhs)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // perform the test
+        actual := lhs.Compare(&rhs)
+
+        // what happened?
+        if actual != toCompare.expected {
+            t.Errorf("lhs: %s; rhs: %s; expected: %d; actual: %d", toCompare.lhs, toCompare.rhs, toCompare.expected, actual)
+            return
+        }
+    }
+}
@@ -0,0 +1,132 @@
+package semver
+
+import (
+    "fmt"
+    "strings"
+)
+
+// returned by SemVersion.WithStability() when it is given a stability
+// level that cannot appear in a version string
+var ErrInvalidStability = fmt.Errorf("invalid stability level")
+
+// NextMajor returns the next major version (X+1.0.0)
+//
+// the minor version number and patch level are reset to 0, and the new
+// version is always stable, with no build metadata
+func (v SemVersion) NextMajor() SemVersion {
+    return SemVersion{Major: v.Major + 1}
+}
+
+// NextMinor returns the next minor version (X.Y+1.0)
+//
+// the patch level is reset to 0, and the new version is always stable,
+// with no build metadata
+func (v SemVersion) NextMinor() SemVersion {
+    return SemVersion{Major: v.Major, Minor: v.Minor + 1}
+}
+
+// NextPatch returns the next patch level (X.Y.Z+1)
+//
+// the new version is always stable, with no build metadata
+func (v SemVersion) NextPatch() SemVersion {
+    return SemVersion{Major: v.Major, Minor: v.Minor, PatchLevel: v.PatchLevel + 1}
+}
+
+// WithStability starts a new series of unstable releases of X.Y.Z, e.g.
+//
+//     1.3.0.WithStability("rc") == 1.3.0-rc-1
+//     1.3.0-beta-4.WithStability("rc") == 1.3.0-rc-1
+//
+// returns ErrInvalidStability if 'level' is empty, or contains anything
+// other than [A-Za-z0-9_]
+func (v SemVersion) WithStability(level string) (SemVersion, error) {
+    if level == "" {
+        return SemVersion{}, ErrInvalidStability
+    }
+    for i := 0; i < len(level); i++ {
+        if !isStabilityChar(level[i]) {
+            return SemVersion{}, fmt.Errorf("%w: %q", ErrInvalidStability, level)
+        }
+    }
+
+    retval := v.Promote()
+    retval.Stability = level
+    retval.Release = 1
+
+    return retval, nil
+}
+
+// NextRelease returns the next unstable release in the same series, e.g.
+//
+//     1.3.0-rc-1 -> 1.3.0-rc-2
+//     1.3.0-rc.1 -> 1.3.0-rc.2
+//     1.3.0-alpha -> 1.3.0-alpha.1
+//
+// for SemVer 2.0.0 pre-releases, the last numeric identifier is
+// incremented, or '.1' is added if there isn't one. Build metadata is
+// dropped.
+//
+// returns ErrStableVersion if 'v' is not an unstable release, and
+// ErrNumberOverflow if the release number is already as large as an int
+// can be
+func (v SemVersion) NextRelease() (SemVersion, error) {
+    retval := v
+    retval.Build = ""
+
+    switch {
+    case v.Stability != "":
+        if v.Release == maxInt {
+            return SemVersion{}, ErrNumberOverflow
+        }
+        retval.Release++
+    case v.PreRelease != "":
+        retval.PreRelease = nextPreRelease(v.PreRelease)
+    default:
+        return SemVersion{}, ErrStableVersion
+    }
+
+    return retval, nil
+}
+
+// Promote returns the stable release that an unstable release leads up
+// to, e.g.
+//
+//     1.3.0-rc-2 -> 1.3.0
+//
+// stable versions are returned as they are, without any build metadata
+func (v SemVersion) Promote() SemVersion {
+    return SemVersion{Major: v.Major, Minor: v.Minor, PatchLevel: v.PatchLevel}
+}
+
+// nextPreRelease increments the last numeric identifier in a list of
+// dot-separated pre-release identifiers
+//
+// adds a '.1' identifier if there are no numeric identifiers
+func nextPreRelease(preRelease string) string {
+    ids := strings.Split(preRelease, ".")
+    for i := len(ids) - 1; i >= 0; i-- {
+        if isNumericIdentifier(ids[i]) {
+            ids[i] = incrementDigits(ids[i])
+            return strings.Join(ids, ".")
+        }
+    }
+
+    return preRelease + ".1"
+}
+
+// incrementDigits adds one to a string of decimal digits
+//
+// numeric identifiers do not have to fit into an int, so we do the
+// arithmetic on the digits themselves
+func incrementDigits(number string) string {
+    buf := []byte(number)
+    for i := len(buf) - 1; i >= 0; i-- {
+        if buf[i] != '9' {
+            buf[i]++
+            return string(buf)
+        }
+        buf[i] = '0'
+    }
+
+    return "1" + string(buf)
+}
@@ -0,0 +1,175 @@
+package semver
+
+import (
+    "errors"
+    "testing"
+)
+
+// ========================================================================
+//
+// Tests for NextMajor(), NextMinor() and NextPatch()
+//
+// ------------------------------------------------------------------------
+
+func TestCanBumpVersions(t *testing.T) {
+    // our list of versions, and what we expect them to become
+    var toBump = []struct {
+        version string
+        major   string
+        minor   string
+        patch   string
+    }{
+        {"1.3.5", "2.0.0", "1.4.0", "1.3.6"},
+        {"1.3", "2.0.0", "1.4.0", "1.3.1"},
+        {"0.9.9+build.5", "1.0.0", "0.10.0", "0.9.10"},
+        {"1.3.0-rc-2", "2.0.0", "1.4.0", "1.3.1"},
+        {"2.0.0-beta.3", "3.0.0", "2.1.0", "2.0.1"},
+    }
+
+    for _, set := range toBump {
+        version, _ := ParseVersion(set.version)
+
+        // perform the test
+        major := version.NextMajor()
+        minor := version.NextMinor()
+        patch := version.NextPatch()
+
+        // did we get back what we expected?
+        if major.String() != set.major {
+            t.Errorf("%s: expected NextMajor() %s, received %s", set.version, set.major, major)
+            return
+        }
+        if minor.String() != set.minor {
+            t.Errorf("%s: expected NextMinor() %s, received %s", set.version, set.minor, minor)
+            return
+        }
+        if patch.String() != set.patch {
+            t.Errorf("%s: expected NextPatch() %s, received %s", set.version, set.patch, patch)
+            return
+        }
+    }
+}
+
+// ========================================================================
+//
+// Tests for WithStability(), NextRelease() and Promote()
+//
+// ------------------------------------------------------------------------
+
+func TestCanStartUnstableSeries(t *testing.T) {
+    // our list of versions, and what we expect them to become
+    var toBump = map[string]string{
+        "1.3.0":        "1.3.0-rc-1",
+        "1.3":          "1.3.0-rc-1",
+        "1.3.0-beta-4": "1.3.0-rc-1",
+        "1.3.0-rc.7":   "1.3.0-rc-1",
+        "1.3.0+build":  "1.3.0-rc-1",
+    }
+
+    for raw, expected := range toBump {
+        version, _ := ParseVersion(raw)
+
+        // perform the test
+        actual, err := version.WithStability("rc")
+
+        // was an error returned?
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual.String() != expected {
+            t.Errorf("%s: expected %s, received %s", raw, expected, actual)
+            return
+        }
+    }
+}
+
+func TestWithStabilityRejectsInvalidLevels(t *testing.T) {
+    version := SemVersion{Major: 1, Minor: 3}
+
+    for _, level := range []string{"", "rc.1", "rc-1"} {
+        // perform the test
+        _, err := version.WithStability(level)
+
+        // did we get back what we expected?
+        if !errors.Is(err, ErrInvalidStability) {
+            t.Errorf("%q: expected ErrInvalidStability, received %v", level, err)
+            return
+        }
+    }
+}
+
+func TestCanBumpReleaseNumbers(t *testing.T) {
+    // our list of versions, and what we expect them to become
+    var toBump = map[string]string{
+        "1.3.0-rc-1":                 "1.3.0-rc-2",
+        "1.3-alpha-9+build5":         "1.3.0-alpha-10",
+        "1.3.0-rc.1":                 "1.3.0-rc.2",
+        "1.3.0-rc.9.beta":            "1.3.0-rc.10.beta",
+        "1.3.0-alpha":                "1.3.0-alpha.1",
+        "1.3.0-99999999999999999999": "1.3.0-100000000000000000000",
+    }
+
+    for raw, expected := range toBump {
+        version, _ := ParseVersion(raw)
+
+        // perform the test
+        actual, err := version.NextRelease()
+
+        // was an error returned?
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual.String() != expected {
+            t.Errorf("%s: expected %s, received %s", raw, expected, actual)
+            return
+        }
+    }
+}
+
+func TestNextReleaseRejectsStableVersions(t *testing.T) {
+    // perform the test
+    version := SemVersion{Major: 1, Minor: 3}
+    _, err := version.NextRelease()
+
+    // did we get back what we expected?
+    if err != ErrStableVersion {
+        t.Errorf("Expected ErrStableVersion, received %v", err)
+        return
+    }
+
+    // what about a release number that cannot go any higher?
+    version = SemVersion{Major: 1, Stability: "rc", Release: maxInt}
+    _, err = version.NextRelease()
+    if err != ErrNumberOverflow {
+        t.Errorf("Expected ErrNumberOverflow, received %v", err)
+        return
+    }
+}
+
+func TestCanPromoteToStable(t *testing.T) {
+    // our list of versions, and what we expect them to become
+    var toPromote = map[string]string{
+        "1.3.0-rc-2":                 "1.3.0",
+        "2.0.0-beta.3+b7": "2.0.0",
+        "1.3.5":                      "1.3.5",
+    }
+
+    for raw, expected := range toPromote {
+        version, _ := ParseVersion(raw)
+
+        // perform the test
+        actual := version.Promote()
+
+        // did we get back what we expected?
+        if actual.String() != expected {
+            t.Errorf("%s: expected %s, received %s", raw, expected, actual)
+            return
+        }
+    }
+}
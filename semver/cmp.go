@@ -0,0 +1,125 @@
+package semver
+
+import (
+    "fmt"
+    "strings"
+)
+
+// value of IncomparablePolicy when incomparable versions are put into a
+// fixed order (this is the default)
+//
+// the versions are ordered by X.Y.Z first, then by stability level (see
+// StabilityOrder; stable releases come last, and stability levels that
+// aren't in StabilityOrder come first, in alphabetical order), then by
+// release number, and finally by pre-release
+const INCOMPARABLE_ORDER = 0
+
+// value of IncomparablePolicy when Cmp() should panic if it is asked to
+// compare two versions that cannot be compared
+const INCOMPARABLE_PANIC = 1
+
+// IncomparablePolicy decides what Cmp() and the functions built on top of
+// it do when SemVersion.Compare() says COMP_APPLES_AND_ORANGES
+var IncomparablePolicy = INCOMPARABLE_ORDER
+
+// Cmp compares two versions, the same way that the standard library's
+// cmp.Compare() does
+//
+// returns -1 if 'a' is less than 'b', 0 if they are the same, and +1 if
+// 'a' is greater than 'b'
+//
+// Cmp can be passed straight to slices.SortFunc(), slices.BinarySearchFunc()
+// and friends. Versions that SemVersion.Compare() cannot compare are dealt
+// with according to IncomparablePolicy.
+func Cmp(a SemVersion, b SemVersion) int {
+    switch a.Compare(&b) {
+    case COMP_LARGER:
+        return -1
+    case COMP_SMALLER:
+        return 1
+    case COMP_EQUAL:
+        return 0
+    }
+
+    if IncomparablePolicy == INCOMPARABLE_PANIC {
+        panic(fmt.Errorf("%w: %s and %s", ErrIncomparable, a, b))
+    }
+
+    return cmpIncomparable(&a, &b)
+}
+
+// Less returns true if 'a' is less than 'b'
+func Less(a SemVersion, b SemVersion) bool {
+    return Cmp(a, b) < 0
+}
+
+// Equal returns true if 'a' and 'b' have the same precedence
+//
+// build metadata is ignored, just like it is by SemVersion.Compare()
+func Equal(a SemVersion, b SemVersion) bool {
+    return Cmp(a, b) == 0
+}
+
+// Max returns the largest of the versions that it is given
+//
+// if several versions are equally large, the first one is returned
+func Max(first SemVersion, rest ...SemVersion) SemVersion {
+    largest := first
+    for _, version := range rest {
+        if Cmp(version, largest) > 0 {
+            largest = version
+        }
+    }
+
+    return largest
+}
+
+// Min returns the smallest of the versions that it is given
+//
+// if several versions are equally small, the first one is returned
+func Min(first SemVersion, rest ...SemVersion) SemVersion {
+    smallest := first
+    for _, version := range rest {
+        if Cmp(version, smallest) < 0 {
+            smallest = version
+        }
+    }
+
+    return smallest
+}
+
+// cmpIncomparable puts two versions that SemVersion.Compare() cannot
+// compare into the order described by INCOMPARABLE_ORDER
+func cmpIncomparable(a *SemVersion, b *SemVersion) int {
+    if result := compareInts(b.Major, a.Major); result != 0 {
+        return result
+    }
+    if result := compareInts(b.Minor, a.Minor); result != 0 {
+        return result
+    }
+    if result := compareInts(b.PatchLevel, a.PatchLevel); result != 0 {
+        return result
+    }
+
+    if !sameStability(a.Stability, b.Stability) {
+        aRank, aOk := stabilityRank(a.Stability)
+        bRank, bOk := stabilityRank(b.Stability)
+        switch {
+        case aOk && !bOk:
+            return 1
+        case !aOk && bOk:
+            return -1
+        case aOk && bOk && aRank != bRank:
+            return compareInts(bRank, aRank)
+        }
+
+        // fall back to alphabetical order
+        return strings.Compare(strings.ToLower(a.Stability), strings.ToLower(b.Stability))
+    }
+
+    if result := compareInts(b.Release, a.Release); result != 0 {
+        return result
+    }
+
+    return comparePreReleases(a.PreRelease, b.PreRelease)
+}
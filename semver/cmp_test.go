@@ -0,0 +1,169 @@
+package semver
+
+import (
+    "errors"
+    "slices"
+    "testing"
+)
+
+// ========================================================================
+//
+// Compare two versions using Cmp()
+//
+// ------------------------------------------------------------------------
+
+func TestCmpReturnsMinusOneZeroOrPlusOne(t *testing.T) {
+    // our list of things to compare
+    var toCompareList = []VersionExpectedResult{
+        VersionExpectedResult{"1.0", "1.0.0", 0},
+        VersionExpectedResult{"1.0.0", "1.0.0+build.1", 0},
+        VersionExpectedResult{"1.0.0", "1.1.0", -1},
+        VersionExpectedResult{"1.1.0", "1.0.0", 1},
+        VersionExpectedResult{"1.0.0-rc.1", "1.0.0", -1},
+        VersionExpectedResult{"1.0.0-alpha-1", "1.0.0-alpha-2", -1},
+        VersionExpectedResult{"1.0.0-alpha-2", "1.0.0-alpha-1", 1},
+
+        // things that SemVersion.Compare() cannot compare
+        VersionExpectedResult{"1.0.0-alpha-1", "1.0.0-beta-1", -1},
+        VersionExpectedResult{"1.0.0-beta-1", "1.0.0", -1},
+        VersionExpectedResult{"1.0.0", "1.0.1-dev-1", -1},
+        VersionExpectedResult{"1.0.1-dev-1", "1.0.0", 1},
+        VersionExpectedResult{"1.0.0-alpha-1", "0.9.0-beta-1", 1},
+        VersionExpectedResult{"1.0.0-nightly-1", "1.0.0-dev-1", -1},
+        VersionExpectedResult{"1.0.0-nightly-9", "1.0.0-weekly-1", -1},
+    }
+
+    for _, toCompare := range toCompareList {
+        // compile the versions
+        a, err := ParseVersion(toCompare.lhs)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        b, err := ParseVersion(toCompare.rhs)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // perform the test
+        actual := Cmp(a, b)
+
+        // what happened?
+        if actual != toCompare.expected {
+            t.Errorf("lhs: %s; rhs: %s; expected: %d; actual: %d", toCompare.lhs, toCompare.rhs, toCompare.expected, actual)
+            return
+        }
+    }
+}
+
+func TestCmpPanicsWhenPolicyIsPanic(t *testing.T) {
+    // setup our policy
+    IncomparablePolicy = INCOMPARABLE_PANIC
+    defer func() { IncomparablePolicy = INCOMPARABLE_ORDER }()
+
+    a, _ := ParseVersion("1.0.0-alpha-1")
+    b, _ := ParseVersion("1.0.0-beta-1")
+
+    // did we get back what we expected?
+    defer func() {
+        err, ok := recover().(error)
+        if !ok || !errors.Is(err, ErrIncomparable) {
+            t.Errorf("Expected a panic with ErrIncomparable, received %v", err)
+        }
+    }()
+
+    // perform the test
+    Cmp(a, b)
+}
+
+func TestCanSortUsingCmp(t *testing.T) {
+    // what result do we expect?
+    expected := []string{
+        "0.9.0",
+        "1.0.0-alpha-1",
+        "1.0.0-alpha-2",
+        "1.0.0-beta-1",
+        "1.0.0-rc.1",
+        "1.0.0",
+        "1.0.1",
+        "1.10.0",
+    }
+
+    // perform the test
+    var actual []SemVersion
+    for _, raw := range []string{"1.10.0", "1.0.0-beta-1", "1.0.0", "1.0.0-alpha-2", "0.9.0", "1.0.1", "1.0.0-rc.1", "1.0.0-alpha-1"} {
+        version, err := ParseVersion(raw)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual = append(actual, version)
+    }
+    slices.SortFunc(actual, Cmp)
+
+    // did we get back what we expected?
+    for i := range expected {
+        version, _ := ParseVersion(expected[i])
+        if actual[i] != version {
+            t.Errorf("Expected %s at position %d, received %+v", expected[i], i, actual[i])
+            return
+        }
+    }
+}
+
+// ========================================================================
+//
+// Tests for Less(), Equal(), Max() and Min()
+//
+// ------------------------------------------------------------------------
+
+func TestLessAndEqual(t *testing.T) {
+    a, _ := ParseVersion("1.2.3")
+    b, _ := ParseVersion("1.2.4")
+    c, _ := ParseVersion("1.2.3+build.5")
+
+    // perform the tests
+    if !Less(a, b) || Less(b, a) || Less(a, c) {
+        t.Errorf("Less() did not agree with Cmp()")
+        return
+    }
+    if !Equal(a, c) || Equal(a, b) {
+        t.Errorf("Equal() did not agree with Cmp()")
+        return
+    }
+}
+
+func TestMaxAndMin(t *testing.T) {
+    var versions []SemVersion
+    for _, raw := range []string{"1.2.3", "2.0.0-rc.1", "0.9.9", "2.0.0", "1.10.0"} {
+        version, err := ParseVersion(raw)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        versions = append(versions, version)
+    }
+
+    // what result do we expect?
+    expectedMax := SemVersion{Major: 2}
+    expectedMin := SemVersion{Minor: 9, PatchLevel: 9}
+
+    // perform the tests
+    actualMax := Max(versions[0], versions[1:]...)
+    actualMin := Min(versions[0], versions[1:]...)
+
+    // did we get back what we expected?
+    if actualMax != expectedMax {
+        t.Errorf("Expected %+v, received %+v", expectedMax, actualMax)
+        return
+    }
+    if actualMin != expectedMin {
+        t.Errorf("Expected %+v, received %+v", expectedMin, actualMin)
+        return
+    }
+    if Max(versions[0]) != versions[0] {
+        t.Errorf("Expected %+v, received %+v", versions[0], Max(versions[0]))
+        return
+    }
+}
@@ -0,0 +1,273 @@
+package semver
+
+import (
+    "strings"
+)
+
+// Coercion describes how CoerceWithReport() turned a string into a
+// version
+type Coercion struct {
+    Version SemVersion // the version that we found
+
+    // the parts of the input that are not in Version, in the order that
+    // they appeared, e.g. 'release-' and '.4' from 'release-1.2.3.4'
+    Discarded []string
+}
+
+// Coerce finds the version in a messy, real-world version string, such
+// as 'v1.2', 'release-1.4.0', '1.2.3.4', '1.2.3_beta2' or '2.1rc1'
+//
+// use this to clean up version strings that come from other tools before
+// you compare them; use ParseVersion() for version strings that you
+// expect to be correct. Call CoerceWithReport() instead if you need to
+// know what was thrown away.
+//
+// The rules are:
+//
+//     - leading and trailing whitespace is ignored
+//     - the version starts at the first number that is followed by '.'
+//       and another number, or at the first number if there is no such
+//       thing; anything before it is discarded (e.g. 'v' or 'release-')
+//     - if ParseVersion() accepts everything from there onwards, we use
+//       what it returns
+//     - otherwise, we read up to three dot-separated numbers as X.Y.Z;
+//       any that are missing are zero, and any more are discarded
+//       (e.g. '1.2.3.4' becomes '1.2.3')
+//     - wildcards after the numbers are discarded too, so '1.2.x' and
+//       '1.*' become '1.2.0' and '1.0.0'
+//     - a word that follows the numbers, on its own or after '-', '_' or
+//       '.', is the stability level. A number after the word (again, on
+//       its own or after '-', '_' or '.') is the release number, so
+//       '1.2.3_beta2' becomes '1.2.3-beta-2' and '2.1rc1' becomes
+//       '2.1.0-rc-1'. A word without a number becomes a pre-release.
+//     - '+<build.metadata>' is kept if it comes next
+//     - anything else is discarded
+//
+// A pre-release that is just a word and a number, such as the 'rc1' in
+// '1.2.3-rc1' or the 'rc.1' in '1.2.3-rc.1', always becomes a stability
+// level and release number ('1.2.3-rc-1'), whichever rule found it. That
+// way, the same pre-release spelt in different ways can be compared.
+//
+// returns a *ParseError if there is no number in the string at all, or
+// one that wraps ErrNumberOverflow if a number is too large for an int
+func Coerce(raw string) (SemVersion, error) {
+    coerced, err := CoerceWithReport(raw)
+    if err != nil {
+        return SemVersion{}, err
+    }
+
+    return coerced.Version, nil
+}
+
+// CoerceWithReport does the same thing as Coerce(), and also tells you
+// which parts of the input were thrown away
+//
+// returns the same errors as Coerce()
+func CoerceWithReport(raw string) (Coercion, error) {
+    trimmed := strings.TrimSpace(raw)
+
+    var retval Coercion
+    start := findVersionStart(trimmed)
+    if start < 0 {
+        return Coercion{}, newParseError(raw, 0, expectMajor)
+    }
+    if start > 0 {
+        retval.Discarded = append(retval.Discarded, trimmed[:start])
+    }
+
+    // is it a version string that we already understand?
+    if version, err := ParseVersion(trimmed[start:]); err == nil {
+        retval.Version = version
+        coercePreRelease(&retval.Version)
+        return retval, nil
+    }
+
+    // no, so we have to pick it apart ourselves
+    pos, err := coerceNumbers(trimmed, start, &retval)
+    if err == nil {
+        pos = coerceWildcards(trimmed, pos, &retval)
+        pos, err = coerceStability(trimmed, pos, &retval.Version)
+    }
+    if err != nil {
+        // make sure the caller sees what they gave us
+        err.Input = raw
+        err.Offset += strings.Index(raw, trimmed)
+        return Coercion{}, err
+    }
+    pos = coerceBuild(trimmed, pos, &retval.Version)
+
+    if pos < len(trimmed) {
+        retval.Discarded = append(retval.Discarded, trimmed[pos:])
+    }
+
+    return retval, nil
+}
+
+// findVersionStart works out where the version starts in 'raw'
+//
+// that is the first number followed by '.' and another number, or the
+// first number if there isn't one of those
+//
+// returns -1 if there are no numbers in 'raw' at all
+func findVersionStart(raw string) int {
+    first := -1
+    for i := 0; i < len(raw); i++ {
+        if !isDigit(raw[i]) || (i > 0 && isDigit(raw[i-1])) {
+            continue
+        }
+        if first < 0 {
+            first = i
+        }
+
+        end := i
+        for end < len(raw) && isDigit(raw[end]) {
+            end++
+        }
+        if end+1 < len(raw) && raw[end] == '.' && isDigit(raw[end+1]) {
+            return i
+        }
+    }
+
+    return first
+}
+
+// coerceNumbers reads X[.Y[.Z]] from raw[pos], and skips over any more
+// numbers that follow them
+//
+// returns the offset of whatever comes next
+func coerceNumbers(raw string, pos int, coerced *Coercion) (int, *ParseError) {
+    fields := []*int{&coerced.Version.Major, &coerced.Version.Minor, &coerced.Version.PatchLevel}
+    expected := []string{expectMajor, expectMinor, expectPatchLevel}
+
+    for i := range fields {
+        if i > 0 {
+            if pos+1 >= len(raw) || raw[pos] != '.' || !isDigit(raw[pos+1]) {
+                return pos, nil
+            }
+            pos++
+        }
+
+        var number digits
+        var ok bool
+        number, pos, _ = scanNumber(raw, pos, false)
+        if *fields[i], ok = number.toInt(raw); !ok {
+            return pos, newOverflowError(raw, number.start, expected[i])
+        }
+    }
+
+    // anything like the '.4' in '1.2.3.4' has nowhere to go
+    extra := pos
+    for pos+1 < len(raw) && raw[pos] == '.' && isDigit(raw[pos+1]) {
+        _, pos, _ = scanNumber(raw, pos+1, false)
+    }
+    if pos > extra {
+        coerced.Discarded = append(coerced.Discarded, raw[extra:pos])
+    }
+
+    return pos, nil
+}
+
+// coerceWildcards skips over any wildcards, such as the '.x' in '1.2.x',
+// that start at raw[pos]
+//
+// returns the offset of whatever comes next
+func coerceWildcards(raw string, pos int, coerced *Coercion) int {
+    start := pos
+    for pos+1 < len(raw) && raw[pos] == '.' && isWildcard(raw[pos+1]) {
+        // 'x' could be the start of a word instead
+        if pos+2 < len(raw) && (isLetter(raw[pos+2]) || isDigit(raw[pos+2])) {
+            break
+        }
+        pos += 2
+    }
+    if pos > start {
+        coerced.Discarded = append(coerced.Discarded, raw[start:pos])
+    }
+
+    return pos
+}
+
+// coercePreRelease turns a pre-release that is just a word and a number
+// (e.g. 'rc1' or 'rc.1') into a stability level and release number, the
+// same way that coerceStability() does
+func coercePreRelease(version *SemVersion) {
+    if version.Stability != "" || version.PreRelease == "" {
+        return
+    }
+
+    var found SemVersion
+    end, err := coerceStability(version.PreRelease, 0, &found)
+    if err != nil || end < len(version.PreRelease) || found.Stability == "" {
+        return
+    }
+
+    version.Stability = found.Stability
+    version.Release = found.Release
+    version.PreRelease = ""
+}
+
+// coerceStability reads a stability level and release number from
+// raw[pos], if there is one
+//
+// returns the offset of whatever comes next
+func coerceStability(raw string, pos int, version *SemVersion) (int, *ParseError) {
+    // the word can follow a separator, or come straight after the numbers
+    start := pos
+    if start+1 < len(raw) && isCoerceSeparator(raw[start]) && isLetter(raw[start+1]) {
+        start++
+    }
+    end := start
+    for end < len(raw) && isLetter(raw[end]) {
+        end++
+    }
+    if end == start {
+        return pos, nil
+    }
+    word := raw[start:end]
+
+    // is there a release number too?
+    numberStart := end
+    if numberStart+1 < len(raw) && isCoerceSeparator(raw[numberStart]) && isDigit(raw[numberStart+1]) {
+        numberStart++
+    }
+    number, next, ok := scanNumber(raw, numberStart, false)
+    if !ok {
+        version.PreRelease = word
+        return end, nil
+    }
+
+    if version.Release, ok = number.toInt(raw); !ok {
+        return next, newOverflowError(raw, number.start, expectRelease)
+    }
+    version.Stability = word
+
+    return next, nil
+}
+
+// coerceBuild reads '+<build.metadata>' from raw[pos], if it is there
+//
+// returns the offset of whatever comes next
+func coerceBuild(raw string, pos int, version *SemVersion) int {
+    if pos >= len(raw) || raw[pos] != '+' {
+        return pos
+    }
+
+    end, failure := scanIdentifiers(raw, pos+1, false)
+    if failure.failed() {
+        return pos
+    }
+    version.Build = raw[pos+1 : end]
+
+    return end
+}
+
+// isCoerceSeparator returns true if 'c' can separate the parts of a
+// version string that Coerce() understands
+func isCoerceSeparator(c byte) bool {
+    return c == '-' || c == '_' || c == '.'
+}
+
+// isLetter returns true if 'c' is an ASCII letter
+func isLetter(c byte) bool {
+    return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,163 @@
+package semver
+
+import (
+    "errors"
+    "strings"
+    "testing"
+)
+
+// ========================================================================
+//
+// Tests for Coerce()
+//
+// ------------------------------------------------------------------------
+
+func TestCanCoerceMessyVersions(t *testing.T) {
+    // our list of messy version strings, and what we expect them to become
+    var toCoerce = []struct {
+        raw       string
+        expected  string
+        discarded []string
+    }{
+        {"1.2.3", "1.2.3", nil},
+        {"v1.2", "1.2.0", []string{"v"}},
+        {"  V2.0.0-rc.1  ", "2.0.0-rc-1", []string{"V"}},
+        {"2.0.0-rc.1.2", "2.0.0-rc.1.2", nil},
+        {"1.2.3-rc1", "1.2.3-rc-1", nil},
+        {"1.2.3-beta", "1.2.3-beta", nil},
+        {"release-1.4.0", "1.4.0", []string{"release-"}},
+        {"python3-1.2.0", "1.2.0", []string{"python3-"}},
+        {"v1", "1.0.0", []string{"v"}},
+        {"1.2.3.4", "1.2.3", []string{".4"}},
+        {"1.2.3_beta2", "1.2.3-beta-2", nil},
+        {"2.1rc1", "2.1.0-rc-1", nil},
+        {"1.2.3.beta.2", "1.2.3-beta-2", nil},
+        {"2.1beta", "2.1.0-beta", nil},
+        {"1.2.3.4-beta1+abc", "1.2.3-beta-1+abc", []string{".4"}},
+        {"1.2.3 (build 456)", "1.2.3", []string{" (build 456)"}},
+        {"app-1.2.3.4.5_rc3 final", "1.2.3-rc-3", []string{"app-", ".4.5", " final"}},
+        {"1.2.x", "1.2.0", []string{".x"}},
+        {"v1.*", "1.0.0", []string{"v", ".*"}},
+        {"1.X.X-beta2", "1.0.0-beta-2", []string{".X.X"}},
+        {"1.2.xyz", "1.2.0-xyz", nil},
+    }
+
+    for _, set := range toCoerce {
+        // perform the test
+        actual, err := CoerceWithReport(set.raw)
+
+        // was an error returned?
+        if err != nil {
+            t.Errorf("%q: %v", set.raw, err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual.Version.String() != set.expected {
+            t.Errorf("%q: expected %s, received %s", set.raw, set.expected, actual.Version)
+            return
+        }
+        if strings.Join(actual.Discarded, "|") != strings.Join(set.discarded, "|") || len(actual.Discarded) != len(set.discarded) {
+            t.Errorf("%q: expected %q to be discarded, received %q", set.raw, set.discarded, actual.Discarded)
+            return
+        }
+
+        // Coerce() must agree
+        version, err := Coerce(set.raw)
+        if err != nil || version != actual.Version {
+            t.Errorf("%q: expected %s, received %s and %v", set.raw, actual.Version, version, err)
+            return
+        }
+    }
+}
+
+func TestCoercedVersionsCanBeParsedAgain(t *testing.T) {
+    // our list of messy version strings
+    var toCoerce = []string{"v1.2", "1.2.3_beta2", "2.1rc1", "1.1.1k", "1.2.3.4-beta1+abc", "1.2.3-rc.1", "1.2.x"}
+
+    for _, raw := range toCoerce {
+        // perform the test
+        coerced, err := Coerce(raw)
+        if err != nil {
+            t.Errorf("%q: %v", raw, err)
+            return
+        }
+        parsed, err := ParseVersion(coerced.String())
+
+        // did we get back what we expected?
+        if err != nil || parsed != coerced {
+            t.Errorf("%q: expected %+v, received %+v and %v", raw, coerced, parsed, err)
+            return
+        }
+    }
+}
+
+func TestCoercedVersionsCanBeCompared(t *testing.T) {
+    // our list of things to compare, each spelt in different ways
+    var toCompareList = []VersionExpectedResult{
+        VersionExpectedResult{"1.2.3_rc2", "1.2.3-rc1", COMP_SMALLER},
+        VersionExpectedResult{"1.2.3_beta10", "1.2.3-beta9", COMP_SMALLER},
+        VersionExpectedResult{"1.2.3-beta.1", "1.2.3beta2", COMP_LARGER},
+        VersionExpectedResult{"v1.2.3-rc.1", "1.2.3_RC1", COMP_EQUAL},
+    }
+
+    for _, toCompare := range toCompareList {
+        lhs, err := Coerce(toCompare.lhs)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        rhs, err := Coerce(toCompare.rhs)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // perform the test
+        actual := lhs.Compare(&rhs)
+
+        // what happened?
+        if actual != toCompare.expected {
+            t.Errorf("lhs: %s; rhs: %s; expected: %d; actual: %d", toCompare.lhs, toCompare.rhs, toCompare.expected, actual)
+            return
+        }
+    }
+}
+
+func TestCoerceRejectsStringsWithoutVersions(t *testing.T) {
+    // our list of strings that have no version in them
+    var toCoerce = []string{"", "   ", "banana", "v.x"}
+
+    for _, raw := range toCoerce {
+        // perform the test
+        _, err := Coerce(raw)
+
+        // was an error returned?
+        var parseErr *ParseError
+        if !errors.As(err, &parseErr) {
+            t.Errorf("%q: expected a *ParseError, received %v", raw, err)
+            return
+        }
+    }
+}
+
+func TestCoerceReportsNumbersThatAreTooLarge(t *testing.T) {
+    // what result do we expect?
+    expected := ParseError{"  99999999999999999999999.1.x", 2, expectMajor, ErrNumberOverflow}
+
+    // perform the test
+    _, err := Coerce(expected.Input)
+
+    // was an error returned?
+    var actual *ParseError
+    if !errors.As(err, &actual) || !errors.Is(err, ErrNumberOverflow) {
+        t.Errorf("Expected a *ParseError wrapping ErrNumberOverflow, received %v", err)
+        return
+    }
+
+    // did we get back what we expected?
+    if *actual != expected {
+        t.Errorf("Expected %v, received %v", &expected, actual)
+        return
+    }
+}
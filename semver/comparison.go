@@ -2,7 +2,6 @@ package semver
 
 import (
     "fmt"
-    "strings"
 )
 
 // errors returned when a version does not match an expression
@@ -22,6 +21,9 @@ var (
     ErrMinorVersionTooLarge     = fmt.Errorf("minor version number is too large")
     ErrPatchLevelTooLarge       = fmt.Errorf("patchlevel is too large")
     ErrReleaseNumberTooLarge    = fmt.Errorf("release number is too large")
+    ErrDifferentPreReleases     = fmt.Errorf("pre-release identifiers are different")
+    ErrPreReleaseTooSmall       = fmt.Errorf("pre-release has too low a precedence")
+    ErrPreReleaseTooLarge       = fmt.Errorf("pre-release has too high a precedence")
     ErrUnstableVersion          = fmt.Errorf("unexpected unstable version received")
     ErrStableVersion            = fmt.Errorf("unexpected stable version received")
     ErrOlderUnstableVersion     = fmt.Errorf("older unstable version")
@@ -29,16 +31,47 @@ var (
     ErrSameVersion              = fmt.Errorf("same version number")
 )
 
+// value of TildeMode when '~' accepts any newer version that has the
+// same major version number (this is the default)
+const TILDE_SAME_MAJOR = 0
+
+// value of TildeMode when '~' only accepts newer versions that have the
+// same major AND minor version numbers, the way that npm does
+const TILDE_SAME_MINOR = 1
+
+// TildeMode decides which versions the '~' operator accepts
+//
+// set it to TILDE_SAME_MINOR if you want '~' to behave the way that it
+// does in npm's package.json
+var TildeMode = TILDE_SAME_MAJOR
+
+// returned (wrapped in a ParseError) when a number in a version string
+// is too large to fit into an int
+//
+// use ParseBigVersion() if you need to work with numbers this large
+var ErrNumberOverflow = fmt.Errorf("number is too large")
+
 // Matches checks to see if 'version' matches the expression that we have
 // already parsed.
 //
 // this is a convenience method around 'MatchesVersion', to avoid parsing
 // the 'version' string yourself first
 //
+// if the expression uses the '@' operator, 'version' is parsed as a
+// branch, tag or commit ID instead
+//
 // returns 'true' if the version matches the expression in 'lhs'
-// returns 'false' plus one of the Err* values if the version does not
-// match
+// returns 'false' plus a *MatchError that wraps one of the Err* values if
+// the version does not match
 func (lhs *VersionExpression) Matches(version string) (bool, error) {
+    if lhs.Operator == OP_AT {
+        rhs, err := ParseReference(version)
+        if err != nil {
+            return false, err
+        }
+        return lhs.MatchesReference(&rhs)
+    }
+
     // we need to turn our raw string into a comparison struct first
     rhs, err := ParseVersion(version)
     if err != nil {
@@ -52,9 +85,30 @@ func (lhs *VersionExpression) Matches(version string) (bool, error) {
 // we have already parsed.
 //
 // returns 'true' if the version matches the expression in 'lhs'
-// returns 'false' plus one of the Err* values if the version does not
-// match
+// returns 'false' plus a *MatchError that wraps one of the Err* values if
+// the version does not match
 func (lhs *VersionExpression) MatchesVersion(rhs *SemVersion) (bool, error) {
+    var ok bool
+    var err error
+
+    // operators added by RegisterOperator() do their own matching;
+    // otherwise, are we putting stability levels in order?
+    if match := lhs.Operator.matchFunc(); match != nil {
+        ok, err = match(lhs, rhs)
+    } else if StabilityMode == STABILITY_ORDERED {
+        ok, err = lhs.matchesWithStabilityOrder(rhs)
+    } else {
+        ok, err = lhs.matchesWithoutStabilityOrder(rhs)
+    }
+
+    if err != nil {
+        return ok, newMatchError(lhs, rhs, err)
+    }
+
+    return ok, nil
+}
+
+func (lhs *VersionExpression) matchesWithoutStabilityOrder(rhs *SemVersion) (bool, error) {
     switch lhs.Operator {
     case OP_EQUALS:
         return lhs.matchesEquals(rhs)
@@ -70,6 +124,22 @@ func (lhs *VersionExpression) MatchesVersion(rhs *SemVersion) (bool, error) {
 
     case OP_NOT_EQUALS:
         return lhs.matchesAnythingBut(rhs)
+
+    case OP_GT:
+        return lhs.matchesGreaterThan(rhs)
+
+    case OP_LT:
+        return lhs.matchesLessThan(rhs)
+
+    case OP_CARET:
+        return lhs.matchesCaret(rhs)
+
+    case OP_RANGE:
+        return lhs.matchesRange(rhs)
+
+    case OP_AT:
+        // use MatchesReference() instead
+        return false, ErrNotAReference
     }
 
     // if we get here, then we do not recognise the operator
@@ -90,12 +160,15 @@ func (lhs *VersionExpression) matchesEquals(rhs *SemVersion) (bool, error) {
     if lhs.Version.PatchLevel != rhs.PatchLevel {
         return false, ErrDifferentPatchLevel
     }
-    if strings.ToLower(lhs.Version.Stability) != strings.ToLower(rhs.Stability) {
+    if !sameStability(lhs.Version.Stability, rhs.Stability) {
         return false, ErrDifferentStabilityLevels
     }
     if lhs.Version.Release != rhs.Release {
         return false, ErrDifferentReleaseNumbers
     }
+    if lhs.Version.PreRelease != rhs.PreRelease {
+        return false, ErrDifferentPreReleases
+    }
 
     // if we get to here, then we're happy that everything will work
     return true, nil
@@ -112,7 +185,7 @@ func (lhs *VersionExpression) matchesGreaterThanOrEqualTo(rhs *SemVersion) (bool
 }
 
 func (lhs *VersionExpression) matchesGreaterThanOrEqualToStable(rhs *SemVersion) (bool, error) {
-    if lhs.Version.Stability != rhs.Stability {
+    if !sameStability(lhs.Version.Stability, rhs.Stability) {
         return false, ErrDifferentStabilityLevels
     }
     if lhs.Version.Stability != "" || rhs.Stability != "" {
@@ -140,6 +213,14 @@ func (lhs *VersionExpression) matchesGreaterThanOrEqualToStable(rhs *SemVersion)
     if rhs.PatchLevel < lhs.Version.PatchLevel {
         return false, ErrPatchLevelTooSmall
     }
+    if rhs.PatchLevel > lhs.Version.PatchLevel {
+        return true, nil
+    }
+
+    // at this point, lhs.X.Y.Z = rhs.X.Y.Z
+    if comparePreReleases(rhs.PreRelease, lhs.Version.PreRelease) < 0 {
+        return false, ErrPreReleaseTooSmall
+    }
 
     // if we get here, then we're good
     //
@@ -149,7 +230,7 @@ func (lhs *VersionExpression) matchesGreaterThanOrEqualToStable(rhs *SemVersion)
 }
 
 func (lhs *VersionExpression) matchesGreaterThanOrEqualToUnstable(rhs *SemVersion) (bool, error) {
-    if lhs.Version.Stability != rhs.Stability {
+    if !sameStability(lhs.Version.Stability, rhs.Stability) {
         return false, ErrDifferentStabilityLevels
     }
     if lhs.Version.Stability == "" || rhs.Stability == "" {
@@ -188,7 +269,7 @@ func (lhs *VersionExpression) matchesLessThanOrEqualTo(rhs *SemVersion) (bool, e
 }
 
 func (lhs *VersionExpression) matchesLessThanOrEqualToStable(rhs *SemVersion) (bool, error) {
-    if lhs.Version.Stability != rhs.Stability {
+    if !sameStability(lhs.Version.Stability, rhs.Stability) {
         return false, ErrDifferentStabilityLevels
     }
     if lhs.Version.Stability != "" || rhs.Stability != "" {
@@ -216,6 +297,14 @@ func (lhs *VersionExpression) matchesLessThanOrEqualToStable(rhs *SemVersion) (b
     if rhs.PatchLevel > lhs.Version.PatchLevel {
         return false, ErrPatchLevelTooLarge
     }
+    if rhs.PatchLevel < lhs.Version.PatchLevel {
+        return true, nil
+    }
+
+    // at this point, lhs.X.Y.Z = rhs.X.Y.Z
+    if comparePreReleases(rhs.PreRelease, lhs.Version.PreRelease) > 0 {
+        return false, ErrPreReleaseTooLarge
+    }
 
     // if we get here, then we're good
     //
@@ -225,7 +314,7 @@ func (lhs *VersionExpression) matchesLessThanOrEqualToStable(rhs *SemVersion) (b
 }
 
 func (lhs *VersionExpression) matchesLessThanOrEqualToUnstable(rhs *SemVersion) (bool, error) {
-    if lhs.Version.Stability != rhs.Stability {
+    if !sameStability(lhs.Version.Stability, rhs.Stability) {
         return false, ErrDifferentStabilityLevels
     }
     if lhs.Version.Stability == "" || rhs.Stability == "" {
@@ -253,6 +342,180 @@ func (lhs *VersionExpression) matchesLessThanOrEqualToUnstable(rhs *SemVersion)
     return true, nil
 }
 
+func (lhs *VersionExpression) matchesGreaterThan(rhs *SemVersion) (bool, error) {
+    // are we checking a stable or an unstable release?
+    if lhs.Version.Stability == "" {
+        return lhs.matchesGreaterThanStable(rhs)
+    }
+
+    // we are checking an unstable release
+    return lhs.matchesGreaterThanUnstable(rhs)
+}
+
+func (lhs *VersionExpression) matchesGreaterThanStable(rhs *SemVersion) (bool, error) {
+    if !sameStability(lhs.Version.Stability, rhs.Stability) {
+        return false, ErrDifferentStabilityLevels
+    }
+    if lhs.Version.Stability != "" || rhs.Stability != "" {
+        return false, ErrUnstableVersion
+    }
+
+    // now it's just a straight-forward check of each of the numerical fields
+    // in turn
+    if rhs.Major < lhs.Version.Major {
+        return false, ErrMajorVersionTooSmall
+    }
+    if rhs.Major > lhs.Version.Major {
+        return true, nil
+    }
+
+    // at this point, lhs.X == rhs.X
+    if rhs.Minor < lhs.Version.Minor {
+        return false, ErrMinorVersionTooSmall
+    }
+    if rhs.Minor > lhs.Version.Minor {
+        return true, nil
+    }
+
+    // at this point, lhs.X.Y = rhs.X.Y
+    if rhs.PatchLevel < lhs.Version.PatchLevel {
+        return false, ErrPatchLevelTooSmall
+    }
+    if rhs.PatchLevel > lhs.Version.PatchLevel {
+        return true, nil
+    }
+
+    // at this point, lhs.X.Y.Z = rhs.X.Y.Z
+    switch comparePreReleases(rhs.PreRelease, lhs.Version.PreRelease) {
+    case -1:
+        return false, ErrPreReleaseTooSmall
+    case 1:
+        return true, nil
+    }
+
+    // if we get here, both versions are the same
+    return false, ErrSameVersion
+}
+
+func (lhs *VersionExpression) matchesGreaterThanUnstable(rhs *SemVersion) (bool, error) {
+    if !sameStability(lhs.Version.Stability, rhs.Stability) {
+        return false, ErrDifferentStabilityLevels
+    }
+    if lhs.Version.Stability == "" || rhs.Stability == "" {
+        return false, ErrStableVersion
+    }
+
+    // now it's just a straight-forward check of each of the numerical fields
+    // in turn
+    if lhs.Version.Major != rhs.Major {
+        return false, ErrDifferentMajorVersions
+    }
+    if lhs.Version.Minor != rhs.Minor {
+        return false, ErrDifferentMinorVersions
+    }
+    if lhs.Version.PatchLevel != rhs.PatchLevel {
+        return false, ErrDifferentPatchLevel
+    }
+
+    // we are an unstable release
+    if rhs.Release < lhs.Version.Release {
+        return false, ErrReleaseNumberTooSmall
+    }
+    if rhs.Release > lhs.Version.Release {
+        return true, nil
+    }
+
+    // if we get here, both versions are the same
+    return false, ErrSameVersion
+}
+
+func (lhs *VersionExpression) matchesLessThan(rhs *SemVersion) (bool, error) {
+    // are we checking a stable or an unstable release?
+    if lhs.Version.Stability == "" {
+        return lhs.matchesLessThanStable(rhs)
+    }
+
+    // we are checking an unstable release
+    return lhs.matchesLessThanUnstable(rhs)
+}
+
+func (lhs *VersionExpression) matchesLessThanStable(rhs *SemVersion) (bool, error) {
+    if !sameStability(lhs.Version.Stability, rhs.Stability) {
+        return false, ErrDifferentStabilityLevels
+    }
+    if lhs.Version.Stability != "" || rhs.Stability != "" {
+        return false, ErrUnstableVersion
+    }
+
+    // now it's just a straight-forward check of each of the numerical fields
+    // in turn
+    if lhs.Version.Major < rhs.Major {
+        return false, ErrMajorVersionTooLarge
+    }
+    if lhs.Version.Major > rhs.Major {
+        return true, nil
+    }
+
+    // at this point, lhs.X == rhs.X
+    if lhs.Version.Minor < rhs.Minor {
+        return false, ErrMinorVersionTooLarge
+    }
+    if lhs.Version.Minor > rhs.Minor {
+        return true, nil
+    }
+
+    // at this point, lhs.X.Y = rhs.X.Y
+    if lhs.Version.PatchLevel < rhs.PatchLevel {
+        return false, ErrPatchLevelTooLarge
+    }
+    if lhs.Version.PatchLevel > rhs.PatchLevel {
+        return true, nil
+    }
+
+    // at this point, lhs.X.Y.Z = rhs.X.Y.Z
+    switch comparePreReleases(rhs.PreRelease, lhs.Version.PreRelease) {
+    case 1:
+        return false, ErrPreReleaseTooLarge
+    case -1:
+        return true, nil
+    }
+
+    // if we get here, both versions are the same
+    return false, ErrSameVersion
+}
+
+func (lhs *VersionExpression) matchesLessThanUnstable(rhs *SemVersion) (bool, error) {
+    if !sameStability(lhs.Version.Stability, rhs.Stability) {
+        return false, ErrDifferentStabilityLevels
+    }
+    if lhs.Version.Stability == "" || rhs.Stability == "" {
+        return false, ErrStableVersion
+    }
+
+    // now it's just a straight-forward check of each of the numerical fields
+    // in turn
+    if lhs.Version.Major != rhs.Major {
+        return false, ErrDifferentMajorVersions
+    }
+    if lhs.Version.Minor != rhs.Minor {
+        return false, ErrDifferentMinorVersions
+    }
+    if lhs.Version.PatchLevel != rhs.PatchLevel {
+        return false, ErrDifferentPatchLevel
+    }
+
+    // we are an unstable release
+    if lhs.Version.Release < rhs.Release {
+        return false, ErrReleaseNumberTooLarge
+    }
+    if lhs.Version.Release > rhs.Release {
+        return true, nil
+    }
+
+    // if we get here, both versions are the same
+    return false, ErrSameVersion
+}
+
 func (lhs *VersionExpression) matchesCompatibleWith(rhs *SemVersion) (bool, error) {
     if lhs.Version.Stability == "" {
         return lhs.matchesCompatibleWithStable(rhs)
@@ -263,13 +526,18 @@ func (lhs *VersionExpression) matchesCompatibleWith(rhs *SemVersion) (bool, erro
 }
 
 func (lhs *VersionExpression) matchesCompatibleWithStable(rhs *SemVersion) (bool, error) {
-    if lhs.Version.Stability != rhs.Stability {
+    if !sameStability(lhs.Version.Stability, rhs.Stability) {
         return false, ErrDifferentStabilityLevels
     }
     if lhs.Version.Stability != "" || rhs.Stability != "" {
         return false, ErrUnstableVersion
     }
 
+    // npm only lets '~' match a pre-release of the same X.Y.Z
+    if TildeMode == TILDE_SAME_MINOR && !lhs.acceptsPreRelease(rhs) {
+        return false, ErrUnstableVersion
+    }
+
     if lhs.Version.Major != rhs.Major {
         return false, ErrDifferentMajorVersions
     }
@@ -278,18 +546,29 @@ func (lhs *VersionExpression) matchesCompatibleWithStable(rhs *SemVersion) (bool
         return false, ErrMinorVersionTooSmall
     }
     if rhs.Minor > lhs.Version.Minor {
+        // npm's '~' requires the same minor version too
+        if TildeMode == TILDE_SAME_MINOR {
+            return false, ErrMinorVersionTooLarge
+        }
         return true, nil
     }
 
     if rhs.PatchLevel < lhs.Version.PatchLevel {
         return false, ErrPatchLevelTooSmall
     }
+    if rhs.PatchLevel > lhs.Version.PatchLevel {
+        return true, nil
+    }
+
+    if comparePreReleases(rhs.PreRelease, lhs.Version.PreRelease) < 0 {
+        return false, ErrPreReleaseTooSmall
+    }
 
     return true, nil
 }
 
 func (lhs *VersionExpression) matchesCompatibleWithUnstable(rhs *SemVersion) (bool, error) {
-    if lhs.Version.Stability != rhs.Stability {
+    if !sameStability(lhs.Version.Stability, rhs.Stability) {
         return false, ErrDifferentStabilityLevels
     }
     if lhs.Version.Stability == "" || rhs.Stability == "" {
@@ -315,8 +594,96 @@ func (lhs *VersionExpression) matchesCompatibleWithUnstable(rhs *SemVersion) (bo
     return true, nil
 }
 
+func (lhs *VersionExpression) matchesCaret(rhs *SemVersion) (bool, error) {
+    if lhs.Version.Stability == "" {
+        return lhs.matchesCaretStable(rhs)
+    }
+
+    // unstable releases are matched exactly the same way that '~'
+    // matches them
+    return lhs.matchesCompatibleWithUnstable(rhs)
+}
+
+func (lhs *VersionExpression) matchesCaretStable(rhs *SemVersion) (bool, error) {
+    if !sameStability(lhs.Version.Stability, rhs.Stability) {
+        return false, ErrDifferentStabilityLevels
+    }
+    if lhs.Version.Stability != "" || rhs.Stability != "" {
+        return false, ErrUnstableVersion
+    }
+
+    // like npm and Cargo, we only match a pre-release of the same X.Y.Z
+    if !lhs.acceptsPreRelease(rhs) {
+        return false, ErrUnstableVersion
+    }
+
+    if ok, err := lhs.matchesCaretFixedParts(rhs); !ok {
+        return false, err
+    }
+
+    // everything to the right of it must be greater than or equal to
+    if rhs.Minor < lhs.Version.Minor {
+        return false, ErrMinorVersionTooSmall
+    }
+    if rhs.Minor > lhs.Version.Minor {
+        return true, nil
+    }
+
+    if rhs.PatchLevel < lhs.Version.PatchLevel {
+        return false, ErrPatchLevelTooSmall
+    }
+    if rhs.PatchLevel > lhs.Version.PatchLevel {
+        return true, nil
+    }
+
+    if comparePreReleases(rhs.PreRelease, lhs.Version.PreRelease) < 0 {
+        return false, ErrPreReleaseTooSmall
+    }
+
+    return true, nil
+}
+
+// matchesCaretFixedParts checks that 'rhs' has the same left-most
+// non-zero number as our version, and the same numbers to the left of it
+//
+// that is the number that breaks backwards compatibility, so it must not
+// change. A part that was left out of the expression is never the
+// left-most non-zero number, and nothing to its right is fixed either:
+// '^0' matches all 0.x.y versions, and '^0.0' matches all 0.0.z versions.
+func (lhs *VersionExpression) matchesCaretFixedParts(rhs *SemVersion) (bool, error) {
+    parts := lhs.Parts
+    if parts == 0 {
+        parts = 3
+    }
+
+    if lhs.Version.Major != rhs.Major {
+        return false, ErrDifferentMajorVersions
+    }
+    if lhs.Version.Major == 0 && parts > 1 && lhs.Version.Minor != rhs.Minor {
+        return false, ErrDifferentMinorVersions
+    }
+    if lhs.Version.Major == 0 && lhs.Version.Minor == 0 && parts > 2 && lhs.Version.PatchLevel != rhs.PatchLevel {
+        return false, ErrDifferentPatchLevel
+    }
+
+    return true, nil
+}
+
+// acceptsPreRelease follows npm's rule that a pre-release version can
+// only match an expression that asks for a pre-release of the same X.Y.Z
+func (lhs *VersionExpression) acceptsPreRelease(rhs *SemVersion) bool {
+    if rhs.PreRelease == "" {
+        return true
+    }
+
+    return lhs.Version.PreRelease != "" &&
+        lhs.Version.Major == rhs.Major &&
+        lhs.Version.Minor == rhs.Minor &&
+        lhs.Version.PatchLevel == rhs.PatchLevel
+}
+
 func (lhs *VersionExpression) matchesAnythingBut(rhs *SemVersion) (bool, error) {
-    if lhs.Version.Stability != rhs.Stability {
+    if !sameStability(lhs.Version.Stability, rhs.Stability) {
         return true, nil
     }
 
@@ -336,5 +703,9 @@ func (lhs *VersionExpression) matchesAnythingBut(rhs *SemVersion) (bool, error)
         return true, nil
     }
 
+    if lhs.Version.PreRelease != rhs.PreRelease {
+        return true, nil
+    }
+
     return false, ErrSameVersion
 }
@@ -1,6 +1,7 @@
 package semver
 
 import (
+    "errors"
     "fmt"
     "testing"
 )
@@ -37,6 +38,8 @@ func TestCanMatchUsingEquals(t *testing.T) {
         [2]string{"=2.5.99-alpha-1", "2.5.99-alpha-1"},
         [2]string{"=2.5.99-ALPHA-1", "2.5.99-ALPHA-1"},
         [2]string{"=2.5.99-SNAPSHOT-20141013", "2.5.99-SNAPSHOT-20141013"},
+        [2]string{"=1.0.0-rc.1", "1.0.0-rc.1"},
+        [2]string{"=1.0.0-rc.1", "1.0.0-rc.1+build.5"},
     }
 
     for _, matchSet := range toMatch {
@@ -86,6 +89,8 @@ func TestCannotMatchUsingEquals(t *testing.T) {
         ExpectedError{"=2.5.99", "2.5.99-BETA-2", ErrDifferentStabilityLevels},
         ExpectedError{"=2.5.99-SNAPSHOT-20141013", "2.5.99-SNAPSHOT-20141012", ErrDifferentReleaseNumbers},
         ExpectedError{"=2.5.99-SNAPSHOT-20141013", "2.5.99-SNAPSHOT-20141014", ErrDifferentReleaseNumbers},
+        ExpectedError{"=1.0.0-rc.1", "1.0.0-rc.2", ErrDifferentPreReleases},
+        ExpectedError{"=1.0.0-rc.1", "1.0.0", ErrDifferentPreReleases},
     }
 
     for _, matchSet := range toMatch {
@@ -98,7 +103,7 @@ func TestCannotMatchUsingEquals(t *testing.T) {
         actual, err := lhs.Matches(matchSet.rhs)
 
         // was an error returned?
-        if err != matchSet.err {
+        if !errors.Is(err, matchSet.err) {
             fmt.Println(lhs)
             t.Error(err)
             return
@@ -155,6 +160,11 @@ func TestCanMatchUsingGreaterThanOrEquals(t *testing.T) {
         [2]string{">=1.3.0", "1.4"},
         [2]string{">=1.3.0", "1.3.1"},
         [2]string{">=1.3-alpha-1", "1.3-alpha-2"},
+        [2]string{">=1.0.0-alpha", "1.0.0-alpha.1"},
+        [2]string{">=1.0.0-alpha.1", "1.0.0-alpha.beta"},
+        [2]string{">=1.0.0-beta.2", "1.0.0-beta.11"},
+        [2]string{">=1.0.0-rc.1", "1.0.0"},
+        [2]string{">=1.0.0-rc.1", "1.0.1-alpha"},
     }
 
     for _, matchSet := range toMatch {
@@ -204,6 +214,9 @@ func TestCannotMatchUsingGreaterThanOrEquals(t *testing.T) {
         ExpectedError{">=2.5.99-ALPHA-2", "2.5.99-ALPHA-1", ErrReleaseNumberTooSmall},
         ExpectedError{">=2.5.99-ALPHA-1", "2.5.99-BETA-2", ErrDifferentStabilityLevels},
         ExpectedError{">=2.5.99-SNAPSHOT-20141013", "2.5.99-SNAPSHOT-20141012", ErrReleaseNumberTooSmall},
+        ExpectedError{">=1.0.0-beta", "1.0.0-alpha.1", ErrPreReleaseTooSmall},
+        ExpectedError{">=1.0.0-beta.11", "1.0.0-beta.2", ErrPreReleaseTooSmall},
+        ExpectedError{">=1.0.0", "1.0.0-rc.1", ErrPreReleaseTooSmall},
     }
 
     for _, matchSet := range toMatch {
@@ -216,7 +229,7 @@ func TestCannotMatchUsingGreaterThanOrEquals(t *testing.T) {
         actual, err := lhs.Matches(matchSet.rhs)
 
         // was an error returned?
-        if err != matchSet.err {
+        if !errors.Is(err, matchSet.err) {
             fmt.Println(matchSet.lhs)
             fmt.Println(matchSet.rhs)
             t.Error(err)
@@ -273,6 +286,9 @@ func TestCanMatchUsingLessThanOrEquals(t *testing.T) {
         [2]string{"<=1.3.0", "0.9.99"},
         [2]string{"<=1.3-alpha-2", "1.3-alpha-1"},
         [2]string{"<=2.5.99-SNAPSHOT-20141013", "2.5.99-SNAPSHOT-20141012"},
+        [2]string{"<=1.0.0", "1.0.0-rc.1"},
+        [2]string{"<=1.0.0-rc.1", "1.0.0-beta.11"},
+        [2]string{"<=1.0.0-alpha.beta", "1.0.0-alpha.1"},
     }
 
     for _, matchSet := range toMatch {
@@ -322,6 +338,8 @@ func TestCannotMatchUsingLessThanOrEquals(t *testing.T) {
         ExpectedError{"<=2.5.99-ALPHA-1", "2.5.99-ALPHA-2", ErrReleaseNumberTooLarge},
         ExpectedError{"<=2.5.99-ALPHA-1", "2.5.99-BETA-2", ErrDifferentStabilityLevels},
         ExpectedError{"<=2.5.99-SNAPSHOT-20141013", "2.5.99-SNAPSHOT-20141014", ErrReleaseNumberTooLarge},
+        ExpectedError{"<=1.0.0-rc.1", "1.0.0", ErrPreReleaseTooLarge},
+        ExpectedError{"<=1.0.0-alpha", "1.0.0-alpha.1", ErrPreReleaseTooLarge},
     }
 
     for _, matchSet := range toMatch {
@@ -334,7 +352,7 @@ func TestCannotMatchUsingLessThanOrEquals(t *testing.T) {
         actual, err := lhs.Matches(matchSet.rhs)
 
         // was an error returned?
-        if err != matchSet.err {
+        if !errors.Is(err, matchSet.err) {
             fmt.Println(matchSet.lhs)
             fmt.Println(matchSet.rhs)
             t.Error(err)
@@ -387,6 +405,8 @@ func TestCanMatchUsingTilde(t *testing.T) {
         [2]string{"~1.3.0", "1.9.99"},
         [2]string{"~1.3-alpha-2", "1.3-alpha-3"},
         [2]string{"~2.5.99-SNAPSHOT-20141013", "2.5.99-SNAPSHOT-20141014"},
+        [2]string{"~1.0.0-rc.1", "1.0.0-rc.2"},
+        [2]string{"~1.0.0-rc.1", "1.2.0"},
     }
 
     for _, matchSet := range toMatch {
@@ -437,6 +457,8 @@ func TestCannotMatchUsingTilde(t *testing.T) {
         ExpectedError{"~2.5.99-ALPHA-1", "2.5.99-BETA-2", ErrDifferentStabilityLevels},
         ExpectedError{"~2.5.99-RC-1", "2.5.99", ErrDifferentStabilityLevels},
         ExpectedError{"~2.5.99-SNAPSHOT-20141013", "2.5.99-SNAPSHOT-20141012", ErrReleaseNumberTooSmall},
+        ExpectedError{"~1.0.0-rc.2", "1.0.0-rc.1", ErrPreReleaseTooSmall},
+        ExpectedError{"~1.0.0-rc.1", "2.0.0", ErrDifferentMajorVersions},
     }
 
     for _, matchSet := range toMatch {
@@ -449,7 +471,7 @@ func TestCannotMatchUsingTilde(t *testing.T) {
         actual, err := lhs.Matches(matchSet.rhs)
 
         // was an error returned?
-        if err != matchSet.err {
+        if !errors.Is(err, matchSet.err) {
             fmt.Println(matchSet.lhs)
             fmt.Println(matchSet.rhs)
             t.Error(err)
@@ -491,6 +513,8 @@ func TestCanMatchUsingNotEquals(t *testing.T) {
         [2]string{"!=2.6-alpha-1", "2.6.0"},
         [2]string{"!=2.6.0", "2.6-alpha-1"},
         [2]string{"!=2.6.0", "2.6.0-alpha-1"},
+        [2]string{"!=1.0.0-rc.1", "1.0.0-rc.2"},
+        [2]string{"!=1.0.0-rc.1", "1.0.0"},
     }
 
     for _, matchSet := range toMatch {
@@ -532,6 +556,7 @@ func TestCannotMatchUsingNotEquals(t *testing.T) {
         ExpectedError{"!=1.3.1", "1.3.1", ErrSameVersion},
         ExpectedError{"!=2.6-alpha-2", "2.6-alpha-2", ErrSameVersion},
         ExpectedError{"!=2.6-alpha-2", "2.6.0-alpha-2", ErrSameVersion},
+        ExpectedError{"!=1.0.0-rc.1", "1.0.0-rc.1+build.5", ErrSameVersion},
     }
 
     for _, matchSet := range toMatch {
@@ -544,7 +569,431 @@ func TestCannotMatchUsingNotEquals(t *testing.T) {
         actual, err := lhs.Matches(matchSet.rhs)
 
         // was an error returned?
-        if err != matchSet.err {
+        if !errors.Is(err, matchSet.err) {
+            fmt.Println(matchSet.lhs)
+            fmt.Println(matchSet.rhs)
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != false {
+            t.Errorf("Expected %d, received %d", false, actual)
+            return
+        }
+    }
+}
+
+// ========================================================================
+//
+// Compare two versions using the greater than operator
+//
+// ------------------------------------------------------------------------
+
+func TestCanMatchUsingGreaterThan(t *testing.T) {
+    // what result do we expect?
+    expected := true
+
+    // our list of strings to match
+    //
+    // LHS contains the operator
+    // RHS contains only a version number to compare against
+    //
+    // all of these pairs should match
+    var toMatch = [][2]string{
+        [2]string{">1.3", "1.3.1"},
+        [2]string{">1.3", "1.4"},
+        [2]string{">1.3", "2.0.0"},
+        [2]string{">1.4.2", "1.4.3"},
+        [2]string{">1.4.2", "1.5.0"},
+        [2]string{">2.6-alpha-1", "2.6-alpha-2"},
+        [2]string{">2.6-alpha-1", "2.6.0-alpha-2"},
+        [2]string{">2.5.99-SNAPSHOT-20141013", "2.5.99-SNAPSHOT-20141014"},
+        [2]string{">1.0.0-rc.1", "1.0.0-rc.2"},
+        [2]string{">1.0.0-rc.1", "1.0.0"},
+    }
+
+    for _, matchSet := range toMatch {
+        // perform the test
+        lhs, err := ParseExpression(matchSet[0])
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual, err := lhs.Matches(matchSet[1])
+
+        // was an error returned?
+        if err != nil {
+            fmt.Println(lhs)
+            fmt.Println(matchSet[1])
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != expected {
+            t.Errorf("Expected %d, received %d", expected, actual)
+            return
+        }
+    }
+}
+
+func TestCannotMatchUsingGreaterThan(t *testing.T) {
+    // our list of strings to compare
+    //
+    // LHS contains the operator
+    // RHS contains only a version number to compare against
+    //
+    // all of these pairs should not match
+    var toMatch = []ExpectedError{
+        ExpectedError{">1.3", "1.3", ErrSameVersion},
+        ExpectedError{">1.3", "1.3.0", ErrSameVersion},
+        ExpectedError{">1.4.2", "1.4.2", ErrSameVersion},
+        ExpectedError{">1.4.2", "0.9", ErrMajorVersionTooSmall},
+        ExpectedError{">1.4.2", "1.3.9", ErrMinorVersionTooSmall},
+        ExpectedError{">1.4.2", "1.4.1", ErrPatchLevelTooSmall},
+        ExpectedError{">1.4.2", "1.4.3-rc-1", ErrDifferentStabilityLevels},
+        ExpectedError{">2.6-alpha-2", "2.6-alpha-2", ErrSameVersion},
+        ExpectedError{">2.6-alpha-2", "2.6-alpha-1", ErrReleaseNumberTooSmall},
+        ExpectedError{">2.6-alpha-2", "2.7-alpha-3", ErrDifferentMinorVersions},
+        ExpectedError{">2.6-alpha-2", "2.6-beta-3", ErrDifferentStabilityLevels},
+        ExpectedError{">1.0.0-rc.2", "1.0.0-rc.1", ErrPreReleaseTooSmall},
+        ExpectedError{">1.0.0-rc.1", "1.0.0-rc.1+build.5", ErrSameVersion},
+    }
+
+    for _, matchSet := range toMatch {
+        // perform the test
+        lhs, err := ParseExpression(matchSet.lhs)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual, err := lhs.Matches(matchSet.rhs)
+
+        // was an error returned?
+        if !errors.Is(err, matchSet.err) {
+            fmt.Println(matchSet.lhs)
+            fmt.Println(matchSet.rhs)
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != false {
+            t.Errorf("Expected %d, received %d", false, actual)
+            return
+        }
+    }
+}
+
+// ========================================================================
+//
+// Compare two versions using the less than operator
+//
+// ------------------------------------------------------------------------
+
+func TestCanMatchUsingLessThan(t *testing.T) {
+    // what result do we expect?
+    expected := true
+
+    // our list of strings to match
+    //
+    // LHS contains the operator
+    // RHS contains only a version number to compare against
+    //
+    // all of these pairs should match
+    var toMatch = [][2]string{
+        [2]string{"<1.3", "1.2.99"},
+        [2]string{"<1.3", "1.2"},
+        [2]string{"<1.3", "0.9"},
+        [2]string{"<1.4.2", "1.4.1"},
+        [2]string{"<1.4.2", "1.3.0"},
+        [2]string{"<2.6-alpha-2", "2.6-alpha-1"},
+        [2]string{"<2.6-alpha-2", "2.6.0-alpha-1"},
+        [2]string{"<2.5.99-SNAPSHOT-20141013", "2.5.99-SNAPSHOT-20141012"},
+        [2]string{"<1.0.0-rc.2", "1.0.0-rc.1"},
+        [2]string{"<1.0.0", "1.0.0-rc.1"},
+    }
+
+    for _, matchSet := range toMatch {
+        // perform the test
+        lhs, err := ParseExpression(matchSet[0])
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual, err := lhs.Matches(matchSet[1])
+
+        // was an error returned?
+        if err != nil {
+            fmt.Println(lhs)
+            fmt.Println(matchSet[1])
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != expected {
+            t.Errorf("Expected %d, received %d", expected, actual)
+            return
+        }
+    }
+}
+
+func TestCannotMatchUsingLessThan(t *testing.T) {
+    // our list of strings to compare
+    //
+    // LHS contains the operator
+    // RHS contains only a version number to compare against
+    //
+    // all of these pairs should not match
+    var toMatch = []ExpectedError{
+        ExpectedError{"<1.3", "1.3", ErrSameVersion},
+        ExpectedError{"<1.3", "1.3.0", ErrSameVersion},
+        ExpectedError{"<1.4.2", "1.4.2", ErrSameVersion},
+        ExpectedError{"<1.4.2", "2.0", ErrMajorVersionTooLarge},
+        ExpectedError{"<1.4.2", "1.5.0", ErrMinorVersionTooLarge},
+        ExpectedError{"<1.4.2", "1.4.3", ErrPatchLevelTooLarge},
+        ExpectedError{"<1.4.2", "1.4.1-rc-1", ErrDifferentStabilityLevels},
+        ExpectedError{"<2.6-alpha-2", "2.6-alpha-2", ErrSameVersion},
+        ExpectedError{"<2.6-alpha-2", "2.6-alpha-3", ErrReleaseNumberTooLarge},
+        ExpectedError{"<2.6-alpha-2", "2.5-alpha-1", ErrDifferentMinorVersions},
+        ExpectedError{"<1.0.0-rc.1", "1.0.0-rc.2", ErrPreReleaseTooLarge},
+        ExpectedError{"<1.0.0-rc.1", "1.0.0", ErrPreReleaseTooLarge},
+    }
+
+    for _, matchSet := range toMatch {
+        // perform the test
+        lhs, err := ParseExpression(matchSet.lhs)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual, err := lhs.Matches(matchSet.rhs)
+
+        // was an error returned?
+        if !errors.Is(err, matchSet.err) {
+            fmt.Println(matchSet.lhs)
+            fmt.Println(matchSet.rhs)
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != false {
+            t.Errorf("Expected %d, received %d", false, actual)
+            return
+        }
+    }
+}
+
+// ========================================================================
+//
+// Compare two versions using the caret operator
+//
+// ------------------------------------------------------------------------
+
+func TestCanMatchUsingCaret(t *testing.T) {
+    // what result do we expect?
+    expected := true
+
+    // our list of strings to match
+    //
+    // LHS contains the operator
+    // RHS contains only a version number to compare against
+    //
+    // all of these pairs should match
+    var toMatch = [][2]string{
+        [2]string{"^1.2.3", "1.2.3"},
+        [2]string{"^1.2.3", "1.2.4"},
+        [2]string{"^1.2.3", "1.9.0"},
+        [2]string{"^1.2", "1.2.0"},
+        [2]string{"^1.2", "1.99.99"},
+        [2]string{"^0.2.3", "0.2.3"},
+        [2]string{"^0.2.3", "0.2.99"},
+        [2]string{"^0.0.3", "0.0.3"},
+        [2]string{"^1.2", "1.2.7"},
+        [2]string{"^0.0", "0.0.0"},
+        [2]string{"^0.0", "0.0.5"},
+        [2]string{"^0", "0.0.5"},
+        [2]string{"^0", "0.9.1"},
+        [2]string{"^1", "1.0.0"},
+        [2]string{"^1", "1.9.0"},
+        [2]string{"^1.2.3-beta.2", "1.2.3-beta.4"},
+        [2]string{"^1.2.3-beta.2", "1.2.3"},
+        [2]string{"^1.2.3-beta.2", "1.3.0"},
+        [2]string{"^2.6-alpha-1", "2.6-alpha-2"},
+    }
+
+    for _, matchSet := range toMatch {
+        // perform the test
+        lhs, err := ParseExpression(matchSet[0])
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual, err := lhs.Matches(matchSet[1])
+
+        // was an error returned?
+        if err != nil {
+            fmt.Println(lhs)
+            fmt.Println(matchSet[1])
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != expected {
+            t.Errorf("Expected %d, received %d", expected, actual)
+            return
+        }
+    }
+}
+
+func TestCannotMatchUsingCaret(t *testing.T) {
+    // our list of strings to compare
+    //
+    // LHS contains the operator
+    // RHS contains only a version number to compare against
+    //
+    // all of these pairs should not match
+    var toMatch = []ExpectedError{
+        ExpectedError{"^1.2.3", "2.0.0", ErrDifferentMajorVersions},
+        ExpectedError{"^1.2.3", "0.9.0", ErrDifferentMajorVersions},
+        ExpectedError{"^1.2.3", "1.1.9", ErrMinorVersionTooSmall},
+        ExpectedError{"^1.2.3", "1.2.2", ErrPatchLevelTooSmall},
+        ExpectedError{"^1.2.3", "1.3.0-rc.1", ErrUnstableVersion},
+        ExpectedError{"^1.2.3", "1.3.0-rc-1", ErrDifferentStabilityLevels},
+        ExpectedError{"^0.2.3", "0.3.0", ErrDifferentMinorVersions},
+        ExpectedError{"^0.2.3", "1.2.3", ErrDifferentMajorVersions},
+        ExpectedError{"^0.2.3", "0.2.2", ErrPatchLevelTooSmall},
+        ExpectedError{"^0.0.3", "0.0.4", ErrDifferentPatchLevel},
+        ExpectedError{"^0.0.3", "0.1.0", ErrDifferentMinorVersions},
+        ExpectedError{"^1.2", "2.0.0", ErrDifferentMajorVersions},
+        ExpectedError{"^1.2", "1.1.9", ErrMinorVersionTooSmall},
+        ExpectedError{"^0.0", "0.1.0", ErrDifferentMinorVersions},
+        ExpectedError{"^0.0", "0.0.5-rc.1", ErrUnstableVersion},
+        ExpectedError{"^0", "1.0.0", ErrDifferentMajorVersions},
+        ExpectedError{"^1.2.3-beta.2", "1.2.4-beta.2", ErrUnstableVersion},
+        ExpectedError{"^1.2.3-beta.2", "1.2.3-beta.1", ErrPreReleaseTooSmall},
+        ExpectedError{"^2.6-alpha-2", "2.6-alpha-1", ErrReleaseNumberTooSmall},
+        ExpectedError{"^2.6-alpha-1", "2.6.0", ErrDifferentStabilityLevels},
+    }
+
+    for _, matchSet := range toMatch {
+        // perform the test
+        lhs, err := ParseExpression(matchSet.lhs)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual, err := lhs.Matches(matchSet.rhs)
+
+        // was an error returned?
+        if !errors.Is(err, matchSet.err) {
+            fmt.Println(matchSet.lhs)
+            fmt.Println(matchSet.rhs)
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != false {
+            t.Errorf("Expected %d, received %d", false, actual)
+            return
+        }
+    }
+}
+
+
+// ========================================================================
+//
+// Compare two versions using the npm flavour of the tilde operator
+//
+// ------------------------------------------------------------------------
+
+func TestCanMatchUsingNpmTilde(t *testing.T) {
+    // npm's '~' only accepts newer patch levels
+    TildeMode = TILDE_SAME_MINOR
+    defer func() { TildeMode = TILDE_SAME_MAJOR }()
+
+    // what result do we expect?
+    expected := true
+
+    // our list of strings to match
+    //
+    // LHS contains the operator
+    // RHS contains only a version number to compare against
+    //
+    // all of these pairs should match
+    var toMatch = [][2]string{
+        [2]string{"~1.2.3", "1.2.3"},
+        [2]string{"~1.2.3", "1.2.99"},
+        [2]string{"~1.2", "1.2.0"},
+        [2]string{"~1.2", "1.2.5"},
+        [2]string{"~0.2.3", "0.2.4"},
+        [2]string{"~1.2.3-beta.2", "1.2.3-beta.4"},
+        [2]string{"~1.2.3-beta.2", "1.2.4"},
+        [2]string{"~2.6-alpha-1", "2.6-alpha-2"},
+    }
+
+    for _, matchSet := range toMatch {
+        // perform the test
+        lhs, err := ParseExpression(matchSet[0])
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual, err := lhs.Matches(matchSet[1])
+
+        // was an error returned?
+        if err != nil {
+            fmt.Println(lhs)
+            fmt.Println(matchSet[1])
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != expected {
+            t.Errorf("Expected %d, received %d", expected, actual)
+            return
+        }
+    }
+}
+
+func TestCannotMatchUsingNpmTilde(t *testing.T) {
+    // npm's '~' only accepts newer patch levels
+    TildeMode = TILDE_SAME_MINOR
+    defer func() { TildeMode = TILDE_SAME_MAJOR }()
+
+    // our list of strings to compare
+    //
+    // LHS contains the operator
+    // RHS contains only a version number to compare against
+    //
+    // all of these pairs should not match
+    var toMatch = []ExpectedError{
+        ExpectedError{"~1.2.3", "1.3.0", ErrMinorVersionTooLarge},
+        ExpectedError{"~1.2.3", "1.1.0", ErrMinorVersionTooSmall},
+        ExpectedError{"~1.2.3", "1.2.2", ErrPatchLevelTooSmall},
+        ExpectedError{"~1.2.3", "2.2.3", ErrDifferentMajorVersions},
+        ExpectedError{"~1.2", "1.3", ErrMinorVersionTooLarge},
+        ExpectedError{"~1.2.3", "1.2.4-rc.1", ErrUnstableVersion},
+        ExpectedError{"~1.2.3-beta.2", "1.2.4-beta.2", ErrUnstableVersion},
+    }
+
+    for _, matchSet := range toMatch {
+        // perform the test
+        lhs, err := ParseExpression(matchSet.lhs)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual, err := lhs.Matches(matchSet.rhs)
+
+        // was an error returned?
+        if !errors.Is(err, matchSet.err) {
             fmt.Println(matchSet.lhs)
             fmt.Println(matchSet.rhs)
             t.Error(err)
@@ -0,0 +1,229 @@
+package semver
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+)
+
+// Constraint holds the result of a parsed list of version expressions,
+// combined using AND and OR
+//
+// create one by calling:
+//
+//     c = semver.ParseConstraint(">=1.2, <=1.9 || ~2.0")
+//
+// A version matches the constraint if it matches every expression in at
+// least one of the alternatives.
+type Constraint struct {
+    // each alternative is a list of clauses that must all match
+    Alternatives [][]VersionExpression
+}
+
+// ConstraintError is returned when a version does not match a Constraint
+//
+// It holds one ClauseFailure for each of the Constraint's alternatives,
+// explaining which clause failed, and why. errors.Is() works with all of
+// the Err* values that the clauses failed with.
+type ConstraintError struct {
+    Failures []ClauseFailure
+}
+
+// ClauseFailure describes the first clause in one of a Constraint's
+// alternatives that a version did not match
+type ClauseFailure struct {
+    Alternative int               // index into Constraint.Alternatives
+    Clause      int               // index into the alternative's clauses
+    Expression  VersionExpression // the clause that did not match
+    Err         error             // why it did not match
+}
+
+// what we expect to find after a clause, if it is not the end of the
+// constraint
+const expectAfterClause = "',', '||' or end of constraint"
+
+// ParseConstraint converts a constraint string into a Constraint struct.
+//
+// Takes a constraint of the form:
+//
+//     <expression>[, <expression> ...] [|| <expression>[, <expression> ...]]
+//
+// where each <expression> is anything that ParseExpression() accepts.
+// Expressions separated by a comma or by whitespace must all match (AND),
+// except for the ' - ' in the middle of a hyphen range.
+// Lists of expressions separated by '||' are alternatives (OR).
+//
+// returns a *ParseError if the constraint cannot be parsed; its Offset is
+// relative to the start of the constraint
+func ParseConstraint(raw string) (Constraint, error) {
+    var constraint Constraint
+    var clauses []VersionExpression
+
+    pos := skipSpaces(raw, 0)
+    for {
+        // find the end of this clause
+        end := pos
+        for end < len(raw) && !isClauseSeparator(raw[end]) {
+            end++
+        }
+        end = hyphenRangeEnd(raw, end)
+        if end == pos {
+            return Constraint{}, newParseError(raw, pos, expectOperator)
+        }
+
+        exp, err := parseExpressionWithOffset(raw[:end], pos)
+        if err != nil {
+            // make sure the caller sees the whole constraint
+            var parseErr *ParseError
+            if errors.As(err, &parseErr) {
+                parseErr.Input = raw
+            }
+            return Constraint{}, err
+        }
+        clauses = append(clauses, exp)
+
+        // what comes next?
+        pos = skipSpaces(raw, end)
+        switch {
+        case pos >= len(raw):
+            constraint.Alternatives = append(constraint.Alternatives, clauses)
+            return constraint, nil
+
+        case raw[pos] == ',':
+            pos = skipSpaces(raw, pos+1)
+
+        case strings.HasPrefix(raw[pos:], "||"):
+            constraint.Alternatives = append(constraint.Alternatives, clauses)
+            clauses = nil
+            pos = skipSpaces(raw, pos+2)
+
+        case pos == end:
+            // clauses must be separated by something
+            return Constraint{}, newParseError(raw, pos, expectAfterClause)
+        }
+    }
+}
+
+// hyphenRangeEnd checks for the ' - ' in the middle of a hyphen range
+//
+// returns the offset of the end of the hyphen range if there is one, or
+// 'end' if there isn't
+func hyphenRangeEnd(raw string, end int) int {
+    pos := skipSpaces(raw, end)
+    if pos == end || pos+1 >= len(raw) || raw[pos] != '-' || !isSpace(raw[pos+1]) {
+        return end
+    }
+
+    pos = skipSpaces(raw, pos+1)
+    for pos < len(raw) && !isClauseSeparator(raw[pos]) {
+        pos++
+    }
+
+    return pos
+}
+
+// skipSpaces returns the offset of the first non-whitespace character at
+// or after raw[pos]
+func skipSpaces(raw string, pos int) int {
+    for pos < len(raw) && isSpace(raw[pos]) {
+        pos++
+    }
+
+    return pos
+}
+
+// isSpace returns true if 'c' is whitespace
+func isSpace(c byte) bool {
+    return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// isClauseSeparator returns true if 'c' marks the end of a clause
+func isClauseSeparator(c byte) bool {
+    return isSpace(c) || c == ',' || c == '|'
+}
+
+// Matches checks to see if 'version' matches the constraint that we have
+// already parsed.
+//
+// this is a convenience method around 'MatchesVersion', to avoid parsing
+// the 'version' string yourself first
+//
+// returns 'true' if the version matches the constraint
+// returns 'false' plus a *ConstraintError if the version does not match
+func (c *Constraint) Matches(version string) (bool, error) {
+    // we need to turn our raw string into a comparison struct first
+    rhs, err := ParseVersion(version)
+    if err != nil {
+        return false, err
+    }
+
+    return c.MatchesVersion(&rhs)
+}
+
+// MatchesVersion checks to see if 'version' matches the constraint that
+// we have already parsed.
+//
+// returns 'true' if the version matches every clause in at least one of
+// the alternatives
+// returns 'false' plus a *ConstraintError if the version does not match
+func (c *Constraint) MatchesVersion(rhs *SemVersion) (bool, error) {
+    var failures []ClauseFailure
+
+    for i, clauses := range c.Alternatives {
+        failure, ok := matchesAllClauses(clauses, rhs)
+        if ok {
+            return true, nil
+        }
+
+        failure.Alternative = i
+        failures = append(failures, failure)
+    }
+
+    return false, &ConstraintError{failures}
+}
+
+// matchesAllClauses checks 'version' against each clause in turn
+//
+// returns 'true' if all of the clauses match, or 'false' plus details of
+// the first clause that did not match
+func matchesAllClauses(clauses []VersionExpression, rhs *SemVersion) (ClauseFailure, bool) {
+    for i := range clauses {
+        ok, err := clauses[i].MatchesVersion(rhs)
+        if !ok {
+            return ClauseFailure{Clause: i, Expression: clauses[i], Err: err}, false
+        }
+    }
+
+    return ClauseFailure{}, true
+}
+
+// Error explains which clause failed in each of the alternatives
+func (e *ConstraintError) Error() string {
+    if len(e.Failures) == 0 {
+        return "constraint has no alternatives to match"
+    }
+
+    reasons := make([]string, len(e.Failures))
+    for i, failure := range e.Failures {
+        reasons[i] = failure.String()
+    }
+
+    return strings.Join(reasons, "; ")
+}
+
+// Is lets errors.Is() look for any of the Err* values that the clauses
+// failed with
+func (e *ConstraintError) Is(target error) bool {
+    for _, failure := range e.Failures {
+        if errors.Is(failure.Err, target) {
+            return true
+        }
+    }
+
+    return false
+}
+
+// String explains which clause failed, and why
+func (f ClauseFailure) String() string {
+    return fmt.Sprintf("alternative %d, clause %d: %v", f.Alternative+1, f.Clause+1, f.Err)
+}
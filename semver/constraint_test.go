@@ -0,0 +1,263 @@
+package semver
+
+import (
+    "errors"
+    "testing"
+)
+
+// ========================================================================
+//
+// Tests for ParseConstraint()
+//
+// ------------------------------------------------------------------------
+
+func TestCanParseSingleExpressionConstraint(t *testing.T) {
+    // what result do we expect?
+    expected := VersionExpression{
+        Operator: OP_GT_EQUALS,
+        Version: SemVersion{
+            Major: 1,
+            Minor: 3,
+        },
+    }
+
+    // perform the test
+    actual, err := ParseConstraint(">=1.3")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if len(actual.Alternatives) != 1 || len(actual.Alternatives[0]) != 1 {
+        t.Errorf("Expected 1 alternative with 1 clause, received %v", actual.Alternatives)
+        return
+    }
+    if actual.Alternatives[0][0] != expected {
+        t.Errorf("Expected %v, received %v", expected, actual.Alternatives[0][0])
+        return
+    }
+}
+
+func TestCanParseCompoundConstraints(t *testing.T) {
+    // our list of constraints to parse, and how many clauses we expect to
+    // find in each alternative
+    var toParse = []struct {
+        raw      string
+        expected []int
+    }{
+        {">=1.2, <=1.9", []int{2}},
+        {">=1.2,<=1.9", []int{2}},
+        {">=1.2 <=1.9", []int{2}},
+        {"  >=1.2 ,  <=1.9  ", []int{2}},
+        {"~1.3 || ~2.0", []int{1, 1}},
+        {"~1.3||~2.0", []int{1, 1}},
+        {">=1.2, <=1.9 || =2.0.0-rc.1 || >=3.0 !=3.0.5", []int{2, 1, 2}},
+    }
+
+    for _, set := range toParse {
+        // perform the test
+        actual, err := ParseConstraint(set.raw)
+
+        // was an error returned?
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if len(actual.Alternatives) != len(set.expected) {
+            t.Errorf("%q: expected %d alternatives, received %d", set.raw, len(set.expected), len(actual.Alternatives))
+            return
+        }
+        for i, clauses := range actual.Alternatives {
+            if len(clauses) != set.expected[i] {
+                t.Errorf("%q: expected %d clauses in alternative %d, received %d", set.raw, set.expected[i], i, len(clauses))
+                return
+            }
+        }
+    }
+}
+
+func TestParseConstraintReportsOffsetIntoConstraint(t *testing.T) {
+    // our list of constraints to parse, and where we expect parsing to fail
+    var toParse = []ParseError{
+        ParseError{"", 0, expectOperator, nil},
+        ParseError{">=1.2,", 6, expectOperator, nil},
+        ParseError{">=1.2 ||", 8, expectOperator, nil},
+        ParseError{">=1.2 || || ~2.0", 9, expectOperator, nil},
+        ParseError{">=1.2, <=1.x", 11, expectMinor, nil},
+        ParseError{">=1.2, <=1.9x", 12, "'.', '-', '+' or end of version string", nil},
+        ParseError{">=1.2 || 2.0", 9, expectOperator, nil},
+        ParseError{">=1.2|~2.0", 5, expectAfterClause, nil},
+    }
+
+    for _, expected := range toParse {
+        // perform the test
+        _, err := ParseConstraint(expected.Input)
+
+        // did we get back what we expected?
+        var actual *ParseError
+        if !errors.As(err, &actual) {
+            t.Errorf("Expected a ParseError for %q, received %v", expected.Input, err)
+            return
+        }
+        if *actual != expected {
+            t.Errorf("Expected %+v, received %+v", expected, *actual)
+            return
+        }
+    }
+}
+
+// ========================================================================
+//
+// Tests for Constraint.Matches()
+//
+// ------------------------------------------------------------------------
+
+func TestCanMatchUsingConstraint(t *testing.T) {
+    // what result do we expect?
+    expected := true
+
+    // our list of strings to match
+    //
+    // LHS contains the constraint
+    // RHS contains only a version number to compare against
+    //
+    // all of these pairs should match
+    var toMatch = [][2]string{
+        [2]string{">=1.2, <=1.9", "1.2"},
+        [2]string{">=1.2, <=1.9", "1.5.3"},
+        [2]string{">=1.2, <=1.9", "1.9.0"},
+        [2]string{">=1.2 <=1.9 !=1.5.3", "1.5.4"},
+        [2]string{"~1.3 || ~2.0", "1.3.1"},
+        [2]string{"~1.3 || ~2.0", "2.4.0"},
+        [2]string{"=1.0 || ~2.6-alpha-1", "2.6-alpha-3"},
+    }
+
+    for _, matchSet := range toMatch {
+        // perform the test
+        lhs, err := ParseConstraint(matchSet[0])
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual, err := lhs.Matches(matchSet[1])
+
+        // was an error returned?
+        if err != nil {
+            t.Errorf("%s %s: %v", matchSet[0], matchSet[1], err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != expected {
+            t.Errorf("Expected %v, received %v", expected, actual)
+            return
+        }
+    }
+}
+
+func TestCannotMatchUsingConstraint(t *testing.T) {
+    // our list of strings to compare
+    //
+    // LHS contains the constraint
+    // RHS contains only a version number to compare against
+    //
+    // all of these pairs should not match
+    var toMatch = []ExpectedError{
+        ExpectedError{">=1.2, <=1.9", "1.1", ErrMinorVersionTooSmall},
+        ExpectedError{">=1.2, <=1.9", "1.9.1", ErrPatchLevelTooLarge},
+        ExpectedError{">=1.2, <=1.9", "2.0", ErrMajorVersionTooLarge},
+        ExpectedError{">=1.2 <=1.9 !=1.5.3", "1.5.3", ErrSameVersion},
+        ExpectedError{"~1.3 || ~2.0", "3.0", ErrDifferentMajorVersions},
+        ExpectedError{"~1.3 || ~2.0", "1.2", ErrMinorVersionTooSmall},
+    }
+
+    for _, matchSet := range toMatch {
+        // perform the test
+        lhs, err := ParseConstraint(matchSet.lhs)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual, err := lhs.Matches(matchSet.rhs)
+
+        // was an error returned?
+        if !errors.Is(err, matchSet.err) {
+            t.Errorf("%s %s: expected %v, received %v", matchSet.lhs, matchSet.rhs, matchSet.err, err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != false {
+            t.Errorf("Expected %v, received %v", false, actual)
+            return
+        }
+    }
+}
+
+func TestConstraintErrorReportsWhichClauseFailed(t *testing.T) {
+    // what result do we expect?
+    expected := []ClauseFailure{
+        ClauseFailure{
+            Alternative: 0,
+            Clause:      1,
+            Expression:  VersionExpression{Operator: OP_LT_EQUALS, Version: SemVersion{Major: 1, Minor: 9}},
+            Err:         ErrMajorVersionTooLarge,
+        },
+        ClauseFailure{
+            Alternative: 1,
+            Clause:      0,
+            Expression:  VersionExpression{Operator: OP_GT_EQUALS, Version: SemVersion{Major: 3}},
+            Err:         ErrMajorVersionTooSmall,
+        },
+    }
+
+    // perform the test
+    lhs, err := ParseConstraint(">=1.2, <=1.9 || >=3.0")
+    if err != nil {
+        t.Error(err)
+        return
+    }
+    _, err = lhs.Matches("2.0")
+
+    // did we get back what we expected?
+    var actual *ConstraintError
+    if !errors.As(err, &actual) {
+        t.Errorf("Expected a ConstraintError, received %v", err)
+        return
+    }
+    if len(actual.Failures) != len(expected) {
+        t.Errorf("Expected %v, received %v", expected, actual.Failures)
+        return
+    }
+    for i := range expected {
+        failure := actual.Failures[i]
+        if failure.Alternative != expected[i].Alternative || failure.Clause != expected[i].Clause || failure.Expression != expected[i].Expression || !errors.Is(failure.Err, expected[i].Err) {
+            t.Errorf("Expected %v, received %v", expected[i], actual.Failures[i])
+            return
+        }
+    }
+}
+
+func TestConstraintErrorExplainsWhatWentWrong(t *testing.T) {
+    // what result do we expect?
+    expected := "alternative 1, clause 2: 2.0.0 does not satisfy <=1.9.0: major version 2 is above 1; alternative 2, clause 1: 2.0.0 does not satisfy >=3.0.0: major version 2 is below 3"
+
+    // perform the test
+    lhs, err := ParseConstraint(">=1.2, <=1.9 || >=3.0")
+    if err != nil {
+        t.Error(err)
+        return
+    }
+    _, err = lhs.Matches("2.0")
+
+    // did we get back what we expected?
+    if err == nil || err.Error() != expected {
+        t.Errorf("Expected %s, received %v", expected, err)
+        return
+    }
+}
@@ -29,6 +29,32 @@
 //     1.0.0
 //     1.1.0-SNAPSHOT-20141013
 //
+// Semantic Versioning 2.0.0 version strings are also understood:
+//
+//     X.Y.Z-<pre.release>+<build.metadata>
+//
+// The dot-separated pre-release identifiers are stored in
+// SemVersion.PreRelease, and the build metadata in SemVersion.Build.
+// Anything that looks like 'X.Y.Z-stability-R' is still parsed into the
+// Stability and Release fields; use ParseSemVer2() if you want the spec's
+// grammar applied exactly.
+//
+// Pre-releases are compared using the precedence rules from the spec:
+//
+//     1.0.0-alpha < 1.0.0-alpha.1 < 1.0.0-alpha.beta < 1.0.0-beta <
+//     1.0.0-beta.2 < 1.0.0-beta.11 < 1.0.0-rc.1 < 1.0.0
+//
+// Build metadata is ignored when comparing versions.
+//
+// The numbers in a version string must fit into an int. If they don't,
+// ParseVersion() returns a *ParseError that wraps ErrNumberOverflow. Use
+// ParseBigVersion() instead if you need to work with numbers that large.
+//
+// Coerce() finds the best-fit version in messy version strings that
+// other tools produce, such as 'v1.2', 'release-1.4.0', '1.2.3.4',
+// '1.2.3_beta2' or '2.1rc1'. CoerceWithReport() also tells you which
+// parts of the string were thrown away.
+//
 // Comparisons
 //
 // The semver package also includes support for comparing two version
@@ -36,10 +62,15 @@
 //
 //     =  : requires exact match
 //     >= : any version that's greater than or equal to
+//     >  : any version that's greater than
 //     <= : any version that's less than or equal to
+//     <  : any version that's less than
 //     ~  : any version that's greater than or equal to, and has the same
 //          major version number
+//     ^  : any version that's greater than or equal to, and has the same
+//          left-most non-zero version number
 //     != : any version that is different in any way
+//     @  : a specific branch, tag or commit ID
 //
 // For example:
 //
@@ -53,6 +84,9 @@
 //     >=1.3.0-alpha-1 : matches '1.3-alpha-1', '1.3.0-alpha-1',
 //                      '1.3.0-alpha-2' and so on
 //
+//     >1.4.2 : matches '1.4.3', '1.5', '2.0.0' and so on, but not '1.4.2'
+//     >1.3.0-alpha-1 : matches '1.3.0-alpha-2', '1.3.0-alpha-3' and so on
+//
 //     <=1.3 : matches '1.3', '1.3.0', '1.2.99999', '1.1.0', '0.9' and
 //             so on
 //     <=1.3.0 : matches '1.3', '1.3.0', '1.2.99999', '1.1.0', '0.9' and
@@ -60,6 +94,9 @@
 //     <=1.3.0-alpha-2 : matches '1.3-alpha-2', '1.3.0-alpha-2',
 //                       '1.3.0-alpha-1' only
 //
+//     <1.3 : matches '1.2.99999', '1.1.0', '0.9' and so on, but not '1.3'
+//     <1.3.0-alpha-2 : matches '1.3-alpha-1' and '1.3.0-alpha-1' only
+//
 //     ~1.3 : matches '1.3', '1.3.0', '1.3.1', '1.4.0', and all newer
 //            stable 1.x releases
 //     ~1.3.0 : matches '1.3', '1.3.0', '1.3.1', '1.4.0', and all newer
@@ -67,6 +104,22 @@
 //     ~1.3.0-alpha-1 : matches '1.3-alpha-1', '1.3.0-alpha-1' and all
 //                      newer alpha releases of 1.3.0
 //
+// Set semver.TildeMode to semver.TILDE_SAME_MINOR if you want '~' to
+// behave like it does in npm and Cargo: '~1.3' then matches '1.3.0',
+// '1.3.1' and all newer stable 1.3.x releases, but not '1.4.0'.
+//
+//     ^1.3 : matches '1.3', '1.3.0', '1.3.1', '1.4.0', and all newer
+//            stable 1.x releases
+//     ^0.3.1 : matches '0.3.1', '0.3.2' and all newer stable 0.3.x releases
+//     ^0.0.3 : matches '0.0.3' only
+//     ^0.0 : matches '0.0.0', '0.0.1' and all newer stable 0.0.x releases
+//     ^0 : matches '0.0.0', '0.1.0' and all newer stable 0.x releases
+//     ^1.3.0-beta.2 : matches '1.3.0-beta.2', '1.3.0-beta.3', '1.3.0' and
+//                     all newer stable 1.x releases, but not '1.4.0-rc.1'
+//
+// Just like npm, '^' never matches a pre-release unless it has the same
+// X.Y.Z as the expression.
+//
 //     !=1.3 : matches anything except '1.3' and '1.3.0'
 //     !=1.3.0 : matches anything except '1.3' and '1.3.0'
 //     !=1.3-alpha-1 : matches anything except '1.3-alpha-1' or
@@ -74,6 +127,29 @@
 //     !=1.3.0-alpha-1 : matches anything except '1.3-alpha-1' or
 //                       '1.3.0-alpha-1'
 //
+//     @main : matches the branch 'main' (or 'refs/heads/main') only
+//     @tags/v1.3 : matches the tag 'v1.3' (or 'refs/tags/v1.3') only
+//     @64f5893 : matches any commit ID that starts with '64f5893', and
+//                any abbreviation of it that is at least 7 digits long
+//
+// Use VersionExpression.MatchesReference() to match a Reference that you
+// have parsed yourself with ParseReference().
+//
+// Hyphen ranges and X-ranges, as used by npm and Composer, don't need an
+// operator:
+//
+//     1.2.3 - 2.3.4 : matches '1.2.3', '2.0.0', '2.3.4' and everything in
+//                     between
+//     1.2 - 2.3 : matches '1.2.0', '2.3.99' and everything in between,
+//                 but not '2.4.0' or '2.4.0-rc.1'
+//     1.2.x : matches '1.2.0', '1.2.1' and all newer 1.2.x releases
+//     1.x : matches '1.0.0', '1.2.0' and all newer 1.x releases
+//     * : matches any version
+//
+// 'x', 'X' and '*' are interchangeable. ParseExpression() turns these
+// into '>=' expressions, or into OP_RANGE expressions that hold an upper
+// bound as well.
+//
 // You can only compare like releases with like:
 //
 //     stable releases can only be compared with other stable releases
@@ -84,6 +160,122 @@
 // The exception to that rule is the != operator, which only returns an
 // error if both versions are equivalent
 //
+// Set StabilityMode to STABILITY_ORDERED if you want to compare different
+// stability levels too. They are then put in the order given by
+// StabilityOrder:
+//
+//     dev, snapshot < alpha < beta < pre, rc < (stable)
+//
+// Pre-releases such as '1.0.0-alpha.1' are put in the same order, as if
+// they were '1.0.0-alpha-1'. Any other pre-release, such as '1.0.0-foo',
+// comes before all of the stability levels. Stability levels are always
+// compared case-insensitively.
+//
+// VersionExpression.Operator is an Operator. Use ParseOperator() and
+// Operator.String() to convert between operators and their symbols, and
+// Operator.IsValid() to check that an operator is one that we know about.
+// RegisterOperator() adds operators of your own, each with a MatchFunc
+// that VersionExpression.MatchesVersion() calls to do the matching. Like
+// RegisterStability(), it must only be called from init().
+// ParseExpression() recognises them too; when more than one operator
+// matches, the longest one wins, so '~>' does not clash with '~'.
+//
+// When a version does not match, VersionExpression.MatchesVersion()
+// returns a *MatchError that explains which part of the version was to
+// blame:
+//
+//     1.2.0 does not satisfy >=1.3.0: minor version 2 is below 3
+//
+// It wraps one of the Err* values, so errors.Is() still works.
+//
+// Constraints
+//
+// Several expressions can be combined into a Constraint:
+//
+//     >=1.2, <=1.9 : matches versions that match both '>=1.2' AND '<=1.9'
+//     >=1.2 <=1.9  : the same, separated by whitespace instead of a comma
+//     ~1.3 || ~2.0 : matches versions that match either '~1.3' OR '~2.0'
+//     1.2.3 - 2.3.4 || 3.x : hyphen ranges and X-ranges work too
+//
+// When a version does not match, Constraint.MatchesVersion() returns a
+// *ConstraintError that tells you which clause failed in each of the
+// alternatives, and why.
+//
+// Combining Constraints
+//
+// NewRange() and NewConstraintRange() turn expressions and Constraints
+// into a Range, which is a sorted list of Intervals. Ranges can be
+// combined, and turned back into a Constraint:
+//
+//     a, _ := semver.ParseConstraint(">=1.2")
+//     b, _ := semver.ParseConstraint("~1.4")
+//     ra, _ := semver.NewConstraintRange(a)
+//     rb, _ := semver.NewConstraintRange(b)
+//     ra.Intersect(rb).String() // ">=1.4.0, <2.0.0-0"
+//
+// The '-0' is the lowest possible pre-release, so '<2.0.0-0' leaves out
+// 2.0.0's pre-releases as well as 2.0.0 itself.
+//
+// Range also has Union(), Complement(), IsEmpty() and Contains().
+//
+// A Range puts versions in the order that Cmp() does. It doesn't know
+// that '~' and '^' skip some pre-releases, so it may contain a few more
+// pre-releases than the Constraint that it came from.
+//
+// Formatting
+//
+// SemVersion, VersionExpression and Reference all have String() methods
+// that return their canonical form, which can be parsed again:
+//
+//     v, _ := semver.ParseVersion("1.3-alpha-1")
+//     v.String()      // "1.3.0-alpha-1"
+//     v.ShortString() // "1.3-alpha-1"
+//
+// They also work with fmt's %s, %q and %v verbs. Use %+v if you want to
+// see the struct's fields instead.
+//
+// SemVersion and VersionExpression also implement encoding.TextMarshaler,
+// encoding.TextUnmarshaler, json.Marshaler and json.Unmarshaler, so that
+// they can be used as struct fields when reading and writing JSON, YAML
+// and other config files. Parse errors are returned as a *ParseError.
+//
+// They implement sql.Scanner and driver.Valuer too. Store a SortableVersion
+// instead if you want ORDER BY to put versions in the same order that
+// Cmp() does; see SemVersion.SortKey() for details.
+//
+// Sorting
+//
+// Cmp() compares two versions the way that cmp.Compare() does, returning
+// -1, 0 or +1, so that you can sort versions with:
+//
+//     slices.SortFunc(versions, semver.Cmp)
+//
+// Less(), Equal(), Max() and Min() are built on top of Cmp(). Versions that
+// SemVersion.Compare() cannot compare are put into a fixed order, unless
+// you set IncomparablePolicy to INCOMPARABLE_PANIC.
+//
+// The Versions type holds a list of versions, and implements sort.Interface.
+// It can also find the newest (or oldest) version that matches an
+// expression:
+//
+//     versions, err := semver.ParseVersions(tags)
+//     exp, err := semver.ParseExpression("~1.3")
+//     newest, ok := versions.MaxSatisfying(exp)
+//
+// Making New Versions
+//
+// SemVersion can work out what the next version should be:
+//
+//     1.3.5.NextMajor()           // 2.0.0
+//     1.3.5.NextMinor()           // 1.4.0
+//     1.3.5.NextPatch()           // 1.3.6
+//     1.3.5.WithStability("rc")   // 1.3.5-rc-1
+//     1.3.5-rc-1.NextRelease()    // 1.3.5-rc-2
+//     1.3.5-rc-2.Promote()        // 1.3.5
+//
+// Each of these resets the numbers to the right of the one that changes,
+// and drops any build metadata.
+//
 // The semver API returns meaningful errors when a comparison fails,
 // explaining exactly why two version strings are different or can't be
 // compared.
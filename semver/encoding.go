@@ -0,0 +1,111 @@
+package semver
+
+import (
+    "encoding/json"
+    "fmt"
+)
+
+// MarshalText turns the version into its canonical form (see String()),
+// so that it can be stored in JSON, YAML, XML and other text formats
+func (v SemVersion) MarshalText() ([]byte, error) {
+    return v.appendTo(nil, true), nil
+}
+
+// UnmarshalText parses a version string into the version
+//
+// returns a *ParseError if the version string cannot be parsed
+func (v *SemVersion) UnmarshalText(text []byte) error {
+    parsed, err := ParseVersion(string(text))
+    if err != nil {
+        return err
+    }
+
+    *v = parsed
+    return nil
+}
+
+// MarshalJSON turns the version into a JSON string
+func (v SemVersion) MarshalJSON() ([]byte, error) {
+    return json.Marshal(v.String())
+}
+
+// UnmarshalJSON parses a JSON string into the version
+//
+// a JSON null leaves the version unchanged; anything else that isn't a
+// JSON string is an error
+func (v *SemVersion) UnmarshalJSON(data []byte) error {
+    text, isNull, err := unmarshalJSONString(data)
+    if err != nil || isNull {
+        return err
+    }
+
+    return v.UnmarshalText(text)
+}
+
+// MarshalText turns the expression into its canonical form (see
+// String()), so that it can be stored in JSON, YAML, XML and other text
+// formats
+//
+// returns ErrUnknownOperator if the expression's operator is not valid,
+// as nothing could parse it back again
+func (exp VersionExpression) MarshalText() ([]byte, error) {
+    if !exp.Operator.IsValid() {
+        return nil, fmt.Errorf("%w: %s", ErrUnknownOperator, exp.Operator)
+    }
+
+    return []byte(exp.String()), nil
+}
+
+// UnmarshalText parses a version expression into the expression
+//
+// returns a *ParseError if the expression cannot be parsed
+func (exp *VersionExpression) UnmarshalText(text []byte) error {
+    parsed, err := ParseExpression(string(text))
+    if err != nil {
+        return err
+    }
+
+    *exp = parsed
+    return nil
+}
+
+// MarshalJSON turns the expression into a JSON string
+//
+// returns the same errors as MarshalText()
+func (exp VersionExpression) MarshalJSON() ([]byte, error) {
+    text, err := exp.MarshalText()
+    if err != nil {
+        return nil, err
+    }
+
+    return json.Marshal(string(text))
+}
+
+// UnmarshalJSON parses a JSON string into the expression
+//
+// a JSON null leaves the expression unchanged; anything else that isn't
+// a JSON string is an error
+func (exp *VersionExpression) UnmarshalJSON(data []byte) error {
+    text, isNull, err := unmarshalJSONString(data)
+    if err != nil || isNull {
+        return err
+    }
+
+    return exp.UnmarshalText(text)
+}
+
+// unmarshalJSONString decodes the JSON string in 'data'
+//
+// returns true if 'data' is a JSON null instead
+func unmarshalJSONString(data []byte) ([]byte, bool, error) {
+    if string(data) == "null" {
+        return nil, true, nil
+    }
+
+    var text string
+    if err := json.Unmarshal(data, &text); err != nil {
+        return nil, false, err
+    }
+
+    return []byte(text), false, nil
+}
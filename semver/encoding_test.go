@@ -0,0 +1,194 @@
+package semver
+
+import (
+    "encoding/json"
+    "errors"
+    "testing"
+)
+
+// an example of how versions and expressions get used in a config file
+type exampleConfig struct {
+    Version  SemVersion          `json:"version"`
+    Requires VersionExpression   `json:"requires"`
+    Pinned   *SemVersion         `json:"pinned,omitempty"`
+    Accepts  []VersionExpression `json:"accepts,omitempty"`
+}
+
+// ========================================================================
+//
+// Tests for MarshalText() and UnmarshalText()
+//
+// ------------------------------------------------------------------------
+
+func TestCanMarshalVersionAsText(t *testing.T) {
+    // what result do we expect?
+    expected := "1.3.0-alpha-1+build.5"
+
+    // perform the test
+    version, _ := ParseVersion("1.3-alpha-1+build.5")
+    actual, err := version.MarshalText()
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if string(actual) != expected {
+        t.Errorf("Expected %s, received %s", expected, actual)
+        return
+    }
+}
+
+func TestCanUnmarshalExpressionFromText(t *testing.T) {
+    // what result do we expect?
+    expected, _ := ParseExpression("^1.2.3")
+
+    // perform the test
+    var actual VersionExpression
+    err := actual.UnmarshalText([]byte("^1.2.3"))
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %v, received %v", expected, actual)
+        return
+    }
+}
+
+func TestMarshalTextRejectsInvalidExpressions(t *testing.T) {
+    exp := VersionExpression{Operator: 42, Version: SemVersion{Major: 1, Minor: 2, PatchLevel: 3}}
+
+    // perform the test
+    _, err1 := exp.MarshalText()
+    _, err2 := json.Marshal(exampleConfig{Requires: exp})
+
+    // was an error returned?
+    if !errors.Is(err1, ErrUnknownOperator) {
+        t.Errorf("Expected ErrUnknownOperator, received %v", err1)
+        return
+    }
+    if !errors.Is(err2, ErrUnknownOperator) {
+        t.Errorf("Expected ErrUnknownOperator, received %v", err2)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for MarshalJSON() and UnmarshalJSON()
+//
+// ------------------------------------------------------------------------
+
+func TestCanMarshalToJSON(t *testing.T) {
+    // what result do we expect?
+    //
+    // encoding/json always escapes '<' and '>'
+    expected := `{"version":"1.3.6","requires":"\u003e=1.2.0","pinned":"2.0.0-rc.1","accepts":["~1.3.0","@main"]}`
+
+    // perform the test
+    config := exampleConfig{
+        Version:  SemVersion{Major: 1, Minor: 3, PatchLevel: 6},
+        Requires: VersionExpression{Operator: OP_GT_EQUALS, Version: SemVersion{Major: 1, Minor: 2}},
+        Pinned:   &SemVersion{Major: 2, PreRelease: "rc.1"},
+        Accepts: []VersionExpression{
+            VersionExpression{Operator: OP_TILDE, Version: SemVersion{Major: 1, Minor: 3}},
+            VersionExpression{Operator: OP_AT, Reference: Reference{REF_BRANCH, "main"}},
+        },
+    }
+    actual, err := json.Marshal(config)
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if string(actual) != expected {
+        t.Errorf("Expected %s, received %s", expected, actual)
+        return
+    }
+}
+
+func TestCanUnmarshalFromJSON(t *testing.T) {
+    // what result do we expect?
+    expected := exampleConfig{
+        Version:  SemVersion{Major: 1, Minor: 3, PatchLevel: 6},
+        Requires: VersionExpression{Operator: OP_GT_EQUALS, Version: SemVersion{Major: 1, Minor: 2}},
+        Accepts: []VersionExpression{
+            VersionExpression{
+                Operator:      OP_RANGE,
+                Version:       SemVersion{Major: 1, Minor: 2},
+                UpperOperator: OP_LT,
+                Upper:         SemVersion{Major: 1, Minor: 3, PreRelease: "0"},
+            },
+        },
+    }
+
+    // perform the test
+    var actual exampleConfig
+    err := json.Unmarshal([]byte(`{"version":"1.3.6","requires":">=1.2","pinned":null,"accepts":["1.2.x"]}`), &actual)
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual.Version != expected.Version || actual.Requires != expected.Requires || actual.Pinned != nil {
+        t.Errorf("Expected %+v, received %+v", expected, actual)
+        return
+    }
+    if len(actual.Accepts) != 1 || actual.Accepts[0] != expected.Accepts[0] {
+        t.Errorf("Expected %+v, received %+v", expected.Accepts, actual.Accepts)
+        return
+    }
+}
+
+func TestUnmarshalJSONReturnsParseError(t *testing.T) {
+    // what result do we expect?
+    expected := ParseError{"1.3.x.6", 4, expectPatchLevel, nil}
+
+    // perform the test
+    var config exampleConfig
+    err := json.Unmarshal([]byte(`{"version":"1.3.x.6"}`), &config)
+
+    // did we get back what we expected?
+    var actual *ParseError
+    if !errors.As(err, &actual) {
+        t.Errorf("Expected a ParseError, received %v", err)
+        return
+    }
+    if *actual != expected {
+        t.Errorf("Expected %+v, received %+v", expected, *actual)
+        return
+    }
+}
+
+func TestUnmarshalJSONRejectsNonStrings(t *testing.T) {
+    // our list of JSON values that are not strings
+    var toUnmarshal = []string{
+        `{"version":1.3}`,
+        `{"requires":[">=1.2"]}`,
+    }
+
+    for _, raw := range toUnmarshal {
+        // perform the test
+        var config exampleConfig
+        err := json.Unmarshal([]byte(raw), &config)
+
+        // did we get back what we expected?
+        if err == nil {
+            t.Errorf("%s: expected an error, received nil", raw)
+            return
+        }
+    }
+}
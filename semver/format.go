@@ -0,0 +1,221 @@
+package semver
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+// the same structs, without any methods, so that fmt can print their
+// fields for us
+type plainSemVersion SemVersion
+type plainVersionExpression VersionExpression
+type plainReference Reference
+
+// String returns the version in its canonical form:
+//
+//     X.Y.Z[-<stability>-R][-<pre.release>][+<build.metadata>]
+//
+// ParseVersion(v.String()) gives you back the same SemVersion, and
+// ParseSemVer2(v.String()) does too for versions that came from
+// ParseSemVer2()
+func (v SemVersion) String() string {
+    return string(v.appendTo(nil, true))
+}
+
+// ShortString returns the version in its canonical form, but leaves out
+// the patch level when it is zero:
+//
+//     X.Y[-<stability>-R][+<build.metadata>]
+//
+// versions with SemVer 2.0.0 pre-release identifiers always include the
+// patch level, because they need it to be parsed again
+func (v SemVersion) ShortString() string {
+    return string(v.appendTo(nil, v.PatchLevel != 0 || v.PreRelease != ""))
+}
+
+// appendTo adds the version string to the end of 'buf'
+func (v *SemVersion) appendTo(buf []byte, withPatchLevel bool) []byte {
+    buf = strconv.AppendInt(buf, int64(v.Major), 10)
+    buf = append(buf, '.')
+    buf = strconv.AppendInt(buf, int64(v.Minor), 10)
+    if withPatchLevel {
+        buf = append(buf, '.')
+        buf = strconv.AppendInt(buf, int64(v.PatchLevel), 10)
+    }
+    if v.Stability != "" {
+        buf = append(buf, '-')
+        buf = append(buf, v.Stability...)
+        buf = append(buf, '-')
+        buf = strconv.AppendInt(buf, int64(v.Release), 10)
+    }
+    if v.PreRelease != "" {
+        buf = append(buf, '-')
+        buf = append(buf, v.PreRelease...)
+    }
+    if v.Build != "" {
+        buf = append(buf, '+')
+        buf = append(buf, v.Build...)
+    }
+
+    return buf
+}
+
+// Format lets fmt print the version
+//
+// %s, %q and %v print the canonical form (see String()); %+v and all
+// other verbs print the struct's fields, the same way that fmt does
+// for any other struct
+func (v SemVersion) Format(f fmt.State, verb rune) {
+    if usesString(f, verb) {
+        fmt.Fprintf(f, fmt.FormatString(f, verb), v.String())
+        return
+    }
+
+    if verb == 'v' && f.Flag('#') {
+        writeGoSyntax(f, "semver.SemVersion", plainSemVersion(v))
+        return
+    }
+
+    fmt.Fprintf(f, fmt.FormatString(f, verb), plainSemVersion(v))
+}
+
+// String returns the expression in its canonical form, e.g. '>=1.2.0',
+// '@main' or '1.2.0 - 2.3.4'
+//
+// ParseExpression(exp.String()) gives you back the same VersionExpression
+func (exp VersionExpression) String() string {
+    switch exp.Operator {
+    case OP_AT:
+        return "@" + exp.Reference.String()
+
+    case OP_RANGE:
+        return exp.rangeString()
+    }
+
+    if !exp.Operator.IsValid() {
+        return "?" + exp.Version.String()
+    }
+
+    // '^0.0' and '^0.0.0' match different versions, so we must not add
+    // the parts that were left out
+    switch {
+    case exp.Operator == OP_CARET && exp.Parts == 1:
+        return "^" + strconv.Itoa(exp.Version.Major)
+    case exp.Operator == OP_CARET && exp.Parts == 2:
+        return "^" + strconv.Itoa(exp.Version.Major) + "." + strconv.Itoa(exp.Version.Minor)
+    }
+
+    return exp.Operator.String() + exp.Version.String()
+}
+
+// rangeString turns a hyphen range or X-range back into text
+func (exp *VersionExpression) rangeString() string {
+    lower := exp.Version.String()
+
+    // '<=' means that the upper bound was a complete version
+    if exp.UpperOperator != OP_LT || exp.Upper.PreRelease != lowestPreRelease {
+        return lower + " - " + exp.Upper.String()
+    }
+
+    // otherwise, we need to turn the upper bound back into an X-range
+    var upper string
+    var floor SemVersion
+    if exp.Upper.Minor == 0 {
+        floor = SemVersion{Major: exp.Upper.Major - 1}
+        upper = strconv.Itoa(floor.Major) + ".x"
+    } else {
+        floor = SemVersion{Major: exp.Upper.Major, Minor: exp.Upper.Minor - 1}
+        upper = strconv.Itoa(floor.Major) + "." + strconv.Itoa(floor.Minor) + ".x"
+    }
+
+    // is this an X-range on its own?
+    if exp.Version == floor {
+        return upper
+    }
+
+    return lower + " - " + upper
+}
+
+// Format lets fmt print the expression
+//
+// %s, %q and %v print the canonical form (see String()); %+v and all
+// other verbs print the struct's fields, the same way that fmt does
+// for any other struct
+func (exp VersionExpression) Format(f fmt.State, verb rune) {
+    if usesString(f, verb) {
+        fmt.Fprintf(f, fmt.FormatString(f, verb), exp.String())
+        return
+    }
+
+    if verb == 'v' && f.Flag('#') {
+        writeGoSyntax(f, "semver.VersionExpression", plainVersionExpression(exp))
+        return
+    }
+
+    fmt.Fprintf(f, fmt.FormatString(f, verb), plainVersionExpression(exp))
+}
+
+// String returns the reference in its canonical form
+//
+// ParseReference(ref.String()) gives you back the same Reference
+func (ref Reference) String() string {
+    switch {
+    case ref.Kind == REF_TAG:
+        return "tags/" + ref.Name
+    case ref.Kind == REF_BRANCH && (isCommitID(ref.Name) || hasRefPrefix(ref.Name)):
+        return "heads/" + ref.Name
+    }
+
+    return ref.Name
+}
+
+// Format lets fmt print the reference
+//
+// %s, %q and %v print the canonical form (see String()); %+v and all
+// other verbs print the struct's fields, the same way that fmt does
+// for any other struct
+func (ref Reference) Format(f fmt.State, verb rune) {
+    if usesString(f, verb) {
+        fmt.Fprintf(f, fmt.FormatString(f, verb), ref.String())
+        return
+    }
+
+    if verb == 'v' && f.Flag('#') {
+        writeGoSyntax(f, "semver.Reference", plainReference(ref))
+        return
+    }
+
+    fmt.Fprintf(f, fmt.FormatString(f, verb), plainReference(ref))
+}
+
+// hasRefPrefix returns true if 'name' starts with one of the prefixes
+// that ParseReference() looks for
+func hasRefPrefix(name string) bool {
+    for _, p := range refPrefixes {
+        if strings.HasPrefix(name, p.prefix) {
+            return true
+        }
+    }
+
+    return false
+}
+
+// writeGoSyntax prints 'plain' the way that %#v does, but with the name
+// of our real type instead of the plain one
+func writeGoSyntax(f fmt.State, name string, plain interface{}) {
+    goSyntax := fmt.Sprintf("%#v", plain)
+    f.Write([]byte(name + goSyntax[strings.IndexByte(goSyntax, '{'):]))
+}
+
+// usesString returns true if 'verb' should print the String() form
+func usesString(f fmt.State, verb rune) bool {
+    switch verb {
+    case 's', 'q':
+        return true
+    case 'v':
+        return !f.Flag('+') && !f.Flag('#')
+    }
+
+    return false
+}
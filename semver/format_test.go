@@ -0,0 +1,221 @@
+package semver
+
+import (
+    "fmt"
+    "testing"
+)
+
+// ========================================================================
+//
+// Tests for SemVersion.String()
+//
+// ------------------------------------------------------------------------
+
+func TestCanConvertVersionToString(t *testing.T) {
+    // our list of versions, and the strings that we expect
+    var toConvert = [][2]string{
+        [2]string{"1.3", "1.3.0"},
+        [2]string{"1.3.6", "1.3.6"},
+        [2]string{"1.3-alpha-1", "1.3.0-alpha-1"},
+        [2]string{"1.3.6-SNAPSHOT-20141013", "1.3.6-SNAPSHOT-20141013"},
+        [2]string{"1.2.3-rc.1+build.5", "1.2.3-rc.1+build.5"},
+        [2]string{"1.2+build.5", "1.2.0+build.5"},
+    }
+
+    for _, set := range toConvert {
+        version, err := ParseVersion(set[0])
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // perform the test
+        actual := version.String()
+
+        // did we get back what we expected?
+        if actual != set[1] {
+            t.Errorf("Expected %s, received %s", set[1], actual)
+            return
+        }
+    }
+}
+
+func TestCanConvertVersionToShortString(t *testing.T) {
+    // our list of versions, and the strings that we expect
+    var toConvert = [][2]string{
+        [2]string{"1.3.0", "1.3"},
+        [2]string{"1.3.6", "1.3.6"},
+        [2]string{"1.3.0-alpha-1", "1.3-alpha-1"},
+        [2]string{"1.3.0-rc.1", "1.3.0-rc.1"},
+        [2]string{"1.2.0+build.5", "1.2+build.5"},
+    }
+
+    for _, set := range toConvert {
+        version, err := ParseVersion(set[0])
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // perform the test
+        actual := version.ShortString()
+
+        // did we get back what we expected?
+        if actual != set[1] {
+            t.Errorf("Expected %s, received %s", set[1], actual)
+            return
+        }
+    }
+}
+
+func TestVersionStringsRoundTrip(t *testing.T) {
+    for _, raw := range commonVersions {
+        expected, err := ParseVersion(raw)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // perform the test
+        long, err := ParseVersion(expected.String())
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        short, err := ParseVersion(expected.ShortString())
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if long != expected || short != expected {
+            t.Errorf("Expected %+v, received %+v and %+v", expected, long, short)
+            return
+        }
+    }
+}
+
+func TestSemVer2StringsRoundTrip(t *testing.T) {
+    // what result do we expect?
+    expected, err := ParseSemVer2("1.0.0-alpha-1+exp.sha.5114f85")
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // perform the test
+    actual, err := ParseSemVer2(expected.String())
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %+v, received %+v", expected, actual)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for VersionExpression.String()
+//
+// ------------------------------------------------------------------------
+
+func TestCanConvertExpressionToString(t *testing.T) {
+    // our list of expressions, and the strings that we expect
+    var toConvert = [][2]string{
+        [2]string{"=1.3", "=1.3.0"},
+        [2]string{">=1.3.6-alpha-1", ">=1.3.6-alpha-1"},
+        [2]string{"<=2.0", "<=2.0.0"},
+        [2]string{"~1.3", "~1.3.0"},
+        [2]string{"!=1.3.6", "!=1.3.6"},
+        [2]string{">1.3.6", ">1.3.6"},
+        [2]string{"<1.3.6-rc.1", "<1.3.6-rc.1"},
+        [2]string{"^0.2.3", "^0.2.3"},
+        [2]string{"^0.0", "^0.0"},
+        [2]string{"^1", "^1"},
+        [2]string{"@main", "@main"},
+        [2]string{"@refs/tags/v1.0", "@tags/v1.0"},
+        [2]string{"@heads/deadbeef", "@heads/deadbeef"},
+        [2]string{"@64f5893", "@64f5893"},
+        [2]string{"*", ">=0.0.0"},
+        [2]string{"1.x", "1.x"},
+        [2]string{"1.2.*", "1.2.x"},
+        [2]string{"1.2.3 - 2.3.4", "1.2.3 - 2.3.4"},
+        [2]string{"1.2 - 2.3", "1.2.0 - 2.3.x"},
+        [2]string{"1.2.3 - 2", "1.2.3 - 2.x"},
+    }
+
+    for _, set := range toConvert {
+        exp, err := ParseExpression(set[0])
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // perform the test
+        actual := exp.String()
+
+        // did we get back what we expected?
+        if actual != set[1] {
+            t.Errorf("Expected %s, received %s", set[1], actual)
+            return
+        }
+
+        // and can we parse it back again?
+        roundTrip, err := ParseExpression(actual)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        if roundTrip != exp {
+            t.Errorf("Expected %+v, received %+v", exp, roundTrip)
+            return
+        }
+    }
+}
+
+// ========================================================================
+//
+// Tests for Format()
+//
+// ------------------------------------------------------------------------
+
+func TestCanFormatUsingVerbs(t *testing.T) {
+    version := SemVersion{Major: 1, Minor: 2, PatchLevel: 3, PreRelease: "rc.1"}
+    exp := VersionExpression{Operator: OP_GT_EQUALS, Version: version}
+
+    // our list of format strings, and what we expect to get back
+    var toFormat = []struct {
+        format   string
+        value    interface{}
+        expected string
+    }{
+        {"%s", version, "1.2.3-rc.1"},
+        {"%v", version, "1.2.3-rc.1"},
+        {"%v", &version, "1.2.3-rc.1"},
+        {"%q", version, `"1.2.3-rc.1"`},
+        {"%12s|", version, "  1.2.3-rc.1|"},
+        {"%+v", version, "{Major:1 Minor:2 PatchLevel:3 Stability: Release:0 PreRelease:rc.1 Build:}"},
+        {"%d", version, "{1 2 3 %!d(string=) 0 %!d(string=rc.1) %!d(string=)}"},
+        {"%#v", version, `semver.SemVersion{Major:1, Minor:2, PatchLevel:3, Stability:"", Release:0, PreRelease:"rc.1", Build:""}`},
+        {"%s", exp, ">=1.2.3-rc.1"},
+        {"%v", []VersionExpression{exp}, "[>=1.2.3-rc.1]"},
+        {"%s", Reference{REF_TAG, "v1.0"}, "tags/v1.0"},
+        {"%+v", Reference{REF_TAG, "v1.0"}, "{Kind:1 Name:v1.0}"},
+    }
+
+    for _, set := range toFormat {
+        // perform the test
+        actual := fmt.Sprintf(set.format, set.value)
+
+        // did we get back what we expected?
+        if actual != set.expected {
+            t.Errorf("%s: expected %s, received %s", set.format, set.expected, actual)
+            return
+        }
+    }
+}
@@ -0,0 +1,202 @@
+package semver
+
+import (
+    "errors"
+    "fmt"
+    "strconv"
+)
+
+// values for MatchError.Component
+const (
+    COMPONENT_MAJOR       = "major version"
+    COMPONENT_MINOR       = "minor version"
+    COMPONENT_PATCH_LEVEL = "patchlevel"
+    COMPONENT_STABILITY   = "stability level"
+    COMPONENT_RELEASE     = "release number"
+    COMPONENT_PRE_RELEASE = "pre-release"
+)
+
+// MatchError is returned by VersionExpression.MatchesVersion() when a
+// version does not match
+//
+// it explains which part of the version was to blame, e.g.
+//
+//     1.2.0 does not satisfy >=1.3.0: minor version 2 is below 3
+//
+// errors.Is() works with the Err* value that it wraps
+type MatchError struct {
+    Expression VersionExpression // the expression that did not match
+    Version    SemVersion        // the version that we tried to match
+
+    // the part of Version that did not match, as one of the COMPONENT_*
+    // values; empty if no single part is to blame (e.g. ErrSameVersion)
+    Component string
+
+    // the value that Component was compared against, e.g. '3' in
+    // 'minor version 2 is below 3'; empty if Component is
+    // COMPONENT_PRE_RELEASE and the expression has no pre-release
+    Expected string
+
+    Err error // one of the Err* values
+}
+
+// matchComponent describes how a version failed to match, for each of
+// the Err* values that are about a single part of the version
+type matchComponent struct {
+    component string
+    relation  string
+}
+
+var matchComponents = map[error]matchComponent{
+    ErrDifferentMajorVersions:   {COMPONENT_MAJOR, "is not"},
+    ErrMajorVersionTooSmall:     {COMPONENT_MAJOR, "is below"},
+    ErrMajorVersionTooLarge:     {COMPONENT_MAJOR, "is above"},
+    ErrDifferentMinorVersions:   {COMPONENT_MINOR, "is not"},
+    ErrMinorVersionTooSmall:     {COMPONENT_MINOR, "is below"},
+    ErrMinorVersionTooLarge:     {COMPONENT_MINOR, "is above"},
+    ErrDifferentPatchLevel:      {COMPONENT_PATCH_LEVEL, "is not"},
+    ErrPatchLevelTooSmall:       {COMPONENT_PATCH_LEVEL, "is below"},
+    ErrPatchLevelTooLarge:       {COMPONENT_PATCH_LEVEL, "is above"},
+    ErrDifferentStabilityLevels: {COMPONENT_STABILITY, "is not"},
+    ErrStabilityLevelTooLow:     {COMPONENT_STABILITY, "is below"},
+    ErrStabilityLevelTooHigh:    {COMPONENT_STABILITY, "is above"},
+    ErrDifferentReleaseNumbers:  {COMPONENT_RELEASE, "is not"},
+    ErrReleaseNumberTooSmall:    {COMPONENT_RELEASE, "is below"},
+    ErrReleaseNumberTooLarge:    {COMPONENT_RELEASE, "is above"},
+    ErrDifferentPreReleases:     {COMPONENT_PRE_RELEASE, "is not"},
+    ErrPreReleaseTooSmall:       {COMPONENT_PRE_RELEASE, "is below"},
+    ErrPreReleaseTooLarge:       {COMPONENT_PRE_RELEASE, "is above"},
+}
+
+// newMatchError wraps the Err* value that a matcher returned in a
+// *MatchError
+//
+// errors that are not about the version itself (e.g. ErrUnknownOperator)
+// are returned as they are
+func newMatchError(lhs *VersionExpression, rhs *SemVersion, err error) error {
+    if err == ErrUnknownOperator || err == ErrNotAReference {
+        return err
+    }
+
+    // ranges are matched one bound at a time; we report the failure
+    // against the whole range
+    var existing *MatchError
+    if errors.As(err, &existing) {
+        retval := *existing
+        retval.Expression = *lhs
+        return &retval
+    }
+
+    retval := &MatchError{Expression: *lhs, Version: *rhs, Err: err}
+    if details, ok := matchComponents[err]; ok {
+        bound := lhs.Version
+        if err == ErrMajorVersionTooLarge || err == ErrMinorVersionTooLarge {
+            bound = lhs.upperBound()
+        }
+        retval.Component = details.component
+        retval.Expected = componentValue(details.component, &bound)
+    }
+
+    return retval
+}
+
+// upperBound returns the version that the 'too large' errors were
+// checked against
+//
+// for X-ranges, that is the highest X.Y that the range accepts, e.g. 1.2
+// for '1.2.x', rather than the '1.3.0-0' that it has been desugared into
+func (lhs *VersionExpression) upperBound() SemVersion {
+    if lhs.Operator != OP_RANGE {
+        return lhs.Version
+    }
+    if lhs.UpperOperator != OP_LT || lhs.Upper.PreRelease != lowestPreRelease {
+        return lhs.Upper
+    }
+    if lhs.Upper.Minor == 0 {
+        return SemVersion{Major: lhs.Upper.Major - 1}
+    }
+
+    return SemVersion{Major: lhs.Upper.Major, Minor: lhs.Upper.Minor - 1}
+}
+
+// componentValue returns one part of a version, as text
+func componentValue(component string, v *SemVersion) string {
+    switch component {
+    case COMPONENT_MAJOR:
+        return strconv.Itoa(v.Major)
+    case COMPONENT_MINOR:
+        return strconv.Itoa(v.Minor)
+    case COMPONENT_PATCH_LEVEL:
+        return strconv.Itoa(v.PatchLevel)
+    case COMPONENT_STABILITY:
+        level := v.Stability
+        if StabilityMode == STABILITY_ORDERED {
+            level, _, _ = orderedStability(v)
+        }
+        switch level {
+        case "":
+            return "stable"
+        case preReleaseLevel:
+            return v.PreRelease
+        }
+        return level
+    case COMPONENT_RELEASE:
+        return strconv.Itoa(v.Release)
+    case COMPONENT_PRE_RELEASE:
+        return v.PreRelease
+    }
+
+    return ""
+}
+
+// Error explains why the version did not match
+func (e *MatchError) Error() string {
+    details, ok := matchComponents[e.Err]
+    if !ok || e.Component == "" {
+        return fmt.Sprintf("%s does not satisfy %s: %v", e.Version, e.Expression, e.Err)
+    }
+
+    // a stable release has no pre-release to compare
+    if e.Component == COMPONENT_PRE_RELEASE && (e.Version.PreRelease == "" || e.Expected == "") {
+        return e.stableReleaseError()
+    }
+
+    return fmt.Sprintf(
+        "%s does not satisfy %s: %s %s %s %s",
+        e.Version,
+        e.Expression,
+        e.Component,
+        componentValue(e.Component, &e.Version),
+        details.relation,
+        e.Expected,
+    )
+}
+
+// stableReleaseError explains why the version did not match, when only
+// one of the version and the expression is a pre-release
+func (e *MatchError) stableReleaseError() string {
+    base := SemVersion{Major: e.Version.Major, Minor: e.Version.Minor, PatchLevel: e.Version.PatchLevel}
+
+    accepts := "stable releases"
+    is := "a pre-release"
+    if e.Version.PreRelease == "" {
+        accepts = "pre-releases"
+        is = "a stable release"
+    }
+
+    return fmt.Sprintf(
+        "%s does not satisfy %s: %s is %s; %s only accepts %s of %s",
+        e.Version,
+        e.Expression,
+        e.Version,
+        is,
+        e.Expression,
+        accepts,
+        base,
+    )
+}
+
+// Unwrap returns the Err* value, so that errors.Is() works
+func (e *MatchError) Unwrap() error {
+    return e.Err
+}
@@ -0,0 +1,109 @@
+package semver
+
+import (
+    "errors"
+    "testing"
+)
+
+// ========================================================================
+//
+// Tests for MatchError
+//
+// ------------------------------------------------------------------------
+
+func TestMatchErrorExplainsWhatWentWrong(t *testing.T) {
+    // our list of expressions and versions, and the messages we expect
+    var toMatch = []struct {
+        expression string
+        version    string
+        expected   string
+    }{
+        {">=1.3", "1.2.0", "1.2.0 does not satisfy >=1.3.0: minor version 2 is below 3"},
+        {"<=1.9", "2.1.0", "2.1.0 does not satisfy <=1.9.0: major version 2 is above 1"},
+        {"=1.2.3", "1.2.4", "1.2.4 does not satisfy =1.2.3: patchlevel 4 is not 3"},
+        {">=1.2.3-beta", "1.2.3-alpha", "1.2.3-alpha does not satisfy >=1.2.3-beta: pre-release alpha is below beta"},
+        {"1.2.x", "1.3.0", "1.3.0 does not satisfy 1.2.x: minor version 3 is above 2"},
+        {"1.x", "2.0.0", "2.0.0 does not satisfy 1.x: major version 2 is above 1"},
+        {"1.2 - 1.4", "1.1.0", "1.1.0 does not satisfy 1.2.0 - 1.4.x: minor version 1 is below 2"},
+        {">=1.2.0", "1.2.0-rc.1", "1.2.0-rc.1 does not satisfy >=1.2.0: 1.2.0-rc.1 is a pre-release; >=1.2.0 only accepts stable releases of 1.2.0"},
+        {"=1.2.0-rc.1", "1.2.0", "1.2.0 does not satisfy =1.2.0-rc.1: 1.2.0 is a stable release; =1.2.0-rc.1 only accepts pre-releases of 1.2.0"},
+    }
+
+    for _, set := range toMatch {
+        lhs, err := ParseExpression(set.expression)
+        if err != nil {
+            t.Errorf("%s: %v", set.expression, err)
+            return
+        }
+
+        // perform the test
+        ok, err := lhs.Matches(set.version)
+
+        // did we get back what we expected?
+        if ok || err == nil {
+            t.Errorf("%s: expected %s not to match", set.expression, set.version)
+            return
+        }
+        if err.Error() != set.expected {
+            t.Errorf("%s: expected %q, received %q", set.expression, set.expected, err)
+            return
+        }
+    }
+}
+
+func TestMatchErrorWrapsTheErrValue(t *testing.T) {
+    lhs, err := ParseExpression(">=1.3")
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // perform the test
+    _, err = lhs.Matches("1.2.0")
+
+    // did we get back what we expected?
+    var actual *MatchError
+    if !errors.As(err, &actual) {
+        t.Errorf("Expected a *MatchError, received %v", err)
+        return
+    }
+    if !errors.Is(err, ErrMinorVersionTooSmall) {
+        t.Errorf("Expected ErrMinorVersionTooSmall, received %v", err)
+        return
+    }
+    if actual.Component != COMPONENT_MINOR || actual.Expected != "3" {
+        t.Errorf("Expected %q and %q, received %q and %q", COMPONENT_MINOR, "3", actual.Component, actual.Expected)
+        return
+    }
+    if actual.Expression != lhs || actual.Version.String() != "1.2.0" {
+        t.Errorf("Expected %s and 1.2.0, received %s and %s", lhs, actual.Expression, actual.Version)
+        return
+    }
+}
+
+func TestMatchErrorWithoutAComponent(t *testing.T) {
+    lhs, err := ParseExpression("!=1.2.3")
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // perform the test
+    _, err = lhs.Matches("1.2.3")
+
+    // did we get back what we expected?
+    var actual *MatchError
+    if !errors.As(err, &actual) || !errors.Is(err, ErrSameVersion) {
+        t.Errorf("Expected a *MatchError wrapping ErrSameVersion, received %v", err)
+        return
+    }
+    if actual.Component != "" {
+        t.Errorf("Expected no component, received %q", actual.Component)
+        return
+    }
+    expected := "1.2.3 does not satisfy !=1.2.3: " + ErrSameVersion.Error()
+    if err.Error() != expected {
+        t.Errorf("Expected %q, received %q", expected, err)
+        return
+    }
+}
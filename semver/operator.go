@@ -0,0 +1,182 @@
+package semver
+
+import (
+    "fmt"
+    "strconv"
+)
+
+// Operator says how a VersionExpression compares versions
+//
+// it is one of the OP_* values, or an operator that has been added by
+// calling RegisterOperator()
+type Operator int
+
+// MatchFunc decides if the version in 'rhs' matches the expression in
+// 'lhs', for operators that have been added by calling RegisterOperator()
+//
+// it must return 'true' if the version matches, or 'false' plus an error
+// that explains why not; use the Err* values where you can, so that
+// errors.Is() works for your operator too
+type MatchFunc func(lhs *VersionExpression, rhs *SemVersion) (bool, error)
+
+// returned by RegisterOperator() when it cannot add the operator
+var (
+    ErrEmptyOperator         = fmt.Errorf("operator has no symbol")
+    ErrDuplicateOperator     = fmt.Errorf("operator is already registered")
+    ErrNoMatchFunc           = fmt.Errorf("operator has no match function")
+    ErrInvalidOperatorSymbol = fmt.Errorf("operator symbol is not allowed")
+)
+
+// operatorInfo is what we know about each operator
+type operatorInfo struct {
+    symbol string
+    match  MatchFunc // nil for the OP_* operators
+}
+
+// a list of supported operators, indexed by Operator
+//
+// OP_RANGE has no symbol of its own in an expression; we use '-' when we
+// need to write it down on its own. Every parse and match reads this list
+// without a lock, which is why RegisterOperator() must only be called
+// from init().
+var opList = []operatorInfo{
+    OP_EQUALS:     {symbol: "="},
+    OP_GT_EQUALS:  {symbol: ">="},
+    OP_LT_EQUALS:  {symbol: "<="},
+    OP_TILDE:      {symbol: "~"},
+    OP_AT:         {symbol: "@"},
+    OP_NOT_EQUALS: {symbol: "!="},
+    OP_GT:         {symbol: ">"},
+    OP_LT:         {symbol: "<"},
+    OP_CARET:      {symbol: "^"},
+    OP_RANGE:      {symbol: "-"},
+}
+
+// RegisterOperator adds an operator of your own, which uses 'fn' to
+// match versions
+//
+// once added, ParseExpression() and ParseConstraint() recognise the new
+// operator, and VersionExpression.MatchesVersion() calls 'fn' for any
+// expression that uses it. The list of operators is read every time that
+// expressions are parsed or matched, and nothing guards it, so (just like
+// RegisterStability()) call this from your init() function, before you
+// start parsing or matching expressions.
+//
+// An expression uses the longest operator that it starts with, so you
+// can add operators such as '~>' or '>==' without breaking '~' or '>='.
+// Symbols cannot contain whitespace, digits, '.', ',' or '|', and cannot
+// start with 'x', 'X' or '*', so that they are never mistaken for part of
+// a version or a constraint.
+//
+// NewRange() returns ErrNotARange for your operators, as it has no way of
+// knowing which versions 'fn' accepts.
+//
+// returns the new Operator, or an error if 'symbol' is empty, is not
+// allowed, or is already in use
+func RegisterOperator(symbol string, fn MatchFunc) (Operator, error) {
+    if symbol == "" {
+        return -1, ErrEmptyOperator
+    }
+    if !isOperatorSymbol(symbol) {
+        return -1, fmt.Errorf("%w: %q", ErrInvalidOperatorSymbol, symbol)
+    }
+    if fn == nil {
+        return -1, fmt.Errorf("%w: %s", ErrNoMatchFunc, symbol)
+    }
+    if _, err := ParseOperator(symbol); err == nil {
+        return -1, fmt.Errorf("%w: %s", ErrDuplicateOperator, symbol)
+    }
+
+    opList = append(opList, operatorInfo{symbol: symbol, match: fn})
+    return Operator(len(opList) - 1), nil
+}
+
+// isOperatorSymbol returns true if 'symbol' cannot be mistaken for part
+// of a version or a constraint
+func isOperatorSymbol(symbol string) bool {
+    switch symbol[0] {
+    case 'x', 'X', '*':
+        return false
+    }
+
+    for i := 0; i < len(symbol); i++ {
+        c := symbol[i]
+        if isSpace(c) || isDigit(c) || c == '.' || c == ',' || c == '|' {
+            return false
+        }
+    }
+
+    return true
+}
+
+// ParseOperator converts an operator's symbol (e.g. '>=') into an
+// Operator
+//
+// the whole string must be the symbol; use ParseExpression() to parse an
+// operator and a version together
+//
+// returns a *ParseError if 'raw' is not an operator that we know about
+func ParseOperator(raw string) (Operator, error) {
+    for i, op := range opList {
+        if op.symbol == raw {
+            return Operator(i), nil
+        }
+    }
+
+    return -1, newParseError(raw, 0, expectOperator)
+}
+
+// IsValid returns true if the operator is one of the OP_* values, or
+// has been added by calling RegisterOperator()
+func (op Operator) IsValid() bool {
+    return op >= 0 && int(op) < len(opList)
+}
+
+// String returns the operator's symbol, e.g. '>='
+//
+// operators that are not valid come back as 'Operator(N)'
+func (op Operator) String() string {
+    if !op.IsValid() {
+        return "Operator(" + strconv.Itoa(int(op)) + ")"
+    }
+
+    return opList[op].symbol
+}
+
+// MarshalText turns the operator into its symbol, so that it can be
+// stored in JSON, YAML, XML and other text formats
+//
+// returns ErrUnknownOperator if the operator is not valid
+func (op Operator) MarshalText() ([]byte, error) {
+    if !op.IsValid() {
+        return nil, fmt.Errorf("%w: %s", ErrUnknownOperator, op)
+    }
+
+    return []byte(opList[op].symbol), nil
+}
+
+// UnmarshalText parses an operator's symbol into the operator
+//
+// returns a *ParseError if the symbol cannot be parsed
+func (op *Operator) UnmarshalText(text []byte) error {
+    parsed, err := ParseOperator(string(text))
+    if err != nil {
+        return err
+    }
+
+    *op = parsed
+    return nil
+}
+
+// matchFunc returns the MatchFunc for an operator that was added by
+// calling RegisterOperator()
+//
+// returns nil for the OP_* operators, and for operators that are not
+// valid
+func (op Operator) matchFunc() MatchFunc {
+    if !op.IsValid() {
+        return nil
+    }
+
+    return opList[op].match
+}
@@ -0,0 +1,332 @@
+package semver
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "testing"
+)
+
+// restoreOperators puts opList back the way it was, once a test that
+// registers operators has finished
+func restoreOperators(t *testing.T) {
+    saved := opList
+    t.Cleanup(func() { opList = saved })
+}
+
+// matchesSameMinor is an example MatchFunc: any patchlevel of the same
+// X.Y, as long as it is stable
+func matchesSameMinor(lhs *VersionExpression, rhs *SemVersion) (bool, error) {
+    if rhs.Major != lhs.Version.Major {
+        return false, ErrDifferentMajorVersions
+    }
+    if rhs.Minor != lhs.Version.Minor {
+        return false, ErrDifferentMinorVersions
+    }
+    if rhs.Stability != "" || rhs.PreRelease != "" {
+        return false, ErrUnstableVersion
+    }
+
+    return true, nil
+}
+
+// ========================================================================
+//
+// Tests for ParseOperator() and String()
+//
+// ------------------------------------------------------------------------
+
+func TestCanParseOperators(t *testing.T) {
+    // our list of symbols, and the operators we expect them to become
+    var toParse = []struct {
+        symbol   string
+        expected Operator
+    }{
+        {"=", OP_EQUALS},
+        {">=", OP_GT_EQUALS},
+        {"<=", OP_LT_EQUALS},
+        {"~", OP_TILDE},
+        {"@", OP_AT},
+        {"!=", OP_NOT_EQUALS},
+        {">", OP_GT},
+        {"<", OP_LT},
+        {"^", OP_CARET},
+        {"-", OP_RANGE},
+    }
+
+    for _, set := range toParse {
+        // perform the test
+        actual, err := ParseOperator(set.symbol)
+
+        // was an error returned?
+        if err != nil {
+            t.Errorf("%q: %v", set.symbol, err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != set.expected {
+            t.Errorf("%q: expected %d, received %d", set.symbol, set.expected, actual)
+            return
+        }
+        if actual.String() != set.symbol {
+            t.Errorf("Expected %q, received %q", set.symbol, actual)
+            return
+        }
+    }
+}
+
+func TestParseOperatorRejectsUnknownOperators(t *testing.T) {
+    // our list of strings that are not operators
+    var toParse = []string{"", "=>", ">=1.0", " >", "~>"}
+
+    for _, raw := range toParse {
+        // perform the test
+        _, err := ParseOperator(raw)
+
+        // was an error returned?
+        var parseErr *ParseError
+        if !errors.As(err, &parseErr) {
+            t.Errorf("%q: expected a *ParseError, received %v", raw, err)
+            return
+        }
+    }
+}
+
+func TestOperatorIsValid(t *testing.T) {
+    // perform the test
+    for op := OP_EQUALS; op <= OP_RANGE; op++ {
+        if !op.IsValid() {
+            t.Errorf("Expected %d to be valid", op)
+            return
+        }
+    }
+    for _, op := range []Operator{-1, OP_RANGE + 1, 42} {
+        if op.IsValid() {
+            t.Errorf("Expected %d not to be valid", op)
+            return
+        }
+        if op.String() != fmt.Sprintf("Operator(%d)", int(op)) {
+            t.Errorf("Expected Operator(%d), received %s", op, op)
+            return
+        }
+    }
+}
+
+// ========================================================================
+//
+// Tests for MarshalText() and UnmarshalText()
+//
+// ------------------------------------------------------------------------
+
+func TestCanMarshalOperatorsToJSON(t *testing.T) {
+    // what result do we expect?
+    expected := `{"op":"~"}`
+
+    // perform the test
+    actual, err := json.Marshal(struct {
+        Op Operator `json:"op"`
+    }{OP_TILDE})
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if string(actual) != expected {
+        t.Errorf("Expected %s, received %s", expected, actual)
+        return
+    }
+
+    // and back again
+    var decoded struct {
+        Op Operator `json:"op"`
+    }
+    if err := json.Unmarshal(actual, &decoded); err != nil {
+        t.Error(err)
+        return
+    }
+    if decoded.Op != OP_TILDE {
+        t.Errorf("Expected %s, received %s", OP_TILDE, decoded.Op)
+        return
+    }
+}
+
+func TestMarshalTextRejectsInvalidOperators(t *testing.T) {
+    // perform the test
+    _, err1 := Operator(42).MarshalText()
+    var op Operator
+    err2 := op.UnmarshalText([]byte("=>"))
+
+    // was an error returned?
+    if !errors.Is(err1, ErrUnknownOperator) {
+        t.Errorf("Expected ErrUnknownOperator, received %v", err1)
+        return
+    }
+    var parseErr *ParseError
+    if !errors.As(err2, &parseErr) {
+        t.Errorf("Expected a *ParseError, received %v", err2)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for RegisterOperator()
+//
+// ------------------------------------------------------------------------
+
+func TestCanRegisterOperators(t *testing.T) {
+    restoreOperators(t)
+
+    // perform the test
+    op, err := RegisterOperator("~=", matchesSameMinor)
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if !op.IsValid() || op.String() != "~=" {
+        t.Errorf("Expected a valid '~=' operator, received %s", op)
+        return
+    }
+    parsed, err := ParseOperator("~=")
+    if err != nil || parsed != op {
+        t.Errorf("Expected %d, received %d and %v", op, parsed, err)
+        return
+    }
+
+    // does MatchesVersion() use our MatchFunc?
+    lhs := VersionExpression{Operator: op, Version: SemVersion{Major: 1, Minor: 2}}
+    if ok, err := lhs.Matches("1.2.9"); !ok || err != nil {
+        t.Errorf("Expected 1.2.9 to match, received %v", err)
+        return
+    }
+    _, err = lhs.Matches("1.3.0")
+    var matchErr *MatchError
+    if !errors.As(err, &matchErr) || !errors.Is(err, ErrDifferentMinorVersions) {
+        t.Errorf("Expected a *MatchError wrapping ErrDifferentMinorVersions, received %v", err)
+        return
+    }
+    if lhs.String() != "~=1.2.0" {
+        t.Errorf("Expected ~=1.2.0, received %s", lhs)
+        return
+    }
+}
+
+func TestRegisterOperatorRejectsBadOperators(t *testing.T) {
+    restoreOperators(t)
+
+    // perform the test
+    _, err1 := RegisterOperator("", matchesSameMinor)
+    _, err2 := RegisterOperator(">=", matchesSameMinor)
+    _, err3 := RegisterOperator("~=", nil)
+
+    // symbols that could be part of a version or a constraint
+    for _, symbol := range []string{"1", "=1", "~.", "x", "*=", "~ ", "|", "=,"} {
+        _, err := RegisterOperator(symbol, matchesSameMinor)
+        if !errors.Is(err, ErrInvalidOperatorSymbol) {
+            t.Errorf("%q: expected ErrInvalidOperatorSymbol, received %v", symbol, err)
+            return
+        }
+    }
+
+    // was an error returned?
+    if !errors.Is(err1, ErrEmptyOperator) {
+        t.Errorf("Expected ErrEmptyOperator, received %v", err1)
+        return
+    }
+    if !errors.Is(err2, ErrDuplicateOperator) {
+        t.Errorf("Expected ErrDuplicateOperator, received %v", err2)
+        return
+    }
+    if !errors.Is(err3, ErrNoMatchFunc) {
+        t.Errorf("Expected ErrNoMatchFunc, received %v", err3)
+        return
+    }
+}
+
+func TestParseExpressionUsesTheLongestOperator(t *testing.T) {
+    restoreOperators(t)
+    pessimistic, err := RegisterOperator("~>", matchesSameMinor)
+    if err != nil {
+        t.Error(err)
+        return
+    }
+    strictlyAbove, err := RegisterOperator(">>", matchesSameMinor)
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // our list of expressions, and the operators we expect them to use
+    var toParse = []struct {
+        raw      string
+        expected Operator
+    }{
+        {"~>1.2", pessimistic},
+        {"~1.2", OP_TILDE},
+        {">>1.2", strictlyAbove},
+        {">=1.2", OP_GT_EQUALS},
+        {">1.2", OP_GT},
+    }
+
+    for _, set := range toParse {
+        // perform the test
+        actual, err := ParseExpression(set.raw)
+
+        // was an error returned?
+        if err != nil {
+            t.Errorf("%q: %v", set.raw, err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual.Operator != set.expected {
+            t.Errorf("%q: expected %s, received %s", set.raw, set.expected, actual.Operator)
+            return
+        }
+    }
+}
+
+func TestCanUseRegisteredOperatorsInConstraints(t *testing.T) {
+    restoreOperators(t)
+    if _, err := RegisterOperator("~>", matchesSameMinor); err != nil {
+        t.Error(err)
+        return
+    }
+
+    // perform the test
+    lhs, err := ParseConstraint("~>1.2 || ~>2.0, !=2.0.1")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if lhs.String() != "~>1.2.0 || ~>2.0.0, !=2.0.1" {
+        t.Errorf("Expected ~>1.2.0 || ~>2.0.0, !=2.0.1, received %s", lhs.String())
+        return
+    }
+    for version, expected := range map[string]bool{"1.2.7": true, "1.3.0": false, "2.0.1": false, "2.0.2": true, "2.0.3-rc.1": false} {
+        actual, _ := lhs.Matches(version)
+        if actual != expected {
+            t.Errorf("%s: expected %v, received %v", version, expected, actual)
+            return
+        }
+    }
+
+    // we cannot turn a MatchFunc into a Range
+    _, err = NewConstraintRange(lhs)
+    if !errors.Is(err, ErrNotARange) {
+        t.Errorf("Expected ErrNotARange, received %v", err)
+        return
+    }
+}
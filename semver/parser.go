@@ -2,8 +2,6 @@ package semver
 
 import (
     "fmt"
-    "regexp"
-    "strconv"
     "strings"
 )
 
@@ -12,59 +10,61 @@ import (
 // create one by calling:
 //
 //     exp = semver.ParseExpression("<operator><version>")
+//
+// Expressions that use the '@' operator hold a Reference instead of a
+// Version. Hyphen ranges and X-ranges (OP_RANGE) also hold an upper
+// bound.
 type VersionExpression struct {
-    Operator int        // which operator are we using?
-    Version  SemVersion // which version is specified?
+    Operator      Operator   // which operator are we using?
+    Version       SemVersion // which version is specified?
+    Reference     Reference  // which branch, tag or commit ID is specified?
+    UpperOperator Operator   // OP_LT or OP_LT_EQUALS, for OP_RANGE
+    Upper         SemVersion // the upper bound, for OP_RANGE
+
+    // how many of X, Y and Z were given, for OP_CARET; 0 means all three
+    //
+    // '^' treats a missing part differently from a zero: '^0.0' is
+    // '>=0.0.0, <0.1.0', but '^0.0.0' only matches '0.0.0'
+    Parts int
 }
 
 // value of VersionExpression.Operator when the expression requires an
 // exact match
-const OP_EQUALS = 0
+const OP_EQUALS Operator = 0
 
 // value of VersionExpression.Operator when the expression requires a
 // version that is greater than or equal to
-const OP_GT_EQUALS = 1
+const OP_GT_EQUALS Operator = 1
 
 // value of VersionExpression.Operator when the expression requires a
 // version that is less than or equal to
-const OP_LT_EQUALS = 2
+const OP_LT_EQUALS Operator = 2
 
 // value of VersionExpression.Operator when the expression requires a
 // version that is both compatible (ie same major version) AND greater
 // than or equal to
-const OP_TILDE = 3
+const OP_TILDE Operator = 3
 
 // value of VersionExpression.Operator when the expression requires a
 // non-version string of some kind (such as a commit_id or a branch name)
-//
-// currently unsupported
-const OP_AT = 4
+const OP_AT Operator = 4
 
 // value of VersionExpression.Operator when the expression requires
 // a version that does NOT equal
-const OP_NOT_EQUALS = 5
+const OP_NOT_EQUALS Operator = 5
 
-// a list of supported operators
-var opList = []string{"=", ">=", "<=", "~", "@", "!="}
-
-// holds our compiled regexes, so that we don't have to compile them
-// more than once
-var versionRegexes [4]*regexp.Regexp
+// value of VersionExpression.Operator when the expression requires a
+// version that is strictly greater than
+const OP_GT Operator = 6
 
-// compiles all of the regexes that we need to parse version strings
-func init() {
-    // here are the regex's that will match version strings
-    var regexes [4]string
-    regexes[0] = "(?P<Major>[0-9]+)\\.(?P<Minor>[0-9]+)\\.(?P<Patchlevel>[0-9]+)-(?P<Stability>[^-]+)-(?P<Release>[0-9]+)" // x.y.z-<stability>-r
-    regexes[1] = "(?P<Major>[0-9]+)\\.(?P<Minor>[0-9]+)\\.(?P<Patchlevel>[0-9]+)"                                          // x.y.z
-    regexes[2] = "(?P<Major>[0-9]+)\\.(?P<Minor>[0-9]+)-(?P<Stability>[^-]+)-(?P<Release>[0-9]+)"                          // x.y-<stability>-r
-    regexes[3] = "(?P<Major>[0-9]+)\\.(?P<Minor>[0-9]+)$"                                                                  // x.y
+// value of VersionExpression.Operator when the expression requires a
+// version that is strictly less than
+const OP_LT Operator = 7
 
-    // compile the regexes to use later
-    for i, regex := range regexes {
-        versionRegexes[i] = regexp.MustCompile(regex)
-    }
-}
+// value of VersionExpression.Operator when the expression requires a
+// version that is greater than or equal to, and that does not change
+// the left-most non-zero number in X.Y.Z (ie npm and Cargo's caret)
+const OP_CARET Operator = 8
 
 // ParseExpression converts a version expression string into a
 // VersionExpression struct.
@@ -72,34 +72,138 @@ func init() {
 // Takes an expression of the form:
 //
 //     <OPERATOR><version-string>
+//     @<branch|tag|commit_id>
+//     <version-string> - <version-string>
+//     X.Y.x, X.x, *
 //
 // and turns it into a VersionExpression struct
+//
+// <OPERATOR> is any of the OP_* operators, or one that has been added by
+// calling RegisterOperator(). If more than one operator matches, the
+// longest one wins.
+//
+// Hyphen ranges and X-ranges are desugared into '>=' (OP_GT_EQUALS) on
+// their own, or into OP_RANGE: a '>=' lower bound plus a '<' or '<='
+// upper bound. '1.2.3 - 2.3.4' means '>=1.2.3, <=2.3.4', '1.2.x' means
+// '>=1.2.0, <1.3.0', and '*' means '>=0.0.0'. A partial upper bound
+// works the way that it does in npm and Composer: '1.2 - 2.3' means
+// '>=1.2.0, <2.4.0'. 'x', 'X' and '*' are interchangeable.
+//
+// returns a *ParseError if the expression cannot be parsed; its Offset
+// is relative to the start of the expression
 func ParseExpression(exp string) (VersionExpression, error) {
+    return parseExpressionWithOffset(exp, 0)
+}
+
+func parseExpressionWithOffset(raw string, offset int) (VersionExpression, error) {
     // do we have an operator?
-    op, offset, err := startsWithOperator(exp)
+    op, next, err := startsWithOperator(raw, offset)
     if err != nil {
-        return VersionExpression{}, err
+        // hyphen ranges and X-ranges do not start with an operator
+        exp, isRange, rangeErr := parseRangeWithOffset(raw, offset)
+        if !isRange {
+            return VersionExpression{}, err
+        }
+        return exp, rangeErr
+    }
+    offset = next
+
+    // '@' is followed by a branch, tag or commit ID instead
+    if op == OP_AT {
+        ref, err := parseReferenceWithOffset(raw, offset)
+        if err != nil {
+            return VersionExpression{}, err
+        }
+        return VersionExpression{Operator: op, Reference: ref}, nil
+    }
+
+    // '^' also accepts 'X' on its own, and needs to know which parts
+    // were left out
+    if op == OP_CARET {
+        return parseCaretWithOffset(raw, offset)
     }
 
     // do we have a semantically-correct version number too?
-    version, err := parseVersionWithOffset(exp, offset)
+    version, err := parseVersionWithOffset(raw, offset)
     if err != nil {
         return VersionExpression{}, err
     }
 
-    parsed := VersionExpression{op, version}
+    parsed := VersionExpression{Operator: op, Version: version}
     return parsed, nil
 }
 
-func startsWithOperator(raw string) (int, int, error) {
+// parseCaretWithOffset parses the version that follows '^', which can be
+// X, X.Y or a complete version string
+func parseCaretWithOffset(raw string, offset int) (VersionExpression, error) {
+    parts := countVersionParts(raw[offset:])
+
+    // 'X' on its own is not a version string
+    if parts == 1 {
+        partial, err := parsePartialVersion(raw, offset, len(raw))
+        if err != nil {
+            return VersionExpression{}, err
+        }
+        return VersionExpression{Operator: OP_CARET, Version: partial.version, Parts: 1}, nil
+    }
+
+    version, err := parseVersionWithOffset(raw, offset)
+    if err != nil {
+        return VersionExpression{}, err
+    }
+
+    parsed := VersionExpression{Operator: OP_CARET, Version: version}
+    if parts == 2 {
+        parsed.Parts = 2
+    }
+    return parsed, nil
+}
+
+// countVersionParts works out how many numbers there are in 'version',
+// if it is nothing but numbers separated by '.'
+//
+// returns 0 if 'version' contains anything else
+func countVersionParts(version string) int {
+    if version == "" {
+        return 0
+    }
+
+    parts := 1
+    for i := 0; i < len(version); i++ {
+        switch {
+        case version[i] == '.':
+            parts++
+        case !isDigit(version[i]):
+            return 0
+        }
+    }
+
+    return parts
+}
+
+// startsWithOperator works out which operator starts at raw[offset]
+//
+// when more than one operator matches (e.g. '>' and '>='), we use the
+// longest one
+//
+// returns the operator, and the offset of whatever follows it
+func startsWithOperator(raw string, offset int) (Operator, int, error) {
+    found := Operator(-1)
     for i, opToEval := range opList {
-        if strings.HasPrefix(raw, opToEval) {
-            return i, len(opToEval), nil
+        if Operator(i) == OP_RANGE || !strings.HasPrefix(raw[offset:], opToEval.symbol) {
+            continue
+        }
+        if !found.IsValid() || len(opToEval.symbol) > len(opList[found].symbol) {
+            found = Operator(i)
         }
     }
 
-    // if we get here, then we cannot decode the string
-    return -1, -1, fmt.Errorf("unrecognised operator")
+    // if we get here without an operator, then we cannot decode the string
+    if !found.IsValid() {
+        return -1, -1, newParseError(raw, offset, expectOperator)
+    }
+
+    return found, offset + len(opList[found].symbol), nil
 }
 
 // ParseVersion takes a version string and turns it into a SemVersion
@@ -111,49 +215,419 @@ func startsWithOperator(raw string) (int, int, error) {
 //     X.Y.Z
 //     X.Y-<stability>-R
 //     X.Y.Z-<stability>-R
+//     X.Y.Z-<pre.release>
+//
+// all of which can be followed by '+<build.metadata>', and turns it into
+// a SemVersion struct
+//
+// A version string that matches our X.Y.Z-<stability>-R format is always
+// treated as one; anything else after X.Y.Z is parsed as a list of
+// SemVer 2.0.0 pre-release identifiers.
 //
-// and turns it into a SemVersion struct
+// The whole string must be a version string. If it isn't, you get back
+// a *ParseError that tells you where parsing failed.
 func ParseVersion(version string) (SemVersion, error) {
     return parseVersionWithOffset(version, 0)
 }
 
+// ParseSemVer2 takes a version string and turns it into a SemVersion
+// struct, following the Semantic Versioning 2.0.0 grammar exactly.
+//
+// Takes any of these strings:
+//
+//     X.Y.Z
+//     X.Y.Z-<pre.release>
+//     X.Y.Z+<build.metadata>
+//     X.Y.Z-<pre.release>+<build.metadata>
+//
+// The Stability and Release fields are never set; anything after a '-'
+// is stored in the PreRelease field instead. Numbers with leading zeroes
+// are rejected, as required by the spec.
+func ParseSemVer2(version string) (SemVersion, error) {
+    return parseVersion(version, 0, true)
+}
+
 func parseVersionWithOffset(raw string, offset int) (SemVersion, error) {
-    for _, re := range versionRegexes {
-        matches := re.FindStringSubmatch(raw)
-        if len(matches) == 0 {
-            continue
+    return parseVersion(raw, offset, false)
+}
+
+func parseVersion(raw string, offset int, semVer2 bool) (SemVersion, error) {
+    scanned, err := scanVersion(raw, offset, semVer2)
+    if err != nil {
+        return SemVersion{}, err
+    }
+
+    parsed, err := scanned.toSemVersion(raw)
+    if err != nil {
+        return SemVersion{}, err
+    }
+
+    return parsed, nil
+}
+
+// ParseError is returned when a version string or a version expression
+// cannot be parsed.
+//
+// Use errors.As() to find out where parsing failed:
+//
+//     var parseErr *semver.ParseError
+//     if errors.As(err, &parseErr) {
+//         fmt.Println(parseErr.Offset, parseErr.Expected)
+//     }
+type ParseError struct {
+    Input    string // the string that we were asked to parse
+    Offset   int    // byte offset into Input where parsing failed
+    Expected string // what we expected to find at Offset
+    Err      error  // the underlying problem, if there is one
+}
+
+// the things that a ParseError can tell you we were expecting
+const (
+    expectOperator   = "operator"
+    expectMajor      = "major version number"
+    expectMinor      = "minor version number"
+    expectPatchLevel = "patch level"
+    expectStability  = "stability level"
+    expectRelease    = "release number"
+    expectPreRelease = "pre-release identifier"
+    expectBuild      = "build metadata identifier"
+    expectDot        = "'.'"
+    expectHyphen     = "'-'"
+)
+
+func newParseError(input string, offset int, expected string) *ParseError {
+    return &ParseError{
+        Input:    input,
+        Offset:   offset,
+        Expected: expected,
+    }
+}
+
+// Error returns a description of what went wrong, and where
+func (e *ParseError) Error() string {
+    if e.Err != nil {
+        return fmt.Sprintf("cannot parse %q: %s at offset %d: %v", e.Input, e.Expected, e.Offset, e.Err)
+    }
+    if e.Offset >= len(e.Input) {
+        return fmt.Sprintf("cannot parse %q: expected %s at end of input", e.Input, e.Expected)
+    }
+
+    return fmt.Sprintf("cannot parse %q: expected %s at offset %d, found %q", e.Input, e.Expected, e.Offset, e.Input[e.Offset])
+}
+
+// Unwrap returns the underlying problem, so that errors.Is() can find
+// errors such as ErrNumberOverflow
+func (e *ParseError) Unwrap() error {
+    return e.Err
+}
+
+// scannedVersion holds the parts of a version string, as found by
+// scanVersion()
+//
+// the numbers are left as spans of digits, so that they can be turned
+// into ints or into big.Ints afterwards
+type scannedVersion struct {
+    major      digits
+    minor      digits
+    patchLevel digits
+    release    digits
+    stability  string
+    preRelease string
+    build      string
+}
+
+// digits marks where a number starts and ends in the string that we
+// scanned; an empty span means the number was not there at all
+type digits struct {
+    start int
+    end   int
+}
+
+// scanVersion parses the version string that starts at raw[offset], in
+// a single pass over the bytes
+//
+// everything that we find points back into 'raw', so we don't allocate
+// any memory unless we need to return an error
+func scanVersion(raw string, offset int, semVer2 bool) (scannedVersion, *ParseError) {
+    var version scannedVersion
+    var pos int
+    var ok bool
+
+    // X.Y is always required
+    version.major, pos, ok = scanNumber(raw, offset, semVer2)
+    if !ok {
+        return scannedVersion{}, newParseError(raw, pos, expectMajor)
+    }
+    if pos >= len(raw) || raw[pos] != '.' {
+        return scannedVersion{}, newParseError(raw, pos, expectDot)
+    }
+    version.minor, pos, ok = scanNumber(raw, pos+1, semVer2)
+    if !ok {
+        return scannedVersion{}, newParseError(raw, pos, expectMinor)
+    }
+
+    // .Z is optional, unless we are following SemVer 2.0.0
+    hasPatchLevel := false
+    if pos < len(raw) && raw[pos] == '.' {
+        version.patchLevel, pos, ok = scanNumber(raw, pos+1, semVer2)
+        if !ok {
+            return scannedVersion{}, newParseError(raw, pos, expectPatchLevel)
         }
+        hasPatchLevel = true
+    } else if semVer2 {
+        return scannedVersion{}, newParseError(raw, pos, expectDot)
+    }
 
-        // store the named results
-        capture := make(map[string]string)
-        for i, name := range re.SubexpNames() {
-            if i == 0 || name == "" {
-                continue
-            }
-            capture[name] = matches[i]
+    var failure scanFailure
+    switch {
+    case semVer2:
+        failure = scanPreReleaseTail(raw, pos, &version)
+
+    case !hasPatchLevel:
+        failure = scanStabilityTail(raw, pos, false, &version)
+
+    default:
+        // X.Y.Z can be followed by either kind of tail; we always try
+        // ours first, and if neither fits, the failure from the one
+        // that got furthest is the most useful
+        unstable := version
+        failure = scanStabilityTail(raw, pos, true, &unstable)
+        if !failure.failed() {
+            return unstable, nil
+        }
+        preReleaseFailure := scanPreReleaseTail(raw, pos, &version)
+        if !preReleaseFailure.failed() {
+            return version, nil
         }
+        if preReleaseFailure.offset > failure.offset {
+            failure = preReleaseFailure
+        }
+    }
 
-        // build our return value
-        version := SemVersion{}
+    if failure.failed() {
+        return scannedVersion{}, newParseError(raw, failure.offset, failure.expected)
+    }
 
-        // Major and Minor versions are present in all of the regexes
-        // that we use
-        version.Major, _ = strconv.Atoi(capture["Major"])
-        version.Minor, _ = strconv.Atoi(capture["Minor"])
+    return version, nil
+}
+
+// toSemVersion converts the parts of a scanned version string into a
+// SemVersion struct
+//
+// returns a ParseError wrapping ErrNumberOverflow if any of the numbers
+// are too large to fit into an int
+func (s *scannedVersion) toSemVersion(raw string) (SemVersion, *ParseError) {
+    var version SemVersion
+    var ok bool
 
-        // the remaining elements are optional
-        if capture["Patchlevel"] != "" {
-            version.PatchLevel, _ = strconv.Atoi(capture["Patchlevel"])
+    if version.Major, ok = s.major.toInt(raw); !ok {
+        return SemVersion{}, newOverflowError(raw, s.major.start, expectMajor)
+    }
+    if version.Minor, ok = s.minor.toInt(raw); !ok {
+        return SemVersion{}, newOverflowError(raw, s.minor.start, expectMinor)
+    }
+    if version.PatchLevel, ok = s.patchLevel.toInt(raw); !ok {
+        return SemVersion{}, newOverflowError(raw, s.patchLevel.start, expectPatchLevel)
+    }
+    if version.Release, ok = s.release.toInt(raw); !ok {
+        return SemVersion{}, newOverflowError(raw, s.release.start, expectRelease)
+    }
+    version.Stability = s.stability
+    version.PreRelease = s.preRelease
+    version.Build = s.build
+
+    return version, nil
+}
+
+// the largest number that we can store in a SemVersion field
+const maxInt = int(^uint(0) >> 1)
+
+// toInt converts a span of digits into an int
+//
+// returns false if the number is too large to fit into an int
+func (d digits) toInt(raw string) (int, bool) {
+    value := 0
+    for i := d.start; i < d.end; i++ {
+        digit := int(raw[i] - '0')
+        if value > (maxInt-digit)/10 {
+            return 0, false
         }
-        if capture["Stability"] != "" {
-            version.Stability = capture["Stability"]
+        value = value*10 + digit
+    }
+
+    return value, true
+}
+
+func newOverflowError(input string, offset int, expected string) *ParseError {
+    return &ParseError{
+        Input:    input,
+        Offset:   offset,
+        Expected: expected,
+        Err:      ErrNumberOverflow,
+    }
+}
+
+// scanFailure tells us where scanning failed, and what we expected to
+// find there
+//
+// we use this instead of a ParseError while we are still trying out
+// alternatives, so that we don't allocate anything
+type scanFailure struct {
+    offset   int
+    expected string
+}
+
+// failed returns true if scanning failed
+func (f scanFailure) failed() bool {
+    return f.expected != ""
+}
+
+// what we expect to find after each part of a version string, if it is
+// not the end of the version string
+const (
+    expectAfterMajorMinor  = "'.', '-', '+' or end of version string"
+    expectAfterPatchLevel  = "'-', '+' or end of version string"
+    expectAfterRelease     = "'+' or end of version string"
+    expectAfterIdentifiers = "'.', '+' or end of version string"
+    expectAfterBuild       = "'.' or end of version string"
+)
+
+// scanStabilityTail parses everything after X.Y[.Z], which must be of
+// the form:
+//
+//     [-<stability>-R][+<build.metadata>]
+func scanStabilityTail(raw string, pos int, hasPatchLevel bool, version *scannedVersion) scanFailure {
+    expectEnd := expectAfterPatchLevel
+    if !hasPatchLevel {
+        expectEnd = expectAfterMajorMinor
+    }
+
+    if pos < len(raw) && raw[pos] == '-' {
+        pos++
+        start := pos
+        for pos < len(raw) && isStabilityChar(raw[pos]) {
+            pos++
+        }
+        if pos == start {
+            return scanFailure{pos, expectStability}
+        }
+        if pos >= len(raw) || raw[pos] != '-' {
+            return scanFailure{pos, expectHyphen}
+        }
+        version.stability = raw[start:pos]
+
+        var ok bool
+        version.release, pos, ok = scanNumber(raw, pos+1, false)
+        if !ok {
+            return scanFailure{pos, expectRelease}
+        }
+        expectEnd = expectAfterRelease
+    }
+
+    return scanBuildTail(raw, pos, expectEnd, version)
+}
+
+// scanPreReleaseTail parses everything after X.Y.Z, which must be of the
+// form:
+//
+//     [-<pre.release>][+<build.metadata>]
+func scanPreReleaseTail(raw string, pos int, version *scannedVersion) scanFailure {
+    expectEnd := expectAfterPatchLevel
+
+    if pos < len(raw) && raw[pos] == '-' {
+        start := pos + 1
+        var failure scanFailure
+        pos, failure = scanIdentifiers(raw, start, true)
+        if failure.failed() {
+            return failure
+        }
+        version.preRelease = raw[start:pos]
+        expectEnd = expectAfterIdentifiers
+    }
+
+    return scanBuildTail(raw, pos, expectEnd, version)
+}
+
+// scanBuildTail parses everything from raw[pos] onwards, which must be
+// either empty, or of the form:
+//
+//     +<build.metadata>
+func scanBuildTail(raw string, pos int, expectEnd string, version *scannedVersion) scanFailure {
+    if pos < len(raw) && raw[pos] == '+' {
+        start := pos + 1
+        var failure scanFailure
+        pos, failure = scanIdentifiers(raw, start, false)
+        if failure.failed() {
+            return failure
+        }
+        version.build = raw[start:pos]
+        expectEnd = expectAfterBuild
+    }
+
+    if pos < len(raw) {
+        return scanFailure{pos, expectEnd}
+    }
+
+    return scanFailure{}
+}
+
+// scanNumber skips over the digits that start at raw[pos]
+//
+// returns where the digits are, the offset of the first non-digit, and
+// whether or not we found an acceptable number; if 'strict' is set,
+// numbers with leading zeroes are not acceptable
+func scanNumber(raw string, pos int, strict bool) (digits, int, bool) {
+    start := pos
+    for pos < len(raw) && raw[pos] >= '0' && raw[pos] <= '9' {
+        pos++
+    }
+    if pos == start {
+        return digits{}, pos, false
+    }
+    if strict && raw[start] == '0' && pos-start > 1 {
+        return digits{}, start, false
+    }
+
+    return digits{start, pos}, pos, true
+}
+
+// scanIdentifiers skips over a list of dot-separated identifiers that
+// starts at raw[pos]
+//
+// SemVer 2.0.0 does not allow numeric pre-release identifiers to have
+// leading zeroes, but it does allow them in build metadata
+func scanIdentifiers(raw string, pos int, isPreRelease bool) (int, scanFailure) {
+    expected := expectBuild
+    if isPreRelease {
+        expected = expectPreRelease
+    }
+
+    for {
+        start := pos
+        for pos < len(raw) && isIdentifierChar(raw[pos]) {
+            pos++
         }
-        if capture["Release"] != "" {
-            version.Release, _ = strconv.Atoi(capture["Release"])
+        if pos == start {
+            return pos, scanFailure{pos, expected}
+        }
+        if isPreRelease && raw[start] == '0' && pos-start > 1 && isNumericIdentifier(raw[start:pos]) {
+            return start, scanFailure{start, expected}
         }
 
-        return version, nil
+        if pos >= len(raw) || raw[pos] != '.' {
+            return pos, scanFailure{}
+        }
+        pos++
     }
+}
+
+// isStabilityChar returns true if 'c' can appear in a stability level
+func isStabilityChar(c byte) bool {
+    return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_'
+}
 
-    return SemVersion{}, fmt.Errorf("don't know how to interpret matches yet")
+// isIdentifierChar returns true if 'c' can appear in a SemVer 2.0.0
+// pre-release or build metadata identifier
+func isIdentifierChar(c byte) bool {
+    return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-'
 }
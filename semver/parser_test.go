@@ -1,6 +1,9 @@
 package semver
 
 import (
+    "errors"
+    "regexp"
+    "strconv"
     "testing"
 )
 
@@ -192,6 +195,329 @@ func TestStabilityLevelCanIncludeAnUnderscore(t *testing.T) {
     }
 }
 
+func TestCanParseSemVer2PreRelease(t *testing.T) {
+    // what result do we expect?
+    expected := SemVersion{
+        Major:      2,
+        Minor:      0,
+        PatchLevel: 0,
+        PreRelease: "alpha.beta.11",
+    }
+
+    // perform the test
+    actual, err := ParseVersion("2.0.0-alpha.beta.11")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %d, received %d", expected, actual)
+        return
+    }
+}
+
+func TestCanParseSemVer2PreReleaseAndBuild(t *testing.T) {
+    // what result do we expect?
+    expected := SemVersion{
+        Major:      1,
+        Minor:      2,
+        PatchLevel: 3,
+        PreRelease: "rc.1",
+        Build:      "build.5",
+    }
+
+    // perform the test
+    actual, err := ParseVersion("1.2.3-rc.1+build.5")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %d, received %d", expected, actual)
+        return
+    }
+}
+
+func TestCanParseSemVer2Build(t *testing.T) {
+    // what result do we expect?
+    expected := SemVersion{
+        Major:      1,
+        Minor:      2,
+        PatchLevel: 3,
+        Build:      "20130313144700",
+    }
+
+    // perform the test
+    actual, err := ParseVersion("1.2.3+20130313144700")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %d, received %d", expected, actual)
+        return
+    }
+}
+
+func TestCanParseUnstableReleaseWithBuild(t *testing.T) {
+    // what result do we expect?
+    expected := SemVersion{
+        Major:      1,
+        Minor:      3,
+        PatchLevel: 6,
+        Stability:  "alpha",
+        Release:    1,
+        Build:      "exp.sha.5114f85",
+    }
+
+    // perform the test
+    actual, err := ParseVersion("1.3.6-alpha-1+exp.sha.5114f85")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %d, received %d", expected, actual)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for ParseSemVer2()
+//
+// ------------------------------------------------------------------------
+
+func TestSemVer2ModeTreatsStabilityAsPreRelease(t *testing.T) {
+    // what result do we expect?
+    expected := SemVersion{
+        Major:      1,
+        Minor:      0,
+        PatchLevel: 0,
+        PreRelease: "alpha-1",
+    }
+
+    // perform the test
+    actual, err := ParseSemVer2("1.0.0-alpha-1")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %d, received %d", expected, actual)
+        return
+    }
+}
+
+func TestSemVer2ModeCanParsePreReleaseAndBuild(t *testing.T) {
+    // what result do we expect?
+    expected := SemVersion{
+        Major:      1,
+        Minor:      0,
+        PatchLevel: 0,
+        PreRelease: "x.7.z.92",
+        Build:      "exp.sha.5114f85",
+    }
+
+    // perform the test
+    actual, err := ParseSemVer2("1.0.0-x.7.z.92+exp.sha.5114f85")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %d, received %d", expected, actual)
+        return
+    }
+}
+
+func TestSemVer2ModeRejectsInvalidVersions(t *testing.T) {
+    // all of these are forbidden by the SemVer 2.0.0 spec
+    var toParse = []string{
+        "1.3",
+        "01.3.0",
+        "1.03.0",
+        "1.3.00",
+        "1.0.0-alpha.01",
+        "1.0.0-alpha..1",
+        "1.0.0-",
+        "1.0.0+",
+        "1.0.0-alpha_romeo",
+    }
+
+    for _, raw := range toParse {
+        // perform the test
+        _, err := ParseSemVer2(raw)
+
+        // was an error returned?
+        if err == nil {
+            t.Errorf("Expected an error parsing %s", raw)
+            return
+        }
+    }
+}
+
+func TestParseVersionRejectsGarbage(t *testing.T) {
+    // our list of strings to parse, and where we expect parsing to fail
+    var toParse = []ParseError{
+        ParseError{"", 0, expectMajor, nil},
+        ParseError{"foo1.2.3", 0, expectMajor, nil},
+        ParseError{"v1.2.3", 0, expectMajor, nil},
+        ParseError{"1", 1, expectDot, nil},
+        ParseError{"1.", 2, expectMinor, nil},
+        ParseError{"1.2.", 4, expectPatchLevel, nil},
+        ParseError{"1.2.3bar", 5, "'-', '+' or end of version string", nil},
+        ParseError{"1.2.3.4.5", 5, "'-', '+' or end of version string", nil},
+        ParseError{"1.2bar", 3, "'.', '-', '+' or end of version string", nil},
+        ParseError{"1.3-alpha-1bar", 11, "'+' or end of version string", nil},
+        ParseError{"1.3-alpha", 9, expectHyphen, nil},
+        ParseError{"1.3-alpha-", 10, expectRelease, nil},
+        ParseError{"1.3--1", 4, expectStability, nil},
+        ParseError{"1.2.3-rc..1", 9, expectPreRelease, nil},
+        ParseError{"1.2.3-rc.1+", 11, expectBuild, nil},
+        ParseError{"1.2.3-rc.1+build..5", 17, expectBuild, nil},
+        ParseError{"1.2.3 ", 5, "'-', '+' or end of version string", nil},
+    }
+
+    for _, expected := range toParse {
+        // perform the test
+        _, err := ParseVersion(expected.Input)
+
+        // did we get back what we expected?
+        var actual *ParseError
+        if !errors.As(err, &actual) {
+            t.Errorf("Expected a ParseError for %q, received %v", expected.Input, err)
+            return
+        }
+        if *actual != expected {
+            t.Errorf("Expected %+v, received %+v", expected, *actual)
+            return
+        }
+    }
+}
+
+func TestParseExpressionReportsOffsetIntoExpression(t *testing.T) {
+    // our list of expressions to parse, and where we expect parsing to fail
+    var toParse = []ParseError{
+        ParseError{"1.2.3", 0, expectOperator, nil},
+        ParseError{"x1.2.3", 0, expectOperator, nil},
+        ParseError{">=foo", 2, expectMajor, nil},
+        ParseError{"~ 1.3", 1, expectMajor, nil},
+        ParseError{">=1.3bar", 5, "'.', '-', '+' or end of version string", nil},
+        ParseError{"!=1.3.0.1", 7, "'-', '+' or end of version string", nil},
+    }
+
+    for _, expected := range toParse {
+        // perform the test
+        _, err := ParseExpression(expected.Input)
+
+        // did we get back what we expected?
+        var actual *ParseError
+        if !errors.As(err, &actual) {
+            t.Errorf("Expected a ParseError for %q, received %v", expected.Input, err)
+            return
+        }
+        if *actual != expected {
+            t.Errorf("Expected %+v, received %+v", expected, *actual)
+            return
+        }
+    }
+}
+
+func TestParseVersionDetectsOverflow(t *testing.T) {
+    // our list of strings to parse, and where we expect parsing to fail
+    var toParse = []ParseError{
+        ParseError{"99999999999999999999.0", 0, expectMajor, ErrNumberOverflow},
+        ParseError{"1.99999999999999999999", 2, expectMinor, ErrNumberOverflow},
+        ParseError{"1.3.99999999999999999999", 4, expectPatchLevel, ErrNumberOverflow},
+        ParseError{"1.1.0-SNAPSHOT-99999999999999999999", 15, expectRelease, ErrNumberOverflow},
+    }
+
+    for _, expected := range toParse {
+        // perform the test
+        _, err := ParseVersion(expected.Input)
+
+        // did we get back what we expected?
+        if !errors.Is(err, ErrNumberOverflow) {
+            t.Errorf("Expected ErrNumberOverflow for %q, received %v", expected.Input, err)
+            return
+        }
+        var actual *ParseError
+        if !errors.As(err, &actual) {
+            t.Errorf("Expected a ParseError for %q, received %v", expected.Input, err)
+            return
+        }
+        if *actual != expected {
+            t.Errorf("Expected %+v, received %+v", expected, *actual)
+            return
+        }
+    }
+}
+
+func TestParseVersionAcceptsLargestInt(t *testing.T) {
+    // what result do we expect?
+    expected := SemVersion{
+        Major:      1,
+        Minor:      1,
+        PatchLevel: 0,
+        Stability:  "SNAPSHOT",
+        Release:    maxInt,
+    }
+
+    // perform the test
+    actual, err := ParseVersion("1.1.0-SNAPSHOT-" + strconv.Itoa(maxInt))
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %d, received %d", expected, actual)
+        return
+    }
+}
+
+func TestParseErrorExplainsWhatWentWrong(t *testing.T) {
+    // what result do we expect?
+    expected := `cannot parse "1.2.3bar": expected '-', '+' or end of version string at offset 5, found 'b'`
+
+    // perform the test
+    _, err := ParseVersion("1.2.3bar")
+
+    // did we get back what we expected?
+    if err == nil || err.Error() != expected {
+        t.Errorf("Expected %s, received %v", expected, err)
+        return
+    }
+}
+
 // ========================================================================
 //
 // Tests for Parse() with =
@@ -662,3 +988,481 @@ func TestCanParseNotEqualsOperatorWithMajorMinorPatchlevelUnstableRelease(t *tes
         return
     }
 }
+
+// ========================================================================
+//
+// Tests for Parse() with >
+//
+// ------------------------------------------------------------------------
+
+func TestCanParseGreaterThanOperatorWithMajorMinor(t *testing.T) {
+    // what result do we expect?
+    expected := VersionExpression{
+        Operator: OP_GT,
+        Version: SemVersion{
+            Major:      1,
+            Minor:      3,
+            PatchLevel: 0,
+            Stability:  "",
+            Release:    0,
+        },
+    }
+
+    // perform the test
+    actual, err := ParseExpression(">1.3")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %d, received %d", expected, actual)
+        return
+    }
+}
+
+func TestCanParseGreaterThanOperatorWithMajorMinorPatchlevel(t *testing.T) {
+    // what result do we expect?
+    expected := VersionExpression{
+        Operator: OP_GT,
+        Version: SemVersion{
+            Major:      1,
+            Minor:      3,
+            PatchLevel: 6,
+            Stability:  "",
+            Release:    0,
+        },
+    }
+
+    // perform the test
+    actual, err := ParseExpression(">1.3.6")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %d, received %d", expected, actual)
+        return
+    }
+}
+
+func TestCanParseGreaterThanOperatorWithMajorMinorPatchlevelUnstableRelease(t *testing.T) {
+    // what result do we expect?
+    expected := VersionExpression{
+        Operator: OP_GT,
+        Version: SemVersion{
+            Major:      1,
+            Minor:      3,
+            PatchLevel: 6,
+            Stability:  "alpha",
+            Release:    1,
+        },
+    }
+
+    // perform the test
+    actual, err := ParseExpression(">1.3.6-alpha-1")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %d, received %d", expected, actual)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for Parse() with <
+//
+// ------------------------------------------------------------------------
+
+func TestCanParseLessThanOperatorWithMajorMinor(t *testing.T) {
+    // what result do we expect?
+    expected := VersionExpression{
+        Operator: OP_LT,
+        Version: SemVersion{
+            Major:      1,
+            Minor:      3,
+            PatchLevel: 0,
+            Stability:  "",
+            Release:    0,
+        },
+    }
+
+    // perform the test
+    actual, err := ParseExpression("<1.3")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %d, received %d", expected, actual)
+        return
+    }
+}
+
+func TestCanParseLessThanOperatorWithMajorMinorPatchlevel(t *testing.T) {
+    // what result do we expect?
+    expected := VersionExpression{
+        Operator: OP_LT,
+        Version: SemVersion{
+            Major:      1,
+            Minor:      3,
+            PatchLevel: 6,
+            Stability:  "",
+            Release:    0,
+        },
+    }
+
+    // perform the test
+    actual, err := ParseExpression("<1.3.6")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %d, received %d", expected, actual)
+        return
+    }
+}
+
+func TestCanParseLessThanOperatorWithMajorMinorPatchlevelUnstableRelease(t *testing.T) {
+    // what result do we expect?
+    expected := VersionExpression{
+        Operator: OP_LT,
+        Version: SemVersion{
+            Major:      1,
+            Minor:      3,
+            PatchLevel: 6,
+            Stability:  "alpha",
+            Release:    1,
+        },
+    }
+
+    // perform the test
+    actual, err := ParseExpression("<1.3.6-alpha-1")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %d, received %d", expected, actual)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for Parse() with ^
+//
+// ------------------------------------------------------------------------
+
+func TestCanParseCaretOperatorWithMajorMinor(t *testing.T) {
+    // what result do we expect?
+    expected := VersionExpression{
+        Operator: OP_CARET,
+        Version: SemVersion{
+            Major:      1,
+            Minor:      3,
+            PatchLevel: 0,
+            Stability:  "",
+            Release:    0,
+        },
+        Parts: 2,
+    }
+
+    // perform the test
+    actual, err := ParseExpression("^1.3")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %d, received %d", expected, actual)
+        return
+    }
+}
+
+func TestCanParseCaretOperatorWithMajor(t *testing.T) {
+    // what result do we expect?
+    expected := VersionExpression{
+        Operator: OP_CARET,
+        Version: SemVersion{
+            Major: 0,
+        },
+        Parts: 1,
+    }
+
+    // perform the test
+    actual, err := ParseExpression("^0")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %d, received %d", expected, actual)
+        return
+    }
+    if actual.String() != "^0" {
+        t.Errorf("Expected ^0, received %s", actual)
+        return
+    }
+}
+
+func TestCanParseCaretOperatorWithMajorMinorPatchlevel(t *testing.T) {
+    // what result do we expect?
+    expected := VersionExpression{
+        Operator: OP_CARET,
+        Version: SemVersion{
+            Major:      0,
+            Minor:      3,
+            PatchLevel: 6,
+            Stability:  "",
+            Release:    0,
+        },
+    }
+
+    // perform the test
+    actual, err := ParseExpression("^0.3.6")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %d, received %d", expected, actual)
+        return
+    }
+}
+
+func TestCanParseCaretOperatorWithMajorMinorPatchlevelUnstableRelease(t *testing.T) {
+    // what result do we expect?
+    expected := VersionExpression{
+        Operator: OP_CARET,
+        Version: SemVersion{
+            Major:      1,
+            Minor:      3,
+            PatchLevel: 6,
+            Stability:  "alpha",
+            Release:    1,
+        },
+    }
+
+    // perform the test
+    actual, err := ParseExpression("^1.3.6-alpha-1")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %d, received %d", expected, actual)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for memory allocations
+//
+// ------------------------------------------------------------------------
+
+// a selection of the version strings that we see most often
+var commonVersions = []string{
+    "1.3",
+    "1.3.6",
+    "100.365.699",
+    "1.3-alpha-1",
+    "1.3.6-SNAPSHOT-20141013",
+    "1.2.3-rc.1+build.5",
+    "2.0.0-alpha.beta.11",
+}
+
+func TestParseVersionDoesNotAllocate(t *testing.T) {
+    for _, raw := range commonVersions {
+        // perform the test
+        allocs := testing.AllocsPerRun(100, func() {
+            ParseVersion(raw)
+        })
+
+        // did we get back what we expected?
+        if allocs != 0 {
+            t.Errorf("Expected 0 allocations parsing %s, received %v", raw, allocs)
+            return
+        }
+    }
+}
+
+func TestParseExpressionDoesNotAllocate(t *testing.T) {
+    // perform the test
+    allocs := testing.AllocsPerRun(100, func() {
+        ParseExpression(">=1.3.6-alpha-1")
+    })
+
+    // did we get back what we expected?
+    if allocs != 0 {
+        t.Errorf("Expected 0 allocations, received %v", allocs)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Benchmarks for ParseVersion()
+//
+// ------------------------------------------------------------------------
+
+// regexVersions holds the regexes that ParseVersion() used before it was
+// rewritten to scan the version string by hand; we keep them here so that
+// the benchmarks can show the difference
+var regexVersions = []*regexp.Regexp{
+    regexp.MustCompile("^(?P<Major>[0-9]+)\\.(?P<Minor>[0-9]+)\\.(?P<Patchlevel>[0-9]+)-(?P<Stability>[A-Za-z0-9_]+)-(?P<Release>[0-9]+)$"),
+    regexp.MustCompile("^(?P<Major>[0-9]+)\\.(?P<Minor>[0-9]+)\\.(?P<Patchlevel>[0-9]+)(?:-(?P<PreRelease>[0-9A-Za-z-]+(?:\\.[0-9A-Za-z-]+)*))?(?:\\+(?P<Build>[0-9A-Za-z-]+(?:\\.[0-9A-Za-z-]+)*))?$"),
+    regexp.MustCompile("^(?P<Major>[0-9]+)\\.(?P<Minor>[0-9]+)-(?P<Stability>[A-Za-z0-9_]+)-(?P<Release>[0-9]+)$"),
+    regexp.MustCompile("^(?P<Major>[0-9]+)\\.(?P<Minor>[0-9]+)$"),
+}
+
+// parseVersionWithRegexes is the old implementation of ParseVersion()
+func parseVersionWithRegexes(raw string) SemVersion {
+    for _, re := range regexVersions {
+        matches := re.FindStringSubmatch(raw)
+        if len(matches) == 0 {
+            continue
+        }
+
+        // store the named results
+        capture := make(map[string]string)
+        for i, name := range re.SubexpNames() {
+            if i == 0 || name == "" {
+                continue
+            }
+            capture[name] = matches[i]
+        }
+
+        version := SemVersion{}
+        version.Major, _ = strconv.Atoi(capture["Major"])
+        version.Minor, _ = strconv.Atoi(capture["Minor"])
+        version.PatchLevel, _ = strconv.Atoi(capture["Patchlevel"])
+        version.Stability = capture["Stability"]
+        version.Release, _ = strconv.Atoi(capture["Release"])
+        version.PreRelease = capture["PreRelease"]
+        version.Build = capture["Build"]
+
+        return version
+    }
+
+    return SemVersion{}
+}
+
+func TestRegexParserAgreesWithScanner(t *testing.T) {
+    for _, raw := range commonVersions {
+        // what result do we expect?
+        expected := parseVersionWithRegexes(raw)
+
+        // perform the test
+        actual, err := ParseVersion(raw)
+
+        // was an error returned?
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != expected {
+            t.Errorf("Expected %+v, received %+v", expected, actual)
+            return
+        }
+    }
+}
+
+func benchmarkParseVersion(b *testing.B, raw string) {
+    b.ReportAllocs()
+    for i := 0; i < b.N; i++ {
+        ParseVersion(raw)
+    }
+}
+
+func benchmarkParseVersionWithRegexes(b *testing.B, raw string) {
+    b.ReportAllocs()
+    for i := 0; i < b.N; i++ {
+        parseVersionWithRegexes(raw)
+    }
+}
+
+func BenchmarkParseVersionMajorMinor(b *testing.B) {
+    benchmarkParseVersion(b, "1.3")
+}
+
+func BenchmarkParseVersionWithRegexesMajorMinor(b *testing.B) {
+    benchmarkParseVersionWithRegexes(b, "1.3")
+}
+
+func BenchmarkParseVersionMajorMinorPatchlevel(b *testing.B) {
+    benchmarkParseVersion(b, "1.3.6")
+}
+
+func BenchmarkParseVersionWithRegexesMajorMinorPatchlevel(b *testing.B) {
+    benchmarkParseVersionWithRegexes(b, "1.3.6")
+}
+
+func BenchmarkParseVersionUnstableRelease(b *testing.B) {
+    benchmarkParseVersion(b, "1.3.6-SNAPSHOT-20141013")
+}
+
+func BenchmarkParseVersionWithRegexesUnstableRelease(b *testing.B) {
+    benchmarkParseVersionWithRegexes(b, "1.3.6-SNAPSHOT-20141013")
+}
+
+func BenchmarkParseVersionPreReleaseAndBuild(b *testing.B) {
+    benchmarkParseVersion(b, "1.2.3-rc.1+build.5")
+}
+
+func BenchmarkParseVersionWithRegexesPreReleaseAndBuild(b *testing.B) {
+    benchmarkParseVersionWithRegexes(b, "1.2.3-rc.1+build.5")
+}
+
+func BenchmarkParseExpression(b *testing.B) {
+    b.ReportAllocs()
+    for i := 0; i < b.N; i++ {
+        ParseExpression(">=1.3.6-alpha-1")
+    }
+}
@@ -0,0 +1,381 @@
+package semver
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+)
+
+// returned by NewRange() when an expression cannot be turned into a Range
+// (e.g. '@main', which does not describe any versions at all)
+var ErrNotARange = fmt.Errorf("expression does not describe a range of versions")
+
+// Bound is one end of an Interval
+type Bound struct {
+    Version   SemVersion // the version at this end of the interval
+    Inclusive bool       // true if Version is part of the interval
+    Unbounded bool       // true if the interval goes on forever in this direction
+}
+
+// Interval is a continuous run of versions, from Lower to Upper
+//
+// versions are put in the order that Cmp() puts them in
+type Interval struct {
+    Lower Bound
+    Upper Bound
+}
+
+// Range is a set of versions, held as a list of Intervals
+//
+// create one by calling:
+//
+//     r, err = semver.NewRange(exp)
+//     r, err = semver.NewConstraintRange(constraint)
+//
+// and combine them with Intersect(), Union() and Complement(). The
+// Intervals are always sorted, and never overlap or touch each other.
+//
+// A Range treats versions as a single line, in the order that Cmp() puts
+// them in. It doesn't know about the rules that stop '~' and '^' from
+// matching some pre-releases and unstable releases, so it can contain a
+// few more of them than the original expression matches.
+type Range struct {
+    Intervals []Interval
+}
+
+// NewRange works out which versions an expression matches
+//
+// '~' and '^' are turned into '>=' plus an exclusive upper bound of the
+// next breaking version's lowest pre-release (e.g. '~1.4' becomes
+// '>=1.4.0, <2.0.0-0'), honouring TildeMode
+//
+// returns ErrNotARange for '@' expressions and for operators added by
+// RegisterOperator(), and ErrUnknownOperator for operators that we don't
+// know about
+func NewRange(exp VersionExpression) (Range, error) {
+    v := exp.Version
+    below := Bound{Unbounded: true}
+    above := Bound{Unbounded: true}
+
+    switch exp.Operator {
+    case OP_EQUALS:
+        return newRange(Interval{Bound{v, true, false}, Bound{v, true, false}}), nil
+    case OP_NOT_EQUALS:
+        return newRange(
+            Interval{below, Bound{v, false, false}},
+            Interval{Bound{v, false, false}, above},
+        ), nil
+    case OP_GT_EQUALS:
+        return newRange(Interval{Bound{v, true, false}, above}), nil
+    case OP_GT:
+        return newRange(Interval{Bound{v, false, false}, above}), nil
+    case OP_LT_EQUALS:
+        return newRange(Interval{below, Bound{v, true, false}}), nil
+    case OP_LT:
+        return newRange(Interval{below, Bound{v, false, false}}), nil
+    case OP_TILDE:
+        next := SemVersion{Major: v.Major + 1, PreRelease: lowestPreRelease}
+        if TildeMode == TILDE_SAME_MINOR {
+            next = SemVersion{Major: v.Major, Minor: v.Minor + 1, PreRelease: lowestPreRelease}
+        }
+        return newRange(Interval{Bound{v, true, false}, Bound{next, false, false}}), nil
+    case OP_CARET:
+        return newRange(caretInterval(v, exp.Parts)), nil
+    case OP_RANGE:
+        upper := Bound{exp.Upper, exp.UpperOperator == OP_LT_EQUALS, false}
+        return newRange(Interval{Bound{v, true, false}, upper}), nil
+    case OP_AT:
+        return Range{}, fmt.Errorf("%w: %s", ErrNotARange, exp)
+    }
+
+    // we cannot tell which versions a MatchFunc accepts
+    if exp.Operator.matchFunc() != nil {
+        return Range{}, fmt.Errorf("%w: %s", ErrNotARange, exp)
+    }
+
+    return Range{}, ErrUnknownOperator
+}
+
+// caretInterval works out the interval that '^v' covers, when 'parts'
+// of X, Y and Z were given (0 means all three)
+func caretInterval(v SemVersion, parts int) Interval {
+    lower := Bound{v, true, false}
+    switch {
+    case v.Major > 0 || parts == 1:
+        return Interval{lower, Bound{SemVersion{Major: v.Major + 1, PreRelease: lowestPreRelease}, false, false}}
+    case v.Minor > 0 || parts == 2:
+        return Interval{lower, Bound{SemVersion{Minor: v.Minor + 1, PreRelease: lowestPreRelease}, false, false}}
+    }
+
+    // '^0.0.z' only matches '0.0.z'
+    return Interval{lower, Bound{v, true, false}}
+}
+
+// NewConstraintRange works out which versions a Constraint matches
+//
+// returns the first error that NewRange() returns for any of the
+// Constraint's clauses
+func NewConstraintRange(c Constraint) (Range, error) {
+    var retval Range
+    for _, clauses := range c.Alternatives {
+        alternative := newRange(Interval{Bound{Unbounded: true}, Bound{Unbounded: true}})
+        for _, clause := range clauses {
+            r, err := NewRange(clause)
+            if err != nil {
+                return Range{}, err
+            }
+            alternative = alternative.Intersect(r)
+        }
+        retval = retval.Union(alternative)
+    }
+
+    return retval, nil
+}
+
+// newRange builds a Range from a list of intervals, sorting and merging
+// them as necessary
+func newRange(intervals ...Interval) Range {
+    var kept []Interval
+    for _, interval := range intervals {
+        if !interval.IsEmpty() {
+            kept = append(kept, interval)
+        }
+    }
+    sort.SliceStable(kept, func(i, j int) bool {
+        return cmpLower(kept[i].Lower, kept[j].Lower) < 0
+    })
+
+    var retval Range
+    for _, interval := range kept {
+        last := len(retval.Intervals) - 1
+        if last >= 0 && touches(retval.Intervals[last].Upper, interval.Lower) {
+            if cmpUpper(interval.Upper, retval.Intervals[last].Upper) > 0 {
+                retval.Intervals[last].Upper = interval.Upper
+            }
+            continue
+        }
+        retval.Intervals = append(retval.Intervals, interval)
+    }
+
+    return retval
+}
+
+// cmpLower compares two lower bounds
+//
+// an inclusive bound starts before an exclusive bound on the same version
+func cmpLower(a Bound, b Bound) int {
+    switch {
+    case a.Unbounded && b.Unbounded:
+        return 0
+    case a.Unbounded:
+        return -1
+    case b.Unbounded:
+        return 1
+    }
+
+    if result := Cmp(a.Version, b.Version); result != 0 {
+        return result
+    }
+    if a.Inclusive == b.Inclusive {
+        return 0
+    }
+    if a.Inclusive {
+        return -1
+    }
+
+    return 1
+}
+
+// cmpUpper compares two upper bounds
+//
+// an inclusive bound ends after an exclusive bound on the same version
+func cmpUpper(a Bound, b Bound) int {
+    switch {
+    case a.Unbounded && b.Unbounded:
+        return 0
+    case a.Unbounded:
+        return 1
+    case b.Unbounded:
+        return -1
+    }
+
+    if result := Cmp(a.Version, b.Version); result != 0 {
+        return result
+    }
+    if a.Inclusive == b.Inclusive {
+        return 0
+    }
+    if a.Inclusive {
+        return 1
+    }
+
+    return -1
+}
+
+// touches returns true if an interval that ends at 'upper' overlaps with,
+// or runs straight into, an interval that starts at 'lower'
+func touches(upper Bound, lower Bound) bool {
+    if upper.Unbounded || lower.Unbounded {
+        return true
+    }
+
+    result := Cmp(upper.Version, lower.Version)
+    if result != 0 {
+        return result > 0
+    }
+
+    return upper.Inclusive || lower.Inclusive
+}
+
+// IsEmpty returns true if the interval does not contain any versions
+func (i Interval) IsEmpty() bool {
+    if i.Lower.Unbounded || i.Upper.Unbounded {
+        return false
+    }
+
+    result := Cmp(i.Lower.Version, i.Upper.Version)
+    if result != 0 {
+        return result > 0
+    }
+
+    return !i.Lower.Inclusive || !i.Upper.Inclusive
+}
+
+// Contains returns true if 'v' is in the interval
+func (i Interval) Contains(v SemVersion) bool {
+    point := Bound{v, true, false}
+    return cmpLower(i.Lower, point) <= 0 && cmpUpper(point, i.Upper) <= 0
+}
+
+// IsEmpty returns true if the range does not contain any versions
+func (r Range) IsEmpty() bool {
+    return len(r.Intervals) == 0
+}
+
+// Contains returns true if 'v' is in the range
+func (r Range) Contains(v SemVersion) bool {
+    for _, interval := range r.Intervals {
+        if interval.Contains(v) {
+            return true
+        }
+    }
+
+    return false
+}
+
+// Intersect returns the versions that are in both ranges
+func (r Range) Intersect(other Range) Range {
+    var intervals []Interval
+    for _, a := range r.Intervals {
+        for _, b := range other.Intervals {
+            lower, upper := a.Lower, a.Upper
+            if cmpLower(b.Lower, lower) > 0 {
+                lower = b.Lower
+            }
+            if cmpUpper(b.Upper, upper) < 0 {
+                upper = b.Upper
+            }
+            intervals = append(intervals, Interval{lower, upper})
+        }
+    }
+
+    return newRange(intervals...)
+}
+
+// Union returns the versions that are in either range
+func (r Range) Union(other Range) Range {
+    intervals := make([]Interval, 0, len(r.Intervals)+len(other.Intervals))
+    intervals = append(intervals, r.Intervals...)
+    intervals = append(intervals, other.Intervals...)
+
+    return newRange(intervals...)
+}
+
+// Complement returns the versions that are not in the range
+func (r Range) Complement() Range {
+    var intervals []Interval
+    lower := Bound{Unbounded: true}
+    for _, interval := range r.Intervals {
+        if !interval.Lower.Unbounded {
+            upper := Bound{interval.Lower.Version, !interval.Lower.Inclusive, false}
+            intervals = append(intervals, Interval{lower, upper})
+        }
+        if interval.Upper.Unbounded {
+            return newRange(intervals...)
+        }
+        lower = Bound{interval.Upper.Version, !interval.Upper.Inclusive, false}
+    }
+    intervals = append(intervals, Interval{lower, Bound{Unbounded: true}})
+
+    return newRange(intervals...)
+}
+
+// Constraint turns the range back into a Constraint, with one alternative
+// for each interval
+//
+// an empty range becomes a Constraint with no alternatives, which does not
+// match anything
+func (r Range) Constraint() Constraint {
+    var retval Constraint
+    for _, interval := range r.Intervals {
+        retval.Alternatives = append(retval.Alternatives, interval.expressions())
+    }
+
+    return retval
+}
+
+// String returns the range in the syntax that ParseConstraint() accepts,
+// e.g. '>=1.2.0, <1.4.0 || >=2.0.0'
+//
+// an empty range has no such syntax, and is returned as an empty string
+func (r Range) String() string {
+    return r.Constraint().String()
+}
+
+// expressions turns the interval into the expressions that describe it
+func (i Interval) expressions() []VersionExpression {
+    lower, upper := i.Lower, i.Upper
+
+    switch {
+    case lower.Unbounded && upper.Unbounded:
+        // '*'
+        return []VersionExpression{{Operator: OP_GT_EQUALS}}
+    case !lower.Unbounded && !upper.Unbounded && lower.Inclusive && upper.Inclusive && Cmp(lower.Version, upper.Version) == 0:
+        return []VersionExpression{{Operator: OP_EQUALS, Version: lower.Version}}
+    }
+
+    var retval []VersionExpression
+    if !lower.Unbounded {
+        op := OP_GT
+        if lower.Inclusive {
+            op = OP_GT_EQUALS
+        }
+        retval = append(retval, VersionExpression{Operator: op, Version: lower.Version})
+    }
+    if !upper.Unbounded {
+        op := OP_LT
+        if upper.Inclusive {
+            op = OP_LT_EQUALS
+        }
+        retval = append(retval, VersionExpression{Operator: op, Version: upper.Version})
+    }
+
+    return retval
+}
+
+// String returns the constraint in the syntax that ParseConstraint()
+// accepts
+//
+// ParseConstraint(c.String()) gives you back an equivalent Constraint
+func (c Constraint) String() string {
+    alternatives := make([]string, len(c.Alternatives))
+    for i, clauses := range c.Alternatives {
+        expressions := make([]string, len(clauses))
+        for j, clause := range clauses {
+            expressions[j] = clause.String()
+        }
+        alternatives[i] = strings.Join(expressions, ", ")
+    }
+
+    return strings.Join(alternatives, " || ")
+}
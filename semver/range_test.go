@@ -0,0 +1,307 @@
+package semver
+
+import (
+    "errors"
+    "testing"
+)
+
+// mustRange turns a constraint string into a Range
+func mustRange(t *testing.T, raw string) Range {
+    t.Helper()
+
+    c, err := ParseConstraint(raw)
+    if err != nil {
+        t.Fatal(err)
+    }
+    r, err := NewConstraintRange(c)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    return r
+}
+
+// ========================================================================
+//
+// Tests for NewRange() and NewConstraintRange()
+//
+// ------------------------------------------------------------------------
+
+func TestCanTurnExpressionsIntoRanges(t *testing.T) {
+    // our list of constraints, and how they should be rendered
+    var toConvert = []struct {
+        raw      string
+        expected string
+    }{
+        {"=1.2.3", "=1.2.3"},
+        {">=1.2", ">=1.2.0"},
+        {">1.2", ">1.2.0"},
+        {"<=1.2", "<=1.2.0"},
+        {"<1.2", "<1.2.0"},
+        {"!=1.2.3", "<1.2.3 || >1.2.3"},
+        {"~1.4", ">=1.4.0, <2.0.0-0"},
+        {"^1.4.2", ">=1.4.2, <2.0.0-0"},
+        {"^0.4.2", ">=0.4.2, <0.5.0-0"},
+        {"^0.0.2", "=0.0.2"},
+        {"^1.2", ">=1.2.0, <2.0.0-0"},
+        {"^0.0", ">=0.0.0, <0.1.0-0"},
+        {"^0", ">=0.0.0, <1.0.0-0"},
+        {"1.2.x", ">=1.2.0, <1.3.0-0"},
+        {"1.2 - 2.3.4", ">=1.2.0, <=2.3.4"},
+        {">=1.2, <1.1", ""},
+    }
+
+    for _, set := range toConvert {
+        // perform the test
+        actual := mustRange(t, set.raw)
+
+        // did we get back what we expected?
+        if actual.String() != set.expected {
+            t.Errorf("%q: expected %q, received %q", set.raw, set.expected, actual.String())
+            return
+        }
+    }
+}
+
+func TestNewRangeHonoursTildeMode(t *testing.T) {
+    TildeMode = TILDE_SAME_MINOR
+    defer func() { TildeMode = TILDE_SAME_MAJOR }()
+
+    // perform the test
+    actual := mustRange(t, "~1.4")
+
+    // did we get back what we expected?
+    if actual.String() != ">=1.4.0, <1.5.0-0" {
+        t.Errorf("Expected %q, received %q", ">=1.4.0, <1.5.0-0", actual.String())
+        return
+    }
+}
+
+func TestNewRangeRejectsReferences(t *testing.T) {
+    exp, _ := ParseExpression("@main")
+
+    // perform the test
+    _, err := NewRange(exp)
+
+    // was an error returned?
+    if !errors.Is(err, ErrNotARange) {
+        t.Errorf("Expected ErrNotARange, received %v", err)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for Intersect(), Union() and Complement()
+//
+// ------------------------------------------------------------------------
+
+func TestCanIntersectRanges(t *testing.T) {
+    // our list of ranges to intersect, and what we expect
+    var toIntersect = []struct {
+        lhs      string
+        rhs      string
+        expected string
+    }{
+        {">=1.2", "~1.4", ">=1.4.0, <2.0.0-0"},
+        {">=1.2", "<1.5", ">=1.2.0, <1.5.0"},
+        {">=1.2, <=1.9", ">1.5 || =1.0.0", ">1.5.0, <=1.9.0"},
+        {"~1.4 || ~3.0", "~3.0 || ~4.0", ">=3.0.0, <4.0.0-0"},
+        {"<=1.2", ">=1.2", "=1.2.0"},
+        {"<1.2", ">=1.2", ""},
+        {"!=1.5.0", "1.x", ">=1.0.0, <1.5.0 || >1.5.0, <2.0.0-0"},
+    }
+
+    for _, set := range toIntersect {
+        // perform the test
+        actual := mustRange(t, set.lhs).Intersect(mustRange(t, set.rhs))
+
+        // did we get back what we expected?
+        if actual.String() != set.expected {
+            t.Errorf("%q AND %q: expected %q, received %q", set.lhs, set.rhs, set.expected, actual.String())
+            return
+        }
+    }
+}
+
+func TestCanUnionRanges(t *testing.T) {
+    // our list of ranges to combine, and what we expect
+    var toUnion = []struct {
+        lhs      string
+        rhs      string
+        expected string
+    }{
+        {"~1.4", "~2.0", ">=1.4.0, <2.0.0-0 || >=2.0.0, <3.0.0-0"},
+        {"<1.2", ">=1.2, <1.5", "<1.5.0"},
+        {"<1.2", ">1.2", "<1.2.0 || >1.2.0"},
+        {"<1.2", ">1.2 || =1.2.0", ">=0.0.0"},
+        {">=1.0, <=1.4", ">=1.2, <=1.8", ">=1.0.0, <=1.8.0"},
+    }
+
+    for _, set := range toUnion {
+        // perform the test
+        actual := mustRange(t, set.lhs).Union(mustRange(t, set.rhs))
+
+        // did we get back what we expected?
+        if actual.String() != set.expected {
+            t.Errorf("%q OR %q: expected %q, received %q", set.lhs, set.rhs, set.expected, actual.String())
+            return
+        }
+    }
+}
+
+func TestCanComplementRanges(t *testing.T) {
+    // our list of ranges to invert, and what we expect
+    var toComplement = []struct {
+        raw      string
+        expected string
+    }{
+        {">=1.2", "<1.2.0"},
+        {">1.2, <=1.5", "<=1.2.0 || >1.5.0"},
+        {"!=1.2.3", "=1.2.3"},
+        {"~1.4 || ~3.0", "<1.4.0 || >=2.0.0-0, <3.0.0 || >=4.0.0-0"},
+    }
+
+    for _, set := range toComplement {
+        // perform the test
+        actual := mustRange(t, set.raw).Complement()
+
+        // did we get back what we expected?
+        if actual.String() != set.expected {
+            t.Errorf("%q: expected %q, received %q", set.raw, set.expected, actual.String())
+            return
+        }
+
+        // inverting it twice should give us back what we started with
+        if actual.Complement().String() != mustRange(t, set.raw).String() {
+            t.Errorf("%q: expected double complement to be %q, received %q", set.raw, mustRange(t, set.raw), actual.Complement())
+            return
+        }
+    }
+
+    // the complement of nothing is everything
+    everything := Range{}.Complement()
+    if everything.IsEmpty() || !everything.Contains(SemVersion{Major: 99}) {
+        t.Errorf("Expected the complement of an empty range to contain everything, received %v", everything.Intervals)
+        return
+    }
+    if !everything.Complement().IsEmpty() {
+        t.Errorf("Expected the complement of everything to be empty, received %v", everything.Complement().Intervals)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for Contains() and IsEmpty()
+//
+// ------------------------------------------------------------------------
+
+func TestRangeContainsMatchingVersions(t *testing.T) {
+    r := mustRange(t, ">=1.2, <1.5 || =2.0.0 || >3.0")
+
+    // our list of versions, and whether we expect them to be in the range
+    var toCheck = []struct {
+        version  string
+        expected bool
+    }{
+        {"1.1.9", false},
+        {"1.2.0", true},
+        {"1.4.99", true},
+        {"1.5.0", false},
+        {"2.0.0", true},
+        {"2.0.1", false},
+        {"3.0.0", false},
+        {"3.0.1", true},
+    }
+
+    for _, set := range toCheck {
+        v, _ := ParseVersion(set.version)
+
+        // perform the test
+        actual := r.Contains(v)
+
+        // did we get back what we expected?
+        if actual != set.expected {
+            t.Errorf("%s: expected %v, received %v", set.version, set.expected, actual)
+            return
+        }
+    }
+}
+
+func TestRangeIsEmpty(t *testing.T) {
+    // our list of ranges, and whether we expect them to be empty
+    var toCheck = []struct {
+        raw      string
+        expected bool
+    }{
+        {">=1.2, <1.2", true},
+        {">1.2, <=1.2", true},
+        {">=1.3, <=1.2", true},
+        {">=1.2, <=1.2", false},
+        {"=1.2.0, !=1.2.0", true},
+        {"=1.2.0, !=1.2.0 || ~2.0", false},
+    }
+
+    for _, set := range toCheck {
+        // perform the test
+        actual := mustRange(t, set.raw).IsEmpty()
+
+        // did we get back what we expected?
+        if actual != set.expected {
+            t.Errorf("%q: expected %v, received %v", set.raw, set.expected, actual)
+            return
+        }
+    }
+}
+
+// ========================================================================
+//
+// Tests for Range.String() and Constraint.String()
+//
+// ------------------------------------------------------------------------
+
+func TestRangeStringCanBeParsedAgain(t *testing.T) {
+    // our list of constraints to render and parse again
+    var toRender = []string{
+        ">=1.2, ~1.4",
+        "<1.2 || >1.5, !=1.7.0",
+        "^0.4.2 || 1.x || =3.0.0-rc.1",
+        "~1.4 || ~3.0, !=3.1.0",
+    }
+
+    for _, raw := range toRender {
+        expected := mustRange(t, raw)
+
+        // perform the test
+        c, err := ParseConstraint(expected.String())
+
+        // was an error returned?
+        if err != nil {
+            t.Errorf("%q: %v", expected.String(), err)
+            return
+        }
+
+        // did we get back what we expected?
+        actual, _ := NewConstraintRange(c)
+        if actual.String() != expected.String() {
+            t.Errorf("%q: expected %q, received %q", raw, expected, actual)
+            return
+        }
+    }
+}
+
+func TestConstraintStringCanBeParsedAgain(t *testing.T) {
+    // what result do we expect?
+    expected := ">=1.2.0, <=1.9.0 || ~2.0.0 || 3.x"
+
+    // perform the test
+    c, _ := ParseConstraint(">=1.2,<=1.9 || ~2.0||3.x")
+    actual := c.String()
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %q, received %q", expected, actual)
+        return
+    }
+}
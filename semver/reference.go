@@ -0,0 +1,217 @@
+package semver
+
+import (
+    "fmt"
+    "strings"
+)
+
+// Reference holds a non-version string that points at a specific piece
+// of source code, such as a branch name, a tag or a commit ID
+//
+// create one by calling:
+//
+//     ref, err = semver.ParseReference("<branch|tag|commit_id>")
+//
+// or by parsing an expression that uses the '@' operator:
+//
+//     exp, err = semver.ParseExpression("@<branch|tag|commit_id>")
+type Reference struct {
+    Kind int    // what kind of reference is this?
+    Name string // the branch name, tag or commit ID
+}
+
+// value of Reference.Kind when the reference is a branch name
+const REF_BRANCH = 0
+
+// value of Reference.Kind when the reference is a tag
+const REF_TAG = 1
+
+// value of Reference.Kind when the reference is a commit ID (a SHA-1 or
+// SHA-256 hash, which may be abbreviated)
+const REF_COMMIT = 2
+
+// the shortest and longest commit IDs that we recognise
+//
+// 7 is the length that git abbreviates commit IDs to by default; anything
+// shorter is too likely to be a branch name that happens to be valid hex
+const minCommitIDLength = 7
+const maxCommitIDLength = 64
+
+// prefixes that force a reference to be treated as a branch or as a tag
+var refPrefixes = []struct {
+    prefix string
+    kind   int
+}{
+    {"refs/heads/", REF_BRANCH},
+    {"refs/tags/", REF_TAG},
+    {"heads/", REF_BRANCH},
+    {"tags/", REF_TAG},
+}
+
+// errors returned when a reference does not match an expression
+var (
+    ErrDifferentReferenceKinds = fmt.Errorf("references are different kinds")
+    ErrDifferentReferences     = fmt.Errorf("references are different")
+    ErrNotAReference           = fmt.Errorf("expression requires a branch, tag or commit ID")
+)
+
+// what a ParseError can tell you we were expecting in a reference
+const (
+    expectReference     = "branch, tag or commit ID"
+    expectReferenceChar = "branch, tag or commit ID character"
+)
+
+// ParseReference takes a branch name, tag or commit ID and turns it into
+// a Reference struct.
+//
+// Takes any of these strings:
+//
+//     <commit_id>
+//     <branch>
+//     heads/<branch>
+//     refs/heads/<branch>
+//     tags/<tag>
+//     refs/tags/<tag>
+//
+// A string of between 7 and 64 hexadecimal digits is treated as a
+// (possibly abbreviated) commit ID. Anything else without a prefix is
+// treated as a branch name. Use the 'heads/' prefix for a branch whose
+// name looks like a commit ID.
+//
+// Names must follow git's rules for reference names; you get back a
+// *ParseError if they don't.
+func ParseReference(ref string) (Reference, error) {
+    return parseReferenceWithOffset(ref, 0)
+}
+
+func parseReferenceWithOffset(raw string, offset int) (Reference, error) {
+    // has the caller told us what kind of reference this is?
+    kind := -1
+    pos := offset
+    for _, p := range refPrefixes {
+        if strings.HasPrefix(raw[offset:], p.prefix) {
+            kind = p.kind
+            pos = offset + len(p.prefix)
+            break
+        }
+    }
+
+    // is the name itself legal?
+    failedAt := scanReferenceName(raw, pos)
+    if failedAt >= 0 {
+        if failedAt == pos && failedAt == len(raw) {
+            return Reference{}, newParseError(raw, failedAt, expectReference)
+        }
+        return Reference{}, newParseError(raw, failedAt, expectReferenceChar)
+    }
+
+    name := raw[pos:]
+    if kind < 0 {
+        kind = REF_BRANCH
+        if isCommitID(name) {
+            kind = REF_COMMIT
+        }
+    }
+
+    return Reference{kind, name}, nil
+}
+
+// scanReferenceName checks the name that starts at raw[pos] against
+// git's rules for reference names (see git-check-ref-format(1))
+//
+// returns -1 if the name is legal, or the offset of the first character
+// that breaks the rules
+func scanReferenceName(raw string, pos int) int {
+    if pos >= len(raw) || raw[pos] == '/' || raw[pos] == '.' || raw[pos] == '-' || raw[pos:] == "@" {
+        return pos
+    }
+
+    for i := pos; i < len(raw); i++ {
+        c := raw[i]
+        if c <= ' ' || c == 0x7f || strings.IndexByte("~^:?*[\\", c) >= 0 {
+            return i
+        }
+
+        // some pairs of characters are not allowed either
+        if i > pos {
+            switch raw[i-1 : i+1] {
+            case "..", "//", "@{", "/.":
+                return i
+            }
+        }
+    }
+
+    // and neither are some endings
+    last := len(raw) - 1
+    if raw[last] == '/' || raw[last] == '.' {
+        return last
+    }
+    if strings.HasSuffix(raw[pos:], ".lock") {
+        return len(raw) - len(".lock")
+    }
+
+    return -1
+}
+
+// isCommitID returns true if 'name' looks like a full or abbreviated
+// commit ID
+func isCommitID(name string) bool {
+    if len(name) < minCommitIDLength || len(name) > maxCommitIDLength {
+        return false
+    }
+
+    for i := 0; i < len(name); i++ {
+        if !isHexChar(name[i]) {
+            return false
+        }
+    }
+
+    return true
+}
+
+// isHexChar returns true if 'c' is a hexadecimal digit
+func isHexChar(c byte) bool {
+    return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// MatchesReference checks to see if 'ref' matches the '@' expression
+// that we have already parsed.
+//
+// Branches and tags must have exactly the same name. Commit IDs match if
+// one is an abbreviation of the other (ie one starts with the other),
+// ignoring case.
+//
+// returns 'true' if the reference matches the expression in 'lhs'
+// returns 'false' plus one of the Err* values if the reference does not
+// match
+func (lhs *VersionExpression) MatchesReference(rhs *Reference) (bool, error) {
+    if lhs.Operator != OP_AT {
+        return false, ErrIncomparable
+    }
+
+    return lhs.Reference.matches(rhs)
+}
+
+func (lhs *Reference) matches(rhs *Reference) (bool, error) {
+    if lhs.Kind != rhs.Kind {
+        return false, ErrDifferentReferenceKinds
+    }
+
+    if lhs.Kind != REF_COMMIT {
+        if lhs.Name != rhs.Name {
+            return false, ErrDifferentReferences
+        }
+        return true, nil
+    }
+
+    // is one commit ID an abbreviation of the other?
+    shorter, longer := lhs.Name, rhs.Name
+    if len(shorter) > len(longer) {
+        shorter, longer = longer, shorter
+    }
+    if !strings.EqualFold(shorter, longer[:len(shorter)]) {
+        return false, ErrDifferentReferences
+    }
+
+    return true, nil
+}
@@ -0,0 +1,331 @@
+package semver
+
+import (
+    "errors"
+    "testing"
+)
+
+// ========================================================================
+//
+// Tests for ParseReference()
+//
+// ------------------------------------------------------------------------
+
+func TestCanParseReferences(t *testing.T) {
+    // our list of references to parse, and what we expect to get back
+    var toParse = []struct {
+        raw      string
+        expected Reference
+    }{
+        {"main", Reference{REF_BRANCH, "main"}},
+        {"feature/new-parser", Reference{REF_BRANCH, "feature/new-parser"}},
+        {"heads/main", Reference{REF_BRANCH, "main"}},
+        {"refs/heads/main", Reference{REF_BRANCH, "main"}},
+        {"heads/deadbeef", Reference{REF_BRANCH, "deadbeef"}},
+        {"tags/v1.2.3", Reference{REF_TAG, "v1.2.3"}},
+        {"refs/tags/v1.2.3", Reference{REF_TAG, "v1.2.3"}},
+        {"v1.2.3", Reference{REF_BRANCH, "v1.2.3"}},
+        {"cafe", Reference{REF_BRANCH, "cafe"}},
+        {"64f5893", Reference{REF_COMMIT, "64f5893"}},
+        {"64F5893ABC", Reference{REF_COMMIT, "64F5893ABC"}},
+        {"64f5893e1d0b2c7a8f9e0d1c2b3a4f5e6d7c8b9a", Reference{REF_COMMIT, "64f5893e1d0b2c7a8f9e0d1c2b3a4f5e6d7c8b9a"}},
+    }
+
+    for _, set := range toParse {
+        // perform the test
+        actual, err := ParseReference(set.raw)
+
+        // was an error returned?
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != set.expected {
+            t.Errorf("%q: expected %v, received %v", set.raw, set.expected, actual)
+            return
+        }
+    }
+}
+
+func TestParseReferenceReportsOffsetOfError(t *testing.T) {
+    // our list of references to parse, and where we expect parsing to
+    // fail
+    var toParse = []ParseError{
+        ParseError{"", 0, expectReference, nil},
+        ParseError{"heads/", 6, expectReference, nil},
+        ParseError{"-main", 0, expectReferenceChar, nil},
+        ParseError{"/main", 0, expectReferenceChar, nil},
+        ParseError{"main branch", 4, expectReferenceChar, nil},
+        ParseError{"main~1", 4, expectReferenceChar, nil},
+        ParseError{"main^", 4, expectReferenceChar, nil},
+        ParseError{"a..b", 2, expectReferenceChar, nil},
+        ParseError{"main@{1}", 5, expectReferenceChar, nil},
+        ParseError{"feature//x", 8, expectReferenceChar, nil},
+        ParseError{"feature/.x", 8, expectReferenceChar, nil},
+        ParseError{"main/", 4, expectReferenceChar, nil},
+        ParseError{"main.", 4, expectReferenceChar, nil},
+        ParseError{"main.lock", 4, expectReferenceChar, nil},
+        ParseError{"@", 0, expectReferenceChar, nil},
+    }
+
+    for _, expected := range toParse {
+        // perform the test
+        _, err := ParseReference(expected.Input)
+
+        // did we get back what we expected?
+        var actual *ParseError
+        if !errors.As(err, &actual) {
+            t.Errorf("Expected a ParseError for %q, received %v", expected.Input, err)
+            return
+        }
+        if *actual != expected {
+            t.Errorf("Expected %+v, received %+v", expected, *actual)
+            return
+        }
+    }
+}
+
+// ========================================================================
+//
+// Tests for Parse() with @
+//
+// ------------------------------------------------------------------------
+
+func TestCanParseAtOperatorWithBranch(t *testing.T) {
+    // what result do we expect?
+    expected := VersionExpression{
+        Operator: OP_AT,
+        Reference: Reference{
+            Kind: REF_BRANCH,
+            Name: "main",
+        },
+    }
+
+    // perform the test
+    actual, err := ParseExpression("@main")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %v, received %v", expected, actual)
+        return
+    }
+}
+
+func TestCanParseAtOperatorWithCommitID(t *testing.T) {
+    // what result do we expect?
+    expected := VersionExpression{
+        Operator: OP_AT,
+        Reference: Reference{
+            Kind: REF_COMMIT,
+            Name: "64f5893",
+        },
+    }
+
+    // perform the test
+    actual, err := ParseExpression("@64f5893")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %v, received %v", expected, actual)
+        return
+    }
+}
+
+func TestParseExpressionReportsOffsetIntoReference(t *testing.T) {
+    // what result do we expect?
+    expected := ParseError{"@main branch", 5, expectReferenceChar, nil}
+
+    // perform the test
+    _, err := ParseExpression(expected.Input)
+
+    // did we get back what we expected?
+    var actual *ParseError
+    if !errors.As(err, &actual) {
+        t.Errorf("Expected a ParseError, received %v", err)
+        return
+    }
+    if *actual != expected {
+        t.Errorf("Expected %+v, received %+v", expected, *actual)
+        return
+    }
+}
+
+func TestCanUseAtOperatorInConstraint(t *testing.T) {
+    // what result do we expect?
+    expected := []VersionExpression{
+        VersionExpression{Operator: OP_GT_EQUALS, Version: SemVersion{Major: 1, Minor: 2}},
+        VersionExpression{Operator: OP_AT, Reference: Reference{REF_BRANCH, "develop"}},
+    }
+
+    // perform the test
+    actual, err := ParseConstraint(">=1.2 || @develop")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if len(actual.Alternatives) != len(expected) {
+        t.Errorf("Expected %v, received %v", expected, actual.Alternatives)
+        return
+    }
+    for i := range expected {
+        if actual.Alternatives[i][0] != expected[i] {
+            t.Errorf("Expected %v, received %v", expected[i], actual.Alternatives[i][0])
+            return
+        }
+    }
+}
+
+// ========================================================================
+//
+// Compare two references using the @ operator
+//
+// ------------------------------------------------------------------------
+
+func TestCanMatchUsingAt(t *testing.T) {
+    // what result do we expect?
+    expected := true
+
+    // our list of strings to compare
+    //
+    // LHS contains the operator
+    // RHS contains only a reference to compare against
+    //
+    // all of these pairs should match
+    var toMatch = [][2]string{
+        [2]string{"@main", "main"},
+        [2]string{"@main", "refs/heads/main"},
+        [2]string{"@feature/new-parser", "heads/feature/new-parser"},
+        [2]string{"@tags/v1.2.3", "refs/tags/v1.2.3"},
+        [2]string{"@64f5893", "64f5893"},
+        [2]string{"@64f5893", "64f5893e1d0b2c7a8f9e0d1c2b3a4f5e6d7c8b9a"},
+        [2]string{"@64f5893e1d0b2c7a8f9e0d1c2b3a4f5e6d7c8b9a", "64f5893e1d"},
+        [2]string{"@64F5893", "64f5893e1d"},
+    }
+
+    for _, matchSet := range toMatch {
+        // perform the test
+        lhs, err := ParseExpression(matchSet[0])
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual, err := lhs.Matches(matchSet[1])
+
+        // was an error returned?
+        if err != nil {
+            t.Errorf("%s %s: %v", matchSet[0], matchSet[1], err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != expected {
+            t.Errorf("Expected %v, received %v", expected, actual)
+            return
+        }
+    }
+}
+
+func TestCannotMatchUsingAt(t *testing.T) {
+    // our list of strings to compare
+    //
+    // LHS contains the operator
+    // RHS contains only a reference to compare against
+    //
+    // all of these pairs should not match
+    var toMatch = []ExpectedError{
+        ExpectedError{"@main", "master", ErrDifferentReferences},
+        ExpectedError{"@main", "tags/main", ErrDifferentReferenceKinds},
+        ExpectedError{"@tags/v1.2.3", "tags/v1.2.4", ErrDifferentReferences},
+        ExpectedError{"@64f5893", "64f5894", ErrDifferentReferences},
+        ExpectedError{"@64f5893e1d", "64f5893e1e", ErrDifferentReferences},
+        ExpectedError{"@64f5893", "heads/64f5893", ErrDifferentReferenceKinds},
+    }
+
+    for _, matchSet := range toMatch {
+        // perform the test
+        lhs, err := ParseExpression(matchSet.lhs)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual, err := lhs.Matches(matchSet.rhs)
+
+        // was an error returned?
+        if !errors.Is(err, matchSet.err) {
+            t.Errorf("%s %s: expected %v, received %v", matchSet.lhs, matchSet.rhs, matchSet.err, err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != false {
+            t.Errorf("Expected %v, received %v", false, actual)
+            return
+        }
+    }
+}
+
+func TestCannotMatchVersionUsingAt(t *testing.T) {
+    // what result do we expect?
+    expected := ErrNotAReference
+
+    // perform the test
+    lhs, err := ParseExpression("@main")
+    if err != nil {
+        t.Error(err)
+        return
+    }
+    rhs := SemVersion{Major: 1, Minor: 2}
+    actual, err := lhs.MatchesVersion(&rhs)
+
+    // did we get back what we expected?
+    if err != expected {
+        t.Errorf("Expected %v, received %v", expected, err)
+        return
+    }
+    if actual != false {
+        t.Errorf("Expected %v, received %v", false, actual)
+        return
+    }
+}
+
+func TestCannotMatchReferenceUsingVersionExpression(t *testing.T) {
+    // what result do we expect?
+    expected := ErrIncomparable
+
+    // perform the test
+    lhs, err := ParseExpression(">=1.2")
+    if err != nil {
+        t.Error(err)
+        return
+    }
+    rhs := Reference{REF_BRANCH, "main"}
+    actual, err := lhs.MatchesReference(&rhs)
+
+    // did we get back what we expected?
+    if err != expected {
+        t.Errorf("Expected %v, received %v", expected, err)
+        return
+    }
+    if actual != false {
+        t.Errorf("Expected %v, received %v", false, actual)
+        return
+    }
+}
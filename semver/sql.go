@@ -0,0 +1,190 @@
+package semver
+
+import (
+    "database/sql/driver"
+    "fmt"
+    "strings"
+)
+
+// returned when we are asked to Scan() a NULL into a SemVersion or a
+// VersionExpression
+//
+// use a *SemVersion or *VersionExpression if your column can be NULL
+var ErrNullValue = fmt.Errorf("cannot scan NULL")
+
+// Scan reads a version from a database column, so that you can pass a
+// *SemVersion to sql.Rows.Scan()
+//
+// the column can hold either a version string, or a key made by
+// SortKey(); returns a *ParseError if it cannot be parsed
+func (v *SemVersion) Scan(src interface{}) error {
+    text, err := scannedText(src, "SemVersion")
+    if err != nil {
+        return err
+    }
+
+    // is this a sort key?
+    if i := strings.LastIndexByte(text, sortKeyEnd); i >= 0 {
+        text = text[i+1:]
+    }
+
+    return v.UnmarshalText([]byte(text))
+}
+
+// Value returns the version in its canonical form (see String()), so that
+// a SemVersion can be passed straight to sql.DB.Exec() and friends
+func (v SemVersion) Value() (driver.Value, error) {
+    return v.String(), nil
+}
+
+// Scan reads a version expression from a database column, so that you can
+// pass a *VersionExpression to sql.Rows.Scan()
+//
+// returns a *ParseError if the expression cannot be parsed
+func (exp *VersionExpression) Scan(src interface{}) error {
+    text, err := scannedText(src, "VersionExpression")
+    if err != nil {
+        return err
+    }
+
+    return exp.UnmarshalText([]byte(text))
+}
+
+// Value returns the expression in its canonical form (see String()), so
+// that a VersionExpression can be passed straight to sql.DB.Exec() and
+// friends
+//
+// returns the same errors as MarshalText()
+func (exp VersionExpression) Value() (driver.Value, error) {
+    text, err := exp.MarshalText()
+    if err != nil {
+        return nil, err
+    }
+
+    return string(text), nil
+}
+
+// scannedText turns the value that the database driver gave us into a
+// string
+func scannedText(src interface{}, name string) (string, error) {
+    switch text := src.(type) {
+    case string:
+        return text, nil
+    case []byte:
+        return string(text), nil
+    case nil:
+        return "", fmt.Errorf("%w into a %s", ErrNullValue, name)
+    }
+
+    return "", fmt.Errorf("cannot scan %T into a %s", src, name)
+}
+
+// SortableVersion is a SemVersion that is stored in the database as a
+// sort key (see SemVersion.SortKey()), so that ORDER BY puts the versions
+// in the same order that Cmp() does
+//
+// the column must be compared byte by byte: use BYTEA or a "C" collation
+// in Postgres, and BLOB or the default BINARY collation in SQLite
+type SortableVersion struct {
+    SemVersion
+}
+
+// Value returns the version's sort key
+func (v SortableVersion) Value() (driver.Value, error) {
+    return v.SortKey(), nil
+}
+
+// the byte that ends each variable-length part of a sort key
+//
+// it sorts before every byte that can appear in a version string
+const sortKeyEnd = '!'
+
+// the byte that separates pre-release identifiers in a sort key
+//
+// it sorts after sortKeyEnd, so that '1.0.0-alpha' comes before
+// '1.0.0-alpha.1', and before every byte that can appear in an identifier
+const sortKeyIdentifierSep = '#'
+
+// SortKey returns a string that sorts, byte by byte, in the same order
+// that Cmp() puts versions in
+//
+// the key ends with the version's canonical form, and can be read back
+// using SemVersion.Scan(). It depends on StabilityMode and StabilityOrder,
+// so you need to rebuild any keys that you have stored if you change them.
+func (v SemVersion) SortKey() string {
+    buf := make([]byte, 0, 128)
+    buf = appendSortKeyInt(buf, v.Major)
+    buf = appendSortKeyInt(buf, v.Minor)
+    buf = appendSortKeyInt(buf, v.PatchLevel)
+
+    // Compare() reads the stability level from SemVer 2.0.0
+    // pre-releases too, when it puts stability levels in order
+    stability, release, preRelease := v.Stability, v.Release, v.PreRelease
+    if StabilityMode == STABILITY_ORDERED {
+        stability, release, preRelease = orderedStability(&v)
+    }
+
+    // unknown stability levels come first, in alphabetical order, then
+    // the ones in StabilityOrder, and finally stable releases
+    rank, ok := stabilityRank(stability)
+    switch {
+    case stability == "":
+        buf = append(buf, '2')
+    case !ok:
+        buf = append(buf, '0')
+        buf = append(buf, strings.ToLower(stability)...)
+    default:
+        buf = append(buf, '1')
+
+        // flipping the sign bit puts negative ranks first
+        buf = append(buf, fmt.Sprintf("%016x", uint64(rank)^(1<<63))...)
+
+        // Cmp() puts levels with the same rank into alphabetical
+        // order, unless Compare() can compare them
+        if StabilityMode != STABILITY_ORDERED {
+            buf = append(buf, strings.ToLower(stability)...)
+        }
+    }
+    buf = append(buf, sortKeyEnd)
+
+    buf = appendSortKeyInt(buf, release)
+    buf = appendSortKeyPreRelease(buf, preRelease)
+
+    return string(v.appendTo(buf, true))
+}
+
+// appendSortKeyInt adds a zero-padded number to the end of 'buf'
+func appendSortKeyInt(buf []byte, number int) []byte {
+    return append(buf, fmt.Sprintf("%020d.", number)...)
+}
+
+// appendSortKeyPreRelease adds the pre-release identifiers to the end of
+// 'buf', in a form that sorts using the precedence rules from SemVer 2.0.0
+func appendSortKeyPreRelease(buf []byte, preRelease string) []byte {
+    // a version without any pre-release identifiers has a higher
+    // precedence than one that has some
+    if preRelease == "" {
+        return append(buf, '3', sortKeyEnd)
+    }
+
+    for preRelease != "" {
+        var id string
+        id, preRelease = nextIdentifier(preRelease)
+
+        if isNumericIdentifier(id) {
+            // the longer number is the larger, once leading zeroes
+            // are ignored
+            id = strings.TrimLeft(id, "0")
+            buf = append(buf, fmt.Sprintf("1%04d", len(id))...)
+        } else {
+            buf = append(buf, '2')
+        }
+        buf = append(buf, id...)
+
+        if preRelease != "" {
+            buf = append(buf, sortKeyIdentifierSep)
+        }
+    }
+
+    return append(buf, sortKeyEnd)
+}
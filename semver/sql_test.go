@@ -0,0 +1,277 @@
+package semver
+
+import (
+    "errors"
+    "sort"
+    "testing"
+)
+
+// ========================================================================
+//
+// Tests for Scan() and Value()
+//
+// ------------------------------------------------------------------------
+
+func TestCanStoreVersionInDatabase(t *testing.T) {
+    // what result do we expect?
+    expected := SemVersion{Major: 1, Minor: 3, Stability: "alpha", Release: 1, Build: "build.5"}
+
+    // perform the test
+    value, err := expected.Value()
+    if err != nil {
+        t.Error(err)
+        return
+    }
+    if value != "1.3.0-alpha-1+build.5" {
+        t.Errorf("Expected 1.3.0-alpha-1+build.5, received %v", value)
+        return
+    }
+
+    // drivers give us either a string or a []byte
+    for _, src := range []interface{}{value, []byte(value.(string))} {
+        var actual SemVersion
+        err = actual.Scan(src)
+
+        // was an error returned?
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != expected {
+            t.Errorf("Expected %+v, received %+v", expected, actual)
+            return
+        }
+    }
+}
+
+func TestCanStoreExpressionInDatabase(t *testing.T) {
+    // what result do we expect?
+    expected, _ := ParseExpression("1.2 - 2.3")
+
+    // perform the test
+    value, _ := expected.Value()
+    var actual VersionExpression
+    err := actual.Scan(value)
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %+v, received %+v", expected, actual)
+        return
+    }
+}
+
+func TestValueRejectsInvalidExpressions(t *testing.T) {
+    // perform the test
+    exp := VersionExpression{Operator: 42, Version: SemVersion{Major: 1}}
+    _, err := exp.Value()
+
+    // did we get back what we expected?
+    if !errors.Is(err, ErrUnknownOperator) {
+        t.Errorf("Expected ErrUnknownOperator, received %v", err)
+        return
+    }
+}
+
+func TestScanRejectsNullAndOtherTypes(t *testing.T) {
+    // perform the test
+    var version SemVersion
+    err := version.Scan(nil)
+
+    // did we get back what we expected?
+    if !errors.Is(err, ErrNullValue) {
+        t.Errorf("Expected ErrNullValue, received %v", err)
+        return
+    }
+
+    var exp VersionExpression
+    err = exp.Scan(int64(1))
+    if err == nil {
+        t.Errorf("Expected an error, received nil")
+        return
+    }
+}
+
+func TestScanReturnsParseError(t *testing.T) {
+    // what result do we expect?
+    expected := ParseError{"1.3.x.6", 4, expectPatchLevel, nil}
+
+    // perform the test
+    var version SemVersion
+    err := version.Scan("1.3.x.6")
+
+    // did we get back what we expected?
+    var actual *ParseError
+    if !errors.As(err, &actual) {
+        t.Errorf("Expected a ParseError, received %v", err)
+        return
+    }
+    if *actual != expected {
+        t.Errorf("Expected %+v, received %+v", expected, *actual)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for SortKey() and SortableVersion
+//
+// ------------------------------------------------------------------------
+
+// sortKeys puts a list of sort keys into order, byte by byte, the way
+// that the database does
+func sortKeys(versions Versions) Versions {
+    keys := make([]string, len(versions))
+    for i, version := range versions {
+        keys[i] = version.SortKey()
+    }
+    sort.Strings(keys)
+
+    retval := make(Versions, len(keys))
+    for i, key := range keys {
+        retval[i].Scan(key)
+    }
+
+    return retval
+}
+
+func TestSortKeysSortLikeCmp(t *testing.T) {
+    // what result do we expect?
+    expected, _ := ParseVersions([]string{
+        "0.9.0",
+        "1.2.9",
+        "1.3.0-FOO-1",
+        "1.3.0-dev-2",
+        "1.3.0-snapshot-1",
+        "1.3.0-alpha-1",
+        "1.3.0-alpha-10",
+        "1.3.0-rc-1",
+        "1.3.0-0",
+        "1.3.0-2",
+        "1.3.0-10",
+        "1.3.0-alpha",
+        "1.3.0-alpha.1",
+        "1.3.0-alpha.beta",
+        "1.3.0-alpha-",
+        "1.3.0",
+        "1.10.0",
+        "10.0.0",
+    })
+
+    // perform the test
+    shuffled := make(Versions, len(expected))
+    for i := range expected {
+        shuffled[i] = expected[(i*7)%len(expected)]
+    }
+    actual := sortKeys(shuffled)
+
+    // did we get back what we expected?
+    for i := range expected {
+        if actual[i] != expected[i] {
+            t.Errorf("Expected %s at position %d, received %s", expected[i], i, actual[i])
+            return
+        }
+    }
+
+    // and does Cmp() agree?
+    sorted := make(Versions, len(shuffled))
+    copy(sorted, shuffled)
+    sorted.Sort()
+    for i := range expected {
+        if Cmp(sorted[i], actual[i]) != 0 {
+            t.Errorf("Cmp() put %s at position %d, sort key put %s there", sorted[i], i, actual[i])
+            return
+        }
+    }
+}
+
+func TestSortKeysSortLikeCmpInBothStabilityModes(t *testing.T) {
+    // a mix of our pre-releases and SemVer 2.0.0 ones
+    versions, _ := ParseVersions([]string{
+        "1.0.0",
+        "1.0.0-rc.1",
+        "1.0.0-beta-1",
+        "1.0.0-alpha.1",
+        "1.0.0-alpha-2",
+        "1.0.0-alpha.beta",
+        "1.0.0-foo",
+        "1.0.0-dev.3",
+        "1.0.0-rc-2",
+        "0.9.0",
+    })
+
+    defer func() { StabilityMode = STABILITY_STRICT }()
+    for _, mode := range []int{STABILITY_STRICT, STABILITY_ORDERED} {
+        StabilityMode = mode
+
+        // perform the test
+        actual := sortKeys(versions)
+        sorted := make(Versions, len(versions))
+        copy(sorted, versions)
+        sorted.Sort()
+
+        // did we get back what we expected?
+        for i := range sorted {
+            if Cmp(sorted[i], actual[i]) != 0 {
+                t.Errorf("mode %d: Cmp() put %s at position %d, sort key put %s there", mode, sorted[i], i, actual[i])
+                return
+            }
+        }
+    }
+}
+
+func TestSortKeysFollowStabilityMode(t *testing.T) {
+    // what result do we expect?
+    expected, _ := ParseVersions([]string{
+        "1.3.0-dev-1",
+        "1.3.0-snapshot-2",
+        "1.3.0-dev-3",
+    })
+
+    // perform the test
+    StabilityMode = STABILITY_ORDERED
+    defer func() { StabilityMode = STABILITY_STRICT }()
+    actual := sortKeys(Versions{expected[2], expected[1], expected[0]})
+
+    // did we get back what we expected?
+    for i := range expected {
+        if actual[i] != expected[i] {
+            t.Errorf("Expected %s at position %d, received %s", expected[i], i, actual[i])
+            return
+        }
+    }
+}
+
+func TestCanStoreSortableVersionInDatabase(t *testing.T) {
+    // what result do we expect?
+    expected := SortableVersion{SemVersion{Major: 2, PreRelease: "rc.1"}}
+
+    // perform the test
+    value, _ := expected.Value()
+    if value != expected.SortKey() {
+        t.Errorf("Expected %s, received %v", expected.SortKey(), value)
+        return
+    }
+
+    var actual SortableVersion
+    err := actual.Scan(value)
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if actual != expected {
+        t.Errorf("Expected %+v, received %+v", expected, actual)
+        return
+    }
+}
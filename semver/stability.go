@@ -0,0 +1,362 @@
+package semver
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+// value of StabilityMode when versions with different stability levels
+// cannot be compared at all (this is the default)
+const STABILITY_STRICT = 0
+
+// value of StabilityMode when stability levels are put in the order
+// given by StabilityOrder
+const STABILITY_ORDERED = 1
+
+// StabilityMode decides whether versions with different stability levels
+// can be compared against each other
+//
+// set it to STABILITY_ORDERED if you want SemVersion.Compare() and all of
+// the operators to use StabilityOrder, so that e.g.
+//
+//     1.0.0-alpha-1 < 1.0.0-beta-1 < 1.0.0-rc-1 < 1.0.0 < 1.0.1-dev-1
+var StabilityMode = STABILITY_STRICT
+
+// StabilityOrder ranks the stability levels that we know about, from the
+// least stable to the most stable
+//
+// stable releases always rank higher than all of these. Stability levels
+// that share the same rank are treated as the same level, and stability
+// levels that are not listed here can only be compared against themselves.
+//
+// keys must be lower case; use RegisterStability() to add your own
+var StabilityOrder = map[string]int{
+    "dev":      10,
+    "snapshot": 10,
+    "alpha":    20,
+    "beta":     30,
+    "pre":      40,
+    "rc":       40,
+}
+
+// returned when a version does not match an expression because of its
+// stability level, when StabilityMode is STABILITY_ORDERED
+var (
+    ErrStabilityLevelTooLow  = fmt.Errorf("stability level is too low")
+    ErrStabilityLevelTooHigh = fmt.Errorf("stability level is too high")
+)
+
+// RegisterStability adds a stability level to StabilityOrder, or changes
+// the rank of one that is already there
+//
+// stability levels are case-insensitive. StabilityOrder is read every
+// time that versions are compared, and nothing guards it, so call this
+// from your init() function, before you start comparing versions.
+func RegisterStability(level string, rank int) {
+    StabilityOrder[strings.ToLower(level)] = rank
+}
+
+// sameStability returns true if both stability levels are the same,
+// ignoring case
+func sameStability(lhs string, rhs string) bool {
+    return strings.EqualFold(lhs, rhs)
+}
+
+// the stability level that orderedStability() gives to a pre-release
+// that does not start with one from StabilityOrder, e.g. '1.0.0-foo'
+//
+// it ranks below all of the levels in StabilityOrder. A real stability
+// level can never contain a '.', so it never clashes with one of those.
+const preReleaseLevel = "."
+
+// the rank of preReleaseLevel
+const minInt = -maxInt - 1
+
+// stabilityRank looks up where 'level' comes in StabilityOrder
+//
+// returns false if we do not know where 'level' comes
+func stabilityRank(level string) (int, bool) {
+    switch level {
+    case "":
+        return maxInt, true
+    case preReleaseLevel:
+        return minInt, true
+    }
+
+    rank, ok := StabilityOrder[level]
+    if !ok {
+        rank, ok = StabilityOrder[strings.ToLower(level)]
+    }
+
+    return rank, ok
+}
+
+// the parts of a SemVersion, in the order that compareOrdered() looks
+// at them
+const (
+    fieldNone = iota
+    fieldMajor
+    fieldMinor
+    fieldPatchLevel
+    fieldStability
+    fieldRelease
+    fieldPreRelease
+    fieldIncomparable
+)
+
+// the errors that we return when a field is different, too small, or
+// too large
+var fieldErrors = [...][3]error{
+    fieldMajor:      {ErrDifferentMajorVersions, ErrMajorVersionTooSmall, ErrMajorVersionTooLarge},
+    fieldMinor:      {ErrDifferentMinorVersions, ErrMinorVersionTooSmall, ErrMinorVersionTooLarge},
+    fieldPatchLevel: {ErrDifferentPatchLevel, ErrPatchLevelTooSmall, ErrPatchLevelTooLarge},
+    fieldStability:  {ErrDifferentStabilityLevels, ErrStabilityLevelTooLow, ErrStabilityLevelTooHigh},
+    fieldRelease:    {ErrDifferentReleaseNumbers, ErrReleaseNumberTooSmall, ErrReleaseNumberTooLarge},
+    fieldPreRelease: {ErrDifferentPreReleases, ErrPreReleaseTooSmall, ErrPreReleaseTooLarge},
+}
+
+// compareOrdered compares two versions, using StabilityOrder to compare
+// their stability levels
+//
+// returns -1 if 'rhs' is smaller than 'lhs', 1 if it is larger, and 0 if
+// they are the same, plus the field that decided the result
+//
+// returns fieldIncomparable if we do not know how to order the two
+// stability levels
+func compareOrdered(lhs *SemVersion, rhs *SemVersion) (int, int) {
+    if result := compareInts(lhs.Major, rhs.Major); result != 0 {
+        return result, fieldMajor
+    }
+    if result := compareInts(lhs.Minor, rhs.Minor); result != 0 {
+        return result, fieldMinor
+    }
+    if result := compareInts(lhs.PatchLevel, rhs.PatchLevel); result != 0 {
+        return result, fieldPatchLevel
+    }
+
+    lhsStability, lhsRelease, lhsPreRelease := orderedStability(lhs)
+    rhsStability, rhsRelease, rhsPreRelease := orderedStability(rhs)
+    result, ok := compareStabilityLevels(lhsStability, rhsStability)
+    if !ok {
+        return 0, fieldIncomparable
+    }
+    if result != 0 {
+        return result, fieldStability
+    }
+
+    if result := compareInts(lhsRelease, rhsRelease); result != 0 {
+        return result, fieldRelease
+    }
+    if result := comparePreReleases(rhsPreRelease, lhsPreRelease); result != 0 {
+        return result, fieldPreRelease
+    }
+
+    return 0, fieldNone
+}
+
+// orderedStability returns the stability level, release number and
+// pre-release that compareOrdered() uses for 'v'
+//
+// a pre-release such as 'alpha.1' is treated as if it was 'alpha-1', so
+// that it can be put in order against versions that use our format. Any
+// other pre-release gets preReleaseLevel, so that it still comes before
+// the stable release.
+func orderedStability(v *SemVersion) (string, int, string) {
+    if v.Stability != "" || v.PreRelease == "" {
+        return v.Stability, v.Release, v.PreRelease
+    }
+
+    level, release, preRelease, ok := splitPreReleaseStability(v.PreRelease)
+    if !ok {
+        return preReleaseLevel, v.Release, v.PreRelease
+    }
+    number := 0
+    if release != "" {
+        var err error
+        if number, err = strconv.Atoi(release); err != nil {
+            return preReleaseLevel, v.Release, v.PreRelease
+        }
+    }
+
+    return level, number, preRelease
+}
+
+// splitPreReleaseStability splits a pre-release such as 'alpha.1' or
+// 'rc.2.hotfix' into a stability level, its release number, and whatever
+// is left over
+//
+// the release number is empty if there isn't one
+//
+// returns false if the pre-release does not start with a stability level
+// from StabilityOrder, or if that is not followed by a release number or
+// nothing at all
+func splitPreReleaseStability(preRelease string) (string, string, string, bool) {
+    level, rest := nextIdentifier(preRelease)
+    if _, ok := StabilityOrder[strings.ToLower(level)]; !ok {
+        return "", "", "", false
+    }
+
+    release, rest := nextIdentifier(rest)
+    if release != "" && !isNumericIdentifier(release) {
+        return "", "", "", false
+    }
+
+    return level, release, rest, true
+}
+
+// compareStabilityLevels uses StabilityOrder to compare two stability
+// levels
+//
+// returns -1 if 'rhs' is less stable than 'lhs', 1 if it is more stable,
+// and 0 if they share the same rank, plus false if we do not know how to
+// order them
+func compareStabilityLevels(lhs string, rhs string) (int, bool) {
+    if sameStability(lhs, rhs) {
+        return 0, true
+    }
+
+    lhsRank, lhsOk := stabilityRank(lhs)
+    rhsRank, rhsOk := stabilityRank(rhs)
+    if !lhsOk || !rhsOk {
+        return 0, false
+    }
+
+    return compareInts(lhsRank, rhsRank), true
+}
+
+// compareInts returns -1 if 'rhs' is smaller than 'lhs', 1 if it is
+// larger, and 0 if they are the same
+func compareInts(lhs int, rhs int) int {
+    if rhs < lhs {
+        return -1
+    }
+    if rhs > lhs {
+        return 1
+    }
+
+    return 0
+}
+
+// compareWithStabilityOrder is SemVersion.Compare() for when StabilityMode
+// is STABILITY_ORDERED
+func (lhs *SemVersion) compareWithStabilityOrder(rhs *SemVersion) int {
+    result, field := compareOrdered(lhs, rhs)
+    if field == fieldIncomparable {
+        return COMP_APPLES_AND_ORANGES
+    }
+
+    switch result {
+    case -1:
+        return COMP_SMALLER
+    case 1:
+        return COMP_LARGER
+    }
+
+    return COMP_EQUAL
+}
+
+// matchesWithStabilityOrder is VersionExpression.MatchesVersion() for
+// when StabilityMode is STABILITY_ORDERED
+//
+// every operator is worked out from a single comparison of the two
+// versions
+func (lhs *VersionExpression) matchesWithStabilityOrder(rhs *SemVersion) (bool, error) {
+    // these operators do not compare the two versions themselves
+    if lhs.Operator == OP_AT || lhs.Operator == OP_RANGE || !lhs.Operator.IsValid() {
+        return lhs.matchesWithoutStabilityOrder(rhs)
+    }
+
+    result, field := compareOrdered(&lhs.Version, rhs)
+    if field == fieldIncomparable {
+        return false, ErrDifferentStabilityLevels
+    }
+
+    switch lhs.Operator {
+    case OP_EQUALS:
+        if result != 0 {
+            return false, fieldErrors[field][0]
+        }
+        return true, nil
+
+    case OP_NOT_EQUALS:
+        if result == 0 {
+            return false, ErrSameVersion
+        }
+        return true, nil
+
+    case OP_GT_EQUALS:
+        if result < 0 {
+            return false, fieldErrors[field][1]
+        }
+        return true, nil
+
+    case OP_LT_EQUALS:
+        if result > 0 {
+            return false, fieldErrors[field][2]
+        }
+        return true, nil
+
+    case OP_GT:
+        if result < 0 {
+            return false, fieldErrors[field][1]
+        }
+        if result == 0 {
+            return false, ErrSameVersion
+        }
+        return true, nil
+
+    case OP_LT:
+        if result > 0 {
+            return false, fieldErrors[field][2]
+        }
+        if result == 0 {
+            return false, ErrSameVersion
+        }
+        return true, nil
+
+    case OP_TILDE:
+        return lhs.matchesCompatibleWithOrdered(rhs, result, field)
+
+    case OP_CARET:
+        return lhs.matchesCaretOrdered(rhs, result, field)
+    }
+
+    // everything else does not depend on the stability level
+    return lhs.matchesWithoutStabilityOrder(rhs)
+}
+
+func (lhs *VersionExpression) matchesCompatibleWithOrdered(rhs *SemVersion, result int, field int) (bool, error) {
+    // npm only lets '~' match a pre-release of the same X.Y.Z
+    if TildeMode == TILDE_SAME_MINOR && !lhs.acceptsPreRelease(rhs) {
+        return false, ErrUnstableVersion
+    }
+
+    if lhs.Version.Major != rhs.Major {
+        return false, ErrDifferentMajorVersions
+    }
+    if TildeMode == TILDE_SAME_MINOR && rhs.Minor > lhs.Version.Minor {
+        return false, ErrMinorVersionTooLarge
+    }
+    if result < 0 {
+        return false, fieldErrors[field][1]
+    }
+
+    return true, nil
+}
+
+func (lhs *VersionExpression) matchesCaretOrdered(rhs *SemVersion, result int, field int) (bool, error) {
+    // like npm and Cargo, we only match a pre-release of the same X.Y.Z
+    if !lhs.acceptsPreRelease(rhs) {
+        return false, ErrUnstableVersion
+    }
+
+    if ok, err := lhs.matchesCaretFixedParts(rhs); !ok {
+        return false, err
+    }
+    if result < 0 {
+        return false, fieldErrors[field][1]
+    }
+
+    return true, nil
+}
@@ -0,0 +1,308 @@
+package semver
+
+import (
+    "errors"
+    "testing"
+)
+
+// ========================================================================
+//
+// Compare two versions using StabilityOrder
+//
+// ------------------------------------------------------------------------
+
+func TestCanCompareTwoVersionsUsingStabilityOrder(t *testing.T) {
+    // put our stability levels in order
+    StabilityMode = STABILITY_ORDERED
+    defer func() { StabilityMode = STABILITY_STRICT }()
+
+    // our list of things to compare
+    var toCompareList = []VersionExpectedResult{
+        // things that should be the same
+        VersionExpectedResult{"1.0.0-alpha-1", "1.0.0-alpha-1", COMP_EQUAL},
+        VersionExpectedResult{"1.0.0-alpha-1", "1.0.0-ALPHA-1", COMP_EQUAL},
+        VersionExpectedResult{"1.0.0-dev-1", "1.0.0-snapshot-1", COMP_EQUAL},
+        VersionExpectedResult{"1.0.0-pre-2", "1.0.0-rc-2", COMP_EQUAL},
+
+        // things that should be larger on the RHS
+        VersionExpectedResult{"1.0.0-dev-1", "1.0.0-alpha-1", COMP_LARGER},
+        VersionExpectedResult{"1.0.0-alpha-1", "1.0.0-beta-1", COMP_LARGER},
+        VersionExpectedResult{"1.0.0-alpha-9", "1.0.0-beta-1", COMP_LARGER},
+        VersionExpectedResult{"1.0.0-beta-1", "1.0.0-rc-1", COMP_LARGER},
+        VersionExpectedResult{"1.0.0-rc-1", "1.0.0", COMP_LARGER},
+        VersionExpectedResult{"1.0.0", "1.0.1-dev-1", COMP_LARGER},
+        VersionExpectedResult{"1.0.0-beta-1", "1.1.0-alpha-1", COMP_LARGER},
+        VersionExpectedResult{"1.0.0-Beta-1", "1.0.0-RC-1", COMP_LARGER},
+
+        // things that should be smaller on the RHS
+        VersionExpectedResult{"1.0.0", "1.0.0-rc-1", COMP_SMALLER},
+        VersionExpectedResult{"1.0.0-beta-1", "1.0.0-alpha-5", COMP_SMALLER},
+        VersionExpectedResult{"1.1.0-alpha-1", "1.0.0", COMP_SMALLER},
+
+        // SemVer 2.0.0 pre-releases that start with a stability level
+        VersionExpectedResult{"1.0.0-rc-1", "1.0.0-alpha.1", COMP_SMALLER},
+        VersionExpectedResult{"1.0.0-alpha.1", "1.0.0-rc-1", COMP_LARGER},
+        VersionExpectedResult{"1.0.0-alpha.2", "1.0.0-alpha-1", COMP_SMALLER},
+        VersionExpectedResult{"1.0.0-beta.1", "1.0.0-BETA-1", COMP_EQUAL},
+        VersionExpectedResult{"1.0.0-rc", "1.0.0-beta-3", COMP_SMALLER},
+        VersionExpectedResult{"1.0.0-rc.1", "1.0.0", COMP_LARGER},
+
+        // pre-releases that do not start with a stability level
+        VersionExpectedResult{"1.0.0-rc.1", "1.0.0-alpha.beta", COMP_SMALLER},
+        VersionExpectedResult{"1.0.0-alpha.beta", "1.0.0", COMP_LARGER},
+        VersionExpectedResult{"1.0.0-dev-1", "1.0.0-foo", COMP_SMALLER},
+        VersionExpectedResult{"1.0.0-foo", "1.0.0-foo.1", COMP_LARGER},
+        VersionExpectedResult{"1.0.0-foo", "0.9.0", COMP_SMALLER},
+
+        // stability levels that we know nothing about
+        VersionExpectedResult{"1.0.0-alpha-1", "1.0.0-nightly-1", COMP_APPLES_AND_ORANGES},
+        VersionExpectedResult{"1.0.0-nightly-1", "1.0.0", COMP_APPLES_AND_ORANGES},
+        VersionExpectedResult{"1.0.0-nightly-1", "1.0.0-nightly-2", COMP_LARGER},
+    }
+
+    for _, toCompare := range toCompareList {
+        // compile the lhs
+        lhs, err := ParseVersion(toCompare.lhs)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        rhs, err := ParseVersion(toCompare.rhs)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual := lhs.Compare(&rhs)
+
+        // what happened?
+        if actual != toCompare.expected {
+            t.Errorf("lhs: %s; rhs: %s; expected: %d; actual: %d", toCompare.lhs, toCompare.rhs, toCompare.expected, actual)
+            return
+        }
+    }
+}
+
+func TestCanRegisterStabilityLevels(t *testing.T) {
+    // put our stability levels in order
+    StabilityMode = STABILITY_ORDERED
+    defer func() { StabilityMode = STABILITY_STRICT }()
+
+    // what result do we expect?
+    expected := COMP_LARGER
+
+    // perform the test
+    RegisterStability("Nightly", 5)
+    defer delete(StabilityOrder, "nightly")
+
+    lhs, err := ParseVersion("1.0.0-nightly-20141013")
+    if err != nil {
+        t.Error(err)
+        return
+    }
+    rhs, err := ParseVersion("1.0.0-dev-1")
+    if err != nil {
+        t.Error(err)
+        return
+    }
+    actual := lhs.Compare(&rhs)
+
+    // what happened?
+    if actual != expected {
+        t.Errorf("expected: %d; actual: %d", expected, actual)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Match versions using StabilityOrder
+//
+// ------------------------------------------------------------------------
+
+func TestCanMatchUsingStabilityOrder(t *testing.T) {
+    // put our stability levels in order
+    StabilityMode = STABILITY_ORDERED
+    defer func() { StabilityMode = STABILITY_STRICT }()
+
+    // what result do we expect?
+    expected := true
+
+    // our list of strings to compare
+    //
+    // LHS contains the operator
+    // RHS contains only a version number to compare against
+    //
+    // all of these pairs should match
+    var toMatch = [][2]string{
+        [2]string{"=1.0.0-alpha-1", "1.0.0-ALPHA-1"},
+        [2]string{"!=1.0.0-alpha-1", "1.0.0-beta-1"},
+        [2]string{">=1.0.0-alpha-1", "1.0.0-beta-1"},
+        [2]string{">=1.0.0-alpha-1", "1.0.0"},
+        [2]string{">=1.0.0-alpha-1", "1.2.0-dev-1"},
+        [2]string{">1.0.0-beta-3", "1.0.0-rc-1"},
+        [2]string{"<=1.0.0", "1.0.0-rc-1"},
+        [2]string{"<1.0.0", "1.0.0-alpha-1"},
+        [2]string{"<1.0.0-beta-1", "0.9.0"},
+        [2]string{"~1.0.0-beta-1", "1.0.0-rc-1"},
+        [2]string{"~1.0.0-beta-1", "1.5.0"},
+        [2]string{"^1.0.0-beta-1", "1.5.0-alpha-1"},
+        [2]string{"^0.0", "0.0.5-rc-1"},
+        [2]string{"1.x", "1.5.0-alpha-1"},
+        [2]string{">=1.0.0-beta-1", "1.0.0-rc.1"},
+        [2]string{"<1.0.0-rc-1", "1.0.0-alpha.3"},
+        [2]string{"<1.0.0-dev-1", "1.0.0-foo"},
+    }
+
+    for _, matchSet := range toMatch {
+        // perform the test
+        lhs, err := ParseExpression(matchSet[0])
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual, err := lhs.Matches(matchSet[1])
+
+        // was an error returned?
+        if err != nil {
+            t.Errorf("%s %s: %v", matchSet[0], matchSet[1], err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != expected {
+            t.Errorf("Expected %v, received %v", expected, actual)
+            return
+        }
+    }
+}
+
+func TestCannotMatchUsingStabilityOrder(t *testing.T) {
+    // put our stability levels in order
+    StabilityMode = STABILITY_ORDERED
+    defer func() { StabilityMode = STABILITY_STRICT }()
+
+    // our list of strings to compare
+    //
+    // LHS contains the operator
+    // RHS contains only a version number to compare against
+    //
+    // all of these pairs should not match
+    var toMatch = []ExpectedError{
+        ExpectedError{"=1.0.0-alpha-1", "1.0.0-beta-1", ErrDifferentStabilityLevels},
+        ExpectedError{"=1.0.0-alpha-1", "1.0.0-alpha-2", ErrDifferentReleaseNumbers},
+        ExpectedError{"!=1.0.0-rc-1", "1.0.0-RC-1", ErrSameVersion},
+        ExpectedError{">=1.0.0-beta-1", "1.0.0-alpha-9", ErrStabilityLevelTooLow},
+        ExpectedError{">=1.0.0", "1.0.0-rc-1", ErrStabilityLevelTooLow},
+        ExpectedError{">=1.0.0-beta-2", "1.0.0-beta-1", ErrReleaseNumberTooSmall},
+        ExpectedError{">=1.0.0-beta-1", "0.9.0", ErrMajorVersionTooSmall},
+        ExpectedError{"<=1.0.0-beta-1", "1.0.0", ErrStabilityLevelTooHigh},
+        ExpectedError{"<1.0.0-beta-1", "1.0.0-BETA-1", ErrSameVersion},
+        ExpectedError{">1.0.0-beta-1", "1.0.0-beta-1", ErrSameVersion},
+        ExpectedError{"~1.0.0-beta-1", "2.0.0", ErrDifferentMajorVersions},
+        ExpectedError{"~1.0.0-beta-1", "1.0.0-alpha-1", ErrStabilityLevelTooLow},
+        ExpectedError{"^1.2.0", "1.2.0-rc-1", ErrStabilityLevelTooLow},
+        ExpectedError{"^0.0", "0.1.0-rc-1", ErrDifferentMinorVersions},
+        ExpectedError{">=1.0.0-alpha-1", "1.0.0-nightly-1", ErrDifferentStabilityLevels},
+        ExpectedError{"1.x", "2.0.0-alpha-1", ErrMajorVersionTooLarge},
+        ExpectedError{">=1.0.0-rc-1", "1.0.0-alpha.1", ErrStabilityLevelTooLow},
+        ExpectedError{">=1.0.0-rc.1", "1.0.0-foo", ErrStabilityLevelTooLow},
+        ExpectedError{">=1.0.0-rc.1", "1.0.0-alpha.beta", ErrStabilityLevelTooLow},
+    }
+
+    for _, matchSet := range toMatch {
+        // perform the test
+        lhs, err := ParseExpression(matchSet.lhs)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual, err := lhs.Matches(matchSet.rhs)
+
+        // was an error returned?
+        if !errors.Is(err, matchSet.err) {
+            t.Errorf("%s %s: expected %v, received %v", matchSet.lhs, matchSet.rhs, matchSet.err, err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != false {
+            t.Errorf("Expected %v, received %v", false, actual)
+            return
+        }
+    }
+}
+
+func TestCannotMatchVersionsAgainstReferencesUsingStabilityOrder(t *testing.T) {
+    // put our stability levels in order
+    StabilityMode = STABILITY_ORDERED
+    defer func() { StabilityMode = STABILITY_STRICT }()
+
+    lhs, err := ParseExpression("@main")
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    for _, version := range []string{"1.0.0", "1.0.0-nightly-1"} {
+        rhs, err := ParseVersion(version)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // perform the test
+        actual, err := lhs.MatchesVersion(&rhs)
+
+        // was an error returned?
+        if !errors.Is(err, ErrNotAReference) {
+            t.Errorf("%s: expected ErrNotAReference, received %v", version, err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != false {
+            t.Errorf("Expected %v, received %v", false, actual)
+            return
+        }
+    }
+}
+
+func TestStrictStabilityModeIgnoresCase(t *testing.T) {
+    // what result do we expect?
+    expected := true
+
+    // our list of strings to compare
+    //
+    // LHS contains the operator
+    // RHS contains only a version number to compare against
+    //
+    // all of these pairs should match
+    var toMatch = [][2]string{
+        [2]string{"=1.0.0-alpha-1", "1.0.0-ALPHA-1"},
+        [2]string{">=1.0.0-Alpha-1", "1.0.0-alpha-2"},
+        [2]string{"<=1.0.0-BETA-3", "1.0.0-beta-2"},
+        [2]string{"~1.0.0-rc-1", "1.0.0-RC-2"},
+    }
+
+    for _, matchSet := range toMatch {
+        // perform the test
+        lhs, err := ParseExpression(matchSet[0])
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual, err := lhs.Matches(matchSet[1])
+
+        // was an error returned?
+        if err != nil {
+            t.Errorf("%s %s: %v", matchSet[0], matchSet[1], err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != expected {
+            t.Errorf("Expected %v, received %v", expected, actual)
+            return
+        }
+    }
+}
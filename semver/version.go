@@ -8,6 +8,10 @@ import (
 //
 //     X.Y.Z-<stability>-R
 //
+// or, for Semantic Versioning 2.0.0 version strings:
+//
+//     X.Y.Z-<pre.release>+<build.metadata>
+//
 // where:
 //
 //     SemVersion.Major holds X
@@ -15,12 +19,16 @@ import (
 //     SemVersion.PatchLevel holds Z
 //     SemVersion.Stability holds <stability> (blank == 'stable')
 //     SemVersion.Release holds the unstable release number
+//     SemVersion.PreRelease holds the dot-separated pre-release identifiers
+//     SemVersion.Build holds the dot-separated build metadata
 type SemVersion struct {
     Major      int    // X
     Minor      int    // Y
     PatchLevel int    // Z
     Stability  string // stability
     Release    int    // R
+    PreRelease string // pre.release
+    Build      string // build.metadata
 }
 
 // returned by SemVersion.Compare when 'rhs' is smaller
@@ -86,9 +94,17 @@ func (lhs *SemVersion) CompareString(version string) (int, error) {
 // compares two versions, and returns a COMP_* constant to tell you whether
 // the right hand side is larger, smaller, or the same as the left hand
 // side
+//
+// versions with different stability levels are COMP_APPLES_AND_ORANGES,
+// unless StabilityMode is STABILITY_ORDERED
 func (lhs *SemVersion) Compare(rhs *SemVersion) int {
+    // are we putting stability levels in order?
+    if StabilityMode == STABILITY_ORDERED {
+        return lhs.compareWithStabilityOrder(rhs)
+    }
+
     // are both sides comparable at all?
-    if lhs.Stability != rhs.Stability {
+    if !sameStability(lhs.Stability, rhs.Stability) {
         return COMP_APPLES_AND_ORANGES
     }
 
@@ -128,11 +144,13 @@ func (lhs *SemVersion) compareStable(rhs *SemVersion) int {
         return COMP_SMALLER
     }
 
-    return COMP_EQUAL
+    // SemVer 2.0.0 pre-releases have a lower precedence than the
+    // version they are a pre-release of
+    return lhs.comparePreRelease(rhs)
 }
 
 func (lhs *SemVersion) compareUnstable(rhs *SemVersion) int {
-    if lhs.Stability == "" || strings.ToLower(rhs.Stability) != strings.ToLower(rhs.Stability) {
+    if lhs.Stability == "" || !sameStability(lhs.Stability, rhs.Stability) {
         return COMP_APPLES_AND_ORANGES
     }
 
@@ -156,5 +174,115 @@ func (lhs *SemVersion) compareUnstable(rhs *SemVersion) int {
         return COMP_SMALLER
     }
 
+    return lhs.comparePreRelease(rhs)
+}
+
+func (lhs *SemVersion) comparePreRelease(rhs *SemVersion) int {
+    switch comparePreReleases(lhs.PreRelease, rhs.PreRelease) {
+    case -1:
+        return COMP_LARGER
+    case 1:
+        return COMP_SMALLER
+    }
+
+    // build metadata is ignored when determining precedence
     return COMP_EQUAL
 }
+
+// comparePreReleases compares two lists of dot-separated pre-release
+// identifiers, using the precedence rules from SemVer 2.0.0
+//
+// returns -1 if 'lhs' has lower precedence than 'rhs', 1 if it has higher
+// precedence, and 0 if both have the same precedence
+func comparePreReleases(lhs string, rhs string) int {
+    if lhs == rhs {
+        return 0
+    }
+
+    // a version without any pre-release identifiers has a higher
+    // precedence than one that has some
+    if lhs == "" {
+        return 1
+    }
+    if rhs == "" {
+        return -1
+    }
+
+    for {
+        var lhsId, rhsId string
+        lhsId, lhs = nextIdentifier(lhs)
+        rhsId, rhs = nextIdentifier(rhs)
+
+        result := compareIdentifiers(lhsId, rhsId)
+        if result != 0 {
+            return result
+        }
+
+        // a larger set of identifiers has a higher precedence, when
+        // all of the preceding identifiers are equal
+        if lhs == "" && rhs == "" {
+            return 0
+        }
+        if lhs == "" {
+            return -1
+        }
+        if rhs == "" {
+            return 1
+        }
+    }
+}
+
+// nextIdentifier splits the first identifier off a dot-separated list
+func nextIdentifier(ids string) (string, string) {
+    i := strings.IndexByte(ids, '.')
+    if i < 0 {
+        return ids, ""
+    }
+
+    return ids[:i], ids[i+1:]
+}
+
+// compareIdentifiers compares a single pair of pre-release identifiers
+//
+// numeric identifiers are compared numerically, and always have a lower
+// precedence than alphanumeric identifiers, which are compared in ASCII
+// sort order
+func compareIdentifiers(lhs string, rhs string) int {
+    lhsNumeric := isNumericIdentifier(lhs)
+    rhsNumeric := isNumericIdentifier(rhs)
+
+    switch {
+    case lhsNumeric && !rhsNumeric:
+        return -1
+    case !lhsNumeric && rhsNumeric:
+        return 1
+    case lhsNumeric && rhsNumeric:
+        // compare without converting, so that we never overflow;
+        // the longer number is the larger, once leading zeroes
+        // are ignored
+        lhs = strings.TrimLeft(lhs, "0")
+        rhs = strings.TrimLeft(rhs, "0")
+        if len(lhs) != len(rhs) {
+            if len(lhs) < len(rhs) {
+                return -1
+            }
+            return 1
+        }
+    }
+
+    return strings.Compare(lhs, rhs)
+}
+
+// isNumericIdentifier returns true if 'id' only contains digits
+func isNumericIdentifier(id string) bool {
+    if id == "" {
+        return false
+    }
+    for i := 0; i < len(id); i++ {
+        if id[i] < '0' || id[i] > '9' {
+            return false
+        }
+    }
+
+    return true
+}
@@ -37,6 +37,8 @@ func TestCanCompareTwoVersions(t *testing.T) {
         VersionExpectedResult{"1.1.0", "1.11.0", COMP_LARGER},
         VersionExpectedResult{"0.0.1", "0.0.2", COMP_LARGER},
         VersionExpectedResult{"0.0.1-alpha-1", "0.0.1-alpha-2", COMP_LARGER},
+        VersionExpectedResult{"0.0.1-ALPHA-1", "0.0.1-alpha-2", COMP_LARGER},
+        VersionExpectedResult{"0.0.1-alpha-1", "0.0.1-Alpha-2", COMP_LARGER},
 
         // things that should be smaller on the RHS
         VersionExpectedResult{"0.0.2", "0.0.1", COMP_SMALLER},
@@ -45,6 +47,19 @@ func TestCanCompareTwoVersions(t *testing.T) {
         VersionExpectedResult{"11.0.0", "1.1.0", COMP_SMALLER},
         VersionExpectedResult{"0.0.1-alpha-2", "0.0.1-alpha-1", COMP_SMALLER},
 
+        // SemVer 2.0.0 pre-releases and build metadata
+        VersionExpectedResult{"1.0.0-alpha", "1.0.0-alpha.1", COMP_LARGER},
+        VersionExpectedResult{"1.0.0-alpha.1", "1.0.0-alpha.beta", COMP_LARGER},
+        VersionExpectedResult{"1.0.0-alpha.beta", "1.0.0-beta", COMP_LARGER},
+        VersionExpectedResult{"1.0.0-beta", "1.0.0-beta.2", COMP_LARGER},
+        VersionExpectedResult{"1.0.0-beta.2", "1.0.0-beta.11", COMP_LARGER},
+        VersionExpectedResult{"1.0.0-beta.11", "1.0.0-rc.1", COMP_LARGER},
+        VersionExpectedResult{"1.0.0-rc.1", "1.0.0", COMP_LARGER},
+        VersionExpectedResult{"1.0.0", "1.0.0-rc.1", COMP_SMALLER},
+        VersionExpectedResult{"1.0.0-beta.11", "1.0.0-beta.2", COMP_SMALLER},
+        VersionExpectedResult{"1.0.0-rc.1+build.1", "1.0.0-rc.1+build.2", COMP_EQUAL},
+        VersionExpectedResult{"1.0.0", "1.0.0+20130313144700", COMP_EQUAL},
+
         // things that make no sense to compare
         VersionExpectedResult{"0.0.1-alpha-1", "0.0.2-alpha-2", COMP_APPLES_AND_ORANGES},
         VersionExpectedResult{"0.0.1-alpha-1", "0.0.2-beta-1", COMP_APPLES_AND_ORANGES},
@@ -0,0 +1,110 @@
+package semver
+
+import (
+    "sort"
+)
+
+// Versions holds a list of versions, such as the tags in a repository
+//
+// create one by calling:
+//
+//     versions, err = semver.ParseVersions([]string{"1.2.3", "1.3.0", ...})
+//
+// Versions implements sort.Interface, using Cmp() to put the versions in
+// order, oldest first.
+type Versions []SemVersion
+
+// ParseVersions takes a list of version strings and turns them into a
+// Versions list.
+//
+// returns the *ParseError for the first version string that cannot be
+// parsed
+func ParseVersions(raw []string) (Versions, error) {
+    versions := make(Versions, 0, len(raw))
+    for _, version := range raw {
+        parsed, err := ParseVersion(version)
+        if err != nil {
+            return nil, err
+        }
+        versions = append(versions, parsed)
+    }
+
+    return versions, nil
+}
+
+// Len is the number of versions in the list
+func (v Versions) Len() int {
+    return len(v)
+}
+
+// Less returns true if the version at v[i] is older than the version at
+// v[j]
+func (v Versions) Less(i, j int) bool {
+    return Cmp(v[i], v[j]) < 0
+}
+
+// Swap swaps the versions at v[i] and v[j]
+func (v Versions) Swap(i, j int) {
+    v[i], v[j] = v[j], v[i]
+}
+
+// Sort puts the list of versions in order, oldest first
+func (v Versions) Sort() {
+    sort.Stable(v)
+}
+
+// Dedupe returns a sorted copy of the list, with only the first of any
+// equivalent versions (e.g. '1.3' and '1.3.0') kept
+//
+// the original list is left untouched
+func (v Versions) Dedupe() Versions {
+    sorted := make(Versions, len(v))
+    copy(sorted, v)
+    sorted.Sort()
+
+    retval := sorted[:0]
+    for i := range sorted {
+        if i == 0 || Cmp(retval[len(retval)-1], sorted[i]) != 0 {
+            retval = append(retval, sorted[i])
+        }
+    }
+
+    return retval
+}
+
+// Filter returns the versions that match 'exp', in the same order that
+// they appear in the list
+func (v Versions) Filter(exp VersionExpression) Versions {
+    var retval Versions
+    for i := range v {
+        if ok, _ := exp.MatchesVersion(&v[i]); ok {
+            retval = append(retval, v[i])
+        }
+    }
+
+    return retval
+}
+
+// MaxSatisfying returns the newest version in the list that matches 'exp'
+//
+// returns 'false' if none of the versions match
+func (v Versions) MaxSatisfying(exp VersionExpression) (SemVersion, bool) {
+    matches := v.Filter(exp)
+    if len(matches) == 0 {
+        return SemVersion{}, false
+    }
+
+    return Max(matches[0], matches[1:]...), true
+}
+
+// MinSatisfying returns the oldest version in the list that matches 'exp'
+//
+// returns 'false' if none of the versions match
+func (v Versions) MinSatisfying(exp VersionExpression) (SemVersion, bool) {
+    matches := v.Filter(exp)
+    if len(matches) == 0 {
+        return SemVersion{}, false
+    }
+
+    return Min(matches[0], matches[1:]...), true
+}
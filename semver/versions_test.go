@@ -0,0 +1,212 @@
+package semver
+
+import (
+    "errors"
+    "sort"
+    "testing"
+)
+
+// a list of tags, like the ones that we find in a real repository
+var exampleTags = []string{
+    "1.3.0",
+    "1.2.9",
+    "2.0.0-rc.1",
+    "1.3",
+    "1.3.5",
+    "1.4.0-beta-1",
+    "1.10.0",
+    "2.0.0",
+    "0.9.0",
+}
+
+// ========================================================================
+//
+// Tests for ParseVersions()
+//
+// ------------------------------------------------------------------------
+
+func TestCanParseVersions(t *testing.T) {
+    // perform the test
+    actual, err := ParseVersions(exampleTags)
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if len(actual) != len(exampleTags) {
+        t.Errorf("Expected %d versions, received %d", len(exampleTags), len(actual))
+        return
+    }
+}
+
+func TestParseVersionsReportsWhichVersionFailed(t *testing.T) {
+    // what result do we expect?
+    expected := ParseError{"v1.3.0", 0, expectMajor, nil}
+
+    // perform the test
+    _, err := ParseVersions([]string{"1.2.0", "v1.3.0", "1.4.0"})
+
+    // did we get back what we expected?
+    var actual *ParseError
+    if !errors.As(err, &actual) {
+        t.Errorf("Expected a ParseError, received %v", err)
+        return
+    }
+    if *actual != expected {
+        t.Errorf("Expected %+v, received %+v", expected, *actual)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for sorting and deduping
+//
+// ------------------------------------------------------------------------
+
+func TestCanSortVersions(t *testing.T) {
+    // what result do we expect?
+    expected, _ := ParseVersions([]string{
+        "0.9.0",
+        "1.2.9",
+        "1.3.0",
+        "1.3",
+        "1.3.5",
+        "1.4.0-beta-1",
+        "1.10.0",
+        "2.0.0-rc.1",
+        "2.0.0",
+    })
+
+    // perform the test
+    actual, _ := ParseVersions(exampleTags)
+    sort.Sort(actual)
+
+    // did we get back what we expected?
+    for i := range expected {
+        if actual[i] != expected[i] {
+            t.Errorf("Expected %+v at position %d, received %+v", expected[i], i, actual[i])
+            return
+        }
+    }
+}
+
+func TestCanDedupeVersions(t *testing.T) {
+    // what result do we expect?
+    expected, _ := ParseVersions([]string{
+        "0.9.0",
+        "1.2.9",
+        "1.3.0",
+        "1.3.5",
+        "1.4.0-beta-1",
+        "1.10.0",
+        "2.0.0-rc.1",
+        "2.0.0",
+    })
+
+    // perform the test
+    versions, _ := ParseVersions(append(exampleTags, "1.3.5+build.7"))
+    actual := versions.Dedupe()
+
+    // did we get back what we expected?
+    if len(actual) != len(expected) {
+        t.Errorf("Expected %v, received %v", expected, actual)
+        return
+    }
+    for i := range expected {
+        if actual[i] != expected[i] {
+            t.Errorf("Expected %+v at position %d, received %+v", expected[i], i, actual[i])
+            return
+        }
+    }
+
+    // the original list must not have changed
+    if versions[0] != (SemVersion{Major: 1, Minor: 3}) {
+        t.Errorf("Dedupe() changed the original list: %v", versions)
+        return
+    }
+}
+
+// ========================================================================
+//
+// Tests for Filter(), MaxSatisfying() and MinSatisfying()
+//
+// ------------------------------------------------------------------------
+
+func TestCanFilterVersions(t *testing.T) {
+    // what result do we expect?
+    expected, _ := ParseVersions([]string{"1.3.0", "1.3", "1.3.5", "1.10.0"})
+
+    // perform the test
+    versions, _ := ParseVersions(exampleTags)
+    exp, _ := ParseExpression("~1.3")
+    actual := versions.Filter(exp)
+
+    // did we get back what we expected?
+    if len(actual) != len(expected) {
+        t.Errorf("Expected %v, received %v", expected, actual)
+        return
+    }
+    for i := range expected {
+        if actual[i] != expected[i] {
+            t.Errorf("Expected %+v at position %d, received %+v", expected[i], i, actual[i])
+            return
+        }
+    }
+}
+
+func TestCanFindMaxAndMinSatisfying(t *testing.T) {
+    // our list of expressions, and the versions that we expect
+    var toFind = []struct {
+        exp string
+        max string
+        min string
+    }{
+        {"~1.3", "1.10.0", "1.3.0"},
+        {"<2.0", "2.0.0-rc.1", "0.9.0"},
+        {"^1.2.0", "1.10.0", "1.2.9"},
+        {"1.3.x", "1.3.5", "1.3.0"},
+        {">=1.4.0-beta-1", "1.4.0-beta-1", "1.4.0-beta-1"},
+    }
+
+    versions, _ := ParseVersions(exampleTags)
+    for _, set := range toFind {
+        exp, err := ParseExpression(set.exp)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        expectedMax, _ := ParseVersion(set.max)
+        expectedMin, _ := ParseVersion(set.min)
+
+        // perform the test
+        actualMax, okMax := versions.MaxSatisfying(exp)
+        actualMin, okMin := versions.MinSatisfying(exp)
+
+        // did we get back what we expected?
+        if !okMax || actualMax != expectedMax {
+            t.Errorf("%s: expected max %+v, received %+v", set.exp, expectedMax, actualMax)
+            return
+        }
+        if !okMin || actualMin != expectedMin {
+            t.Errorf("%s: expected min %+v, received %+v", set.exp, expectedMin, actualMin)
+            return
+        }
+    }
+}
+
+func TestMaxSatisfyingReturnsFalseWhenNothingMatches(t *testing.T) {
+    // perform the test
+    versions, _ := ParseVersions(exampleTags)
+    exp, _ := ParseExpression(">=3.0")
+    _, ok := versions.MaxSatisfying(exp)
+
+    // did we get back what we expected?
+    if ok {
+        t.Errorf("Expected false, received %v", ok)
+        return
+    }
+}
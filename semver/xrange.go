@@ -0,0 +1,250 @@
+package semver
+
+// value of VersionExpression.Operator when the expression requires a
+// version that is greater than or equal to VersionExpression.Version,
+// AND that also matches VersionExpression.UpperOperator and
+// VersionExpression.Upper
+//
+// created by parsing hyphen ranges (e.g. '1.2.3 - 2.3.4') and X-ranges
+// (e.g. '1.2.x')
+const OP_RANGE Operator = 9
+
+// what a ParseError can tell you we were expecting in a range
+const (
+    expectWildcard    = "'x', 'X' or '*'"
+    expectAfterRange  = "' - ' or end of expression"
+    expectAfterHyphen = "' ' after '-'"
+    expectEndOfRange  = "end of version string"
+)
+
+// the pre-release that sorts before all others
+const lowestPreRelease = "0"
+
+// partialVersion holds a version that may have missing or wildcard parts,
+// such as '1.2', '1.x' or '*'
+type partialVersion struct {
+    version SemVersion
+    parts   int // how many of X, Y and Z were given as numbers
+}
+
+// parseRangeWithOffset parses the hyphen range or X-range that starts at
+// raw[offset]
+//
+// returns 'false' if raw[offset] does not start a range at all, so that
+// the caller can report a more useful error
+func parseRangeWithOffset(raw string, offset int) (VersionExpression, bool, error) {
+    // where does the first version end?
+    end := offset
+    for end < len(raw) && !isSpace(raw[end]) {
+        end++
+    }
+
+    // ranges always start with a number or a wildcard
+    if end == offset || (!isDigit(raw[offset]) && !isWildcardAt(raw, offset, end)) {
+        return VersionExpression{}, false, nil
+    }
+
+    lower, err := parsePartialVersion(raw, offset, end)
+    if err != nil {
+        return VersionExpression{}, true, err
+    }
+
+    // is this an X-range on its own?
+    if end == len(raw) {
+        // a version without an operator is not a range
+        if !hasWildcard(raw[offset:end]) {
+            return VersionExpression{}, false, nil
+        }
+        return desugarRange(lower, lower), true, nil
+    }
+
+    // or is it a hyphen range?
+    pos := skipSpaces(raw, end)
+    if pos >= len(raw) || raw[pos] != '-' {
+        return VersionExpression{}, true, newParseError(raw, end, expectAfterRange)
+    }
+    if pos+1 >= len(raw) || !isSpace(raw[pos+1]) {
+        return VersionExpression{}, true, newParseError(raw, pos+1, expectAfterHyphen)
+    }
+
+    start := skipSpaces(raw, pos+1)
+    upper, err := parsePartialVersion(raw, start, len(raw))
+    if err != nil {
+        return VersionExpression{}, true, err
+    }
+
+    return desugarRange(lower, upper), true, nil
+}
+
+// desugarRange turns the two ends of a range into an expression that our
+// existing '>=', '<=' and '<' matchers can work with
+//
+// incomplete versions are treated the way that npm and Composer treat
+// them: '1.2' as a lower bound means '>=1.2.0', and as an upper bound
+// means '<1.3.0-0'
+//
+// the '-0' is the lowest possible pre-release, so that pre-releases of
+// 1.3.0 do not sneak in underneath the upper bound
+func desugarRange(lower partialVersion, upper partialVersion) VersionExpression {
+    exp := VersionExpression{
+        Operator: OP_GT_EQUALS,
+        Version:  lower.version,
+    }
+
+    switch upper.parts {
+    case 0:
+        // '*' has no upper bound at all
+        return exp
+    case 1:
+        exp.Upper = SemVersion{Major: upper.version.Major + 1, PreRelease: lowestPreRelease}
+        exp.UpperOperator = OP_LT
+    case 2:
+        exp.Upper = SemVersion{Major: upper.version.Major, Minor: upper.version.Minor + 1, PreRelease: lowestPreRelease}
+        exp.UpperOperator = OP_LT
+    default:
+        exp.Upper = upper.version
+        exp.UpperOperator = OP_LT_EQUALS
+    }
+    exp.Operator = OP_RANGE
+
+    return exp
+}
+
+// parsePartialVersion parses the version in raw[offset:end], which may
+// have missing or wildcard parts
+func parsePartialVersion(raw string, offset int, end int) (partialVersion, error) {
+    var numbers [3]int
+    var parts int
+
+    expected := [3]string{expectMajor, expectMinor, expectPatchLevel}
+    pos := offset
+    for parts < 3 {
+        // the rest of the version must be wildcards
+        if isWildcardAt(raw, pos, end) {
+            return scanWildcards(raw, pos, end, numbers, parts)
+        }
+
+        number, next, ok := scanNumber(raw[:end], pos, false)
+        if !ok {
+            return partialVersion{}, newParseError(raw, next, expected[parts])
+        }
+        if numbers[parts], ok = number.toInt(raw); !ok {
+            return partialVersion{}, newOverflowError(raw, number.start, expected[parts])
+        }
+        parts++
+        pos = next
+
+        // X.Y and X.Y.Z may be followed by the rest of a version string
+        if pos < end && raw[pos] != '.' && parts >= 2 {
+            break
+        }
+        if pos == end || parts == 3 {
+            break
+        }
+        if raw[pos] != '.' {
+            return partialVersion{}, newParseError(raw, pos, expectDot)
+        }
+        pos++
+    }
+
+    // is there anything left over?
+    if pos < end || parts == 3 {
+        version, err := parseVersionWithOffset(raw[:end], offset)
+        if err != nil {
+            return partialVersion{}, err
+        }
+        return partialVersion{version, 3}, nil
+    }
+
+    return partialVersion{SemVersion{Major: numbers[0], Minor: numbers[1]}, parts}, nil
+}
+
+// scanWildcards skips over the wildcards that start at raw[pos], up to
+// and including the patch level
+func scanWildcards(raw string, pos int, end int, numbers [3]int, parts int) (partialVersion, error) {
+    for i := parts; i < 3; i++ {
+        if !isWildcardAt(raw, pos, end) {
+            return partialVersion{}, newParseError(raw, pos, expectWildcard)
+        }
+        pos++
+        if pos == end {
+            return partialVersion{SemVersion{Major: numbers[0], Minor: numbers[1]}, parts}, nil
+        }
+        if raw[pos] != '.' || i == 2 {
+            return partialVersion{}, newParseError(raw, pos, expectEndOfRange)
+        }
+        pos++
+    }
+
+    return partialVersion{}, newParseError(raw, pos, expectWildcard)
+}
+
+// isWildcardAt returns true if there is a wildcard part at raw[pos]
+func isWildcardAt(raw string, pos int, end int) bool {
+    if pos >= end || !isWildcard(raw[pos]) {
+        return false
+    }
+
+    return pos+1 == end || raw[pos+1] == '.'
+}
+
+// isDigit returns true if 'c' is a decimal digit
+func isDigit(c byte) bool {
+    return c >= '0' && c <= '9'
+}
+
+// isWildcard returns true if 'c' can stand in for any number
+func isWildcard(c byte) bool {
+    return c == 'x' || c == 'X' || c == '*'
+}
+
+// hasWildcard returns true if 'version' contains a wildcard part
+func hasWildcard(version string) bool {
+    for i := 0; i < len(version); i++ {
+        if isWildcardAt(version, i, len(version)) && (i == 0 || version[i-1] == '.') {
+            return true
+        }
+    }
+
+    return false
+}
+
+// bounds returns the two expressions that a range has been desugared
+// into
+func (lhs *VersionExpression) bounds() (VersionExpression, VersionExpression) {
+    lower := VersionExpression{Operator: OP_GT_EQUALS, Version: lhs.Version}
+    upper := VersionExpression{Operator: lhs.UpperOperator, Version: lhs.Upper}
+
+    return lower, upper
+}
+
+func (lhs *VersionExpression) matchesRange(rhs *SemVersion) (bool, error) {
+    lower, upper := lhs.bounds()
+    if ok, err := lower.MatchesVersion(rhs); !ok {
+        return false, err
+    }
+
+    // '1.x' and '1.2.x' exclude everything from the next X.Y.0 upwards,
+    // including all of its pre-releases and unstable releases
+    if lhs.UpperOperator == OP_LT && lhs.Upper.PreRelease == lowestPreRelease {
+        return lhs.matchesBelowUpperBound(rhs)
+    }
+
+    return upper.MatchesVersion(rhs)
+}
+
+// matchesBelowUpperBound checks that 'rhs' comes before the X.Y.0 in
+// our upper bound
+func (lhs *VersionExpression) matchesBelowUpperBound(rhs *SemVersion) (bool, error) {
+    if rhs.Major < lhs.Upper.Major {
+        return true, nil
+    }
+    if rhs.Major > lhs.Upper.Major || lhs.Upper.Minor == 0 {
+        return false, ErrMajorVersionTooLarge
+    }
+    if rhs.Minor < lhs.Upper.Minor {
+        return true, nil
+    }
+
+    return false, ErrMinorVersionTooLarge
+}
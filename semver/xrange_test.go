@@ -0,0 +1,285 @@
+package semver
+
+import (
+    "errors"
+    "testing"
+)
+
+// ========================================================================
+//
+// Tests for Parse() with hyphen ranges and X-ranges
+//
+// ------------------------------------------------------------------------
+
+func TestCanParseXRanges(t *testing.T) {
+    // our list of expressions to parse, and what we expect to get back
+    var toParse = []struct {
+        raw      string
+        expected VersionExpression
+    }{
+        {"*", VersionExpression{Operator: OP_GT_EQUALS}},
+        {"x", VersionExpression{Operator: OP_GT_EQUALS}},
+        {"X.x.*", VersionExpression{Operator: OP_GT_EQUALS}},
+        {"1.x", VersionExpression{
+            Operator:      OP_RANGE,
+            Version:       SemVersion{Major: 1},
+            UpperOperator: OP_LT,
+            Upper:         SemVersion{Major: 2, PreRelease: "0"},
+        }},
+        {"1.x.x", VersionExpression{
+            Operator:      OP_RANGE,
+            Version:       SemVersion{Major: 1},
+            UpperOperator: OP_LT,
+            Upper:         SemVersion{Major: 2, PreRelease: "0"},
+        }},
+        {"1.2.*", VersionExpression{
+            Operator:      OP_RANGE,
+            Version:       SemVersion{Major: 1, Minor: 2},
+            UpperOperator: OP_LT,
+            Upper:         SemVersion{Major: 1, Minor: 3, PreRelease: "0"},
+        }},
+        {"1.2.X", VersionExpression{
+            Operator:      OP_RANGE,
+            Version:       SemVersion{Major: 1, Minor: 2},
+            UpperOperator: OP_LT,
+            Upper:         SemVersion{Major: 1, Minor: 3, PreRelease: "0"},
+        }},
+    }
+
+    for _, set := range toParse {
+        // perform the test
+        actual, err := ParseExpression(set.raw)
+
+        // was an error returned?
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != set.expected {
+            t.Errorf("%q: expected %+v, received %+v", set.raw, set.expected, actual)
+            return
+        }
+    }
+}
+
+func TestCanParseHyphenRanges(t *testing.T) {
+    // our list of expressions to parse, and what we expect to get back
+    var toParse = []struct {
+        raw      string
+        expected VersionExpression
+    }{
+        {"1.2.3 - 2.3.4", VersionExpression{
+            Operator:      OP_RANGE,
+            Version:       SemVersion{Major: 1, Minor: 2, PatchLevel: 3},
+            UpperOperator: OP_LT_EQUALS,
+            Upper:         SemVersion{Major: 2, Minor: 3, PatchLevel: 4},
+        }},
+        {"1.2.3   -   2.3.4", VersionExpression{
+            Operator:      OP_RANGE,
+            Version:       SemVersion{Major: 1, Minor: 2, PatchLevel: 3},
+            UpperOperator: OP_LT_EQUALS,
+            Upper:         SemVersion{Major: 2, Minor: 3, PatchLevel: 4},
+        }},
+        {"1.2 - 2.3", VersionExpression{
+            Operator:      OP_RANGE,
+            Version:       SemVersion{Major: 1, Minor: 2},
+            UpperOperator: OP_LT,
+            Upper:         SemVersion{Major: 2, Minor: 4, PreRelease: "0"},
+        }},
+        {"1 - 2", VersionExpression{
+            Operator:      OP_RANGE,
+            Version:       SemVersion{Major: 1},
+            UpperOperator: OP_LT,
+            Upper:         SemVersion{Major: 3, PreRelease: "0"},
+        }},
+        {"1.2.3-beta.2 - 2.3.4-rc.1", VersionExpression{
+            Operator:      OP_RANGE,
+            Version:       SemVersion{Major: 1, Minor: 2, PatchLevel: 3, PreRelease: "beta.2"},
+            UpperOperator: OP_LT_EQUALS,
+            Upper:         SemVersion{Major: 2, Minor: 3, PatchLevel: 4, PreRelease: "rc.1"},
+        }},
+        {"1.2.3 - *", VersionExpression{
+            Operator: OP_GT_EQUALS,
+            Version:  SemVersion{Major: 1, Minor: 2, PatchLevel: 3},
+        }},
+    }
+
+    for _, set := range toParse {
+        // perform the test
+        actual, err := ParseExpression(set.raw)
+
+        // was an error returned?
+        if err != nil {
+            t.Error(err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != set.expected {
+            t.Errorf("%q: expected %+v, received %+v", set.raw, set.expected, actual)
+            return
+        }
+    }
+}
+
+func TestParseRangeReportsOffsetOfError(t *testing.T) {
+    // our list of expressions to parse, and where we expect parsing to
+    // fail
+    var toParse = []ParseError{
+        ParseError{"1.2", 0, expectOperator, nil},
+        ParseError{"1.2.3", 0, expectOperator, nil},
+        ParseError{"xyz", 0, expectOperator, nil},
+        ParseError{"1.x.3", 4, expectWildcard, nil},
+        ParseError{"1.2.x.3", 5, expectEndOfRange, nil},
+        ParseError{"1.y", 2, expectMinor, nil},
+        ParseError{"1.2.3 2.3.4", 5, expectAfterRange, nil},
+        ParseError{"1.2.3 -2.3.4", 7, expectAfterHyphen, nil},
+        ParseError{"1.2.3 - ", 8, expectMajor, nil},
+        ParseError{"1.2.3 - 2.3.4x", 13, "'-', '+' or end of version string", nil},
+    }
+
+    for _, expected := range toParse {
+        // perform the test
+        _, err := ParseExpression(expected.Input)
+
+        // did we get back what we expected?
+        var actual *ParseError
+        if !errors.As(err, &actual) {
+            t.Errorf("Expected a ParseError for %q, received %v", expected.Input, err)
+            return
+        }
+        if *actual != expected {
+            t.Errorf("Expected %+v, received %+v", expected, *actual)
+            return
+        }
+    }
+}
+
+func TestCanUseHyphenRangeInConstraint(t *testing.T) {
+    // what result do we expect?
+    expected := []int{2, 1}
+
+    // perform the test
+    actual, err := ParseConstraint("1.2.3 - 2.3.4, !=2.0.0 || 3.x")
+
+    // was an error returned?
+    if err != nil {
+        t.Error(err)
+        return
+    }
+
+    // did we get back what we expected?
+    if len(actual.Alternatives) != len(expected) {
+        t.Errorf("Expected %v alternatives, received %v", len(expected), actual.Alternatives)
+        return
+    }
+    for i, clauses := range actual.Alternatives {
+        if len(clauses) != expected[i] {
+            t.Errorf("Expected %d clauses in alternative %d, received %v", expected[i], i, clauses)
+            return
+        }
+    }
+    if actual.Alternatives[0][0].Operator != OP_RANGE {
+        t.Errorf("Expected a hyphen range, received %+v", actual.Alternatives[0][0])
+        return
+    }
+}
+
+// ========================================================================
+//
+// Compare two versions using hyphen ranges and X-ranges
+//
+// ------------------------------------------------------------------------
+
+func TestCanMatchUsingRanges(t *testing.T) {
+    // what result do we expect?
+    expected := true
+
+    // our list of strings to compare
+    //
+    // LHS contains the range
+    // RHS contains only a version number to compare against
+    //
+    // all of these pairs should match
+    var toMatch = [][2]string{
+        [2]string{"*", "0.0.1"},
+        [2]string{"*", "99.0.0"},
+        [2]string{"1.x", "1.0.0"},
+        [2]string{"1.x", "1.99.99"},
+        [2]string{"1.2.*", "1.2.0"},
+        [2]string{"1.2.*", "1.2.99"},
+        [2]string{"1.2.3 - 2.3.4", "1.2.3"},
+        [2]string{"1.2.3 - 2.3.4", "2.0"},
+        [2]string{"1.2.3 - 2.3.4", "2.3.4"},
+        [2]string{"1.2 - 2.3", "2.3.99"},
+        [2]string{"1.2.3-beta.2 - 2", "1.2.3-beta.3"},
+    }
+
+    for _, matchSet := range toMatch {
+        // perform the test
+        lhs, err := ParseExpression(matchSet[0])
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual, err := lhs.Matches(matchSet[1])
+
+        // was an error returned?
+        if err != nil {
+            t.Errorf("%s %s: %v", matchSet[0], matchSet[1], err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != expected {
+            t.Errorf("Expected %v, received %v", expected, actual)
+            return
+        }
+    }
+}
+
+func TestCannotMatchUsingRanges(t *testing.T) {
+    // our list of strings to compare
+    //
+    // LHS contains the range
+    // RHS contains only a version number to compare against
+    //
+    // all of these pairs should not match
+    var toMatch = []ExpectedError{
+        ExpectedError{"1.x", "0.9.9", ErrMajorVersionTooSmall},
+        ExpectedError{"1.x", "2.0.0", ErrMajorVersionTooLarge},
+        ExpectedError{"1.x", "2.0.0-rc.1", ErrMajorVersionTooLarge},
+        ExpectedError{"1.2.*", "1.1.9", ErrMinorVersionTooSmall},
+        ExpectedError{"1.2.*", "1.3.0", ErrMinorVersionTooLarge},
+        ExpectedError{"1.2.*", "1.3.0-alpha.1", ErrMinorVersionTooLarge},
+        ExpectedError{"1.2.3 - 2.3.4", "1.2.2", ErrPatchLevelTooSmall},
+        ExpectedError{"1.2.3 - 2.3.4", "2.3.5", ErrPatchLevelTooLarge},
+        ExpectedError{"1.2.3 - 2.3.4", "3.0.0", ErrMajorVersionTooLarge},
+        ExpectedError{"1.2 - 2.3", "2.4.0", ErrMinorVersionTooLarge},
+        ExpectedError{"1 - 2", "3.0.0", ErrMajorVersionTooLarge},
+    }
+
+    for _, matchSet := range toMatch {
+        // perform the test
+        lhs, err := ParseExpression(matchSet.lhs)
+        if err != nil {
+            t.Error(err)
+            return
+        }
+        actual, err := lhs.Matches(matchSet.rhs)
+
+        // was an error returned?
+        if !errors.Is(err, matchSet.err) {
+            t.Errorf("%s %s: expected %v, received %v", matchSet.lhs, matchSet.rhs, matchSet.err, err)
+            return
+        }
+
+        // did we get back what we expected?
+        if actual != false {
+            t.Errorf("Expected %v, received %v", false, actual)
+            return
+        }
+    }
+}